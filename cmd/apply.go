@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/productivityenthusiast/keygen-cli/internal/state"
+	"github.com/spf13/cobra"
+)
+
+const stateFileHelp = `The state file is YAML and declares the products, entitlements, policies
+and key customer licenses the account should have:
+
+  version: 1
+  products:
+    - name: App
+      url: https://example.com
+  entitlements:
+    - code: SSO
+      name: Single sign-on
+  policies:
+    - product: App
+      name: Pro
+      duration: 31536000
+      max_machines: 3
+      entitlements: [SSO]
+  licenses:
+    - key: ACME-0001
+      name: Acme Corp
+      policy: App/Pro
+      owner: ops@acme.example
+      expiry: 2027-01-01T00:00:00Z
+      metadata: {tier: gold}
+
+Fields are Keygen attributes in snake_case. Resources are matched by
+product name, entitlement code, product and policy name, and license key.
+policy takes "product/name", a policy name that is unique in the account,
+or an ID; owner takes an existing user's email or ID.
+
+Only what the file declares is managed: undeclared attributes and
+resources are left alone and nothing is deleted. Declared metadata replaces
+the resource's metadata as a whole. A declared entitlements
+list replaces the entitlements attached directly to the policy or license;
+a license needn't list the entitlements its policy grants.`
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Preview the changes apply would make",
+	Long: `Compare a desired-state file with the live account and show the changes
+'keygen apply' would make, without changing anything.
+
+` + stateFileHelp + `
+
+The plan is printed like a Terraform plan; with --format it is returned as
+structured output instead.
+
+Examples:
+  keygen plan -f state.yaml
+  keygen plan -f state.yaml --format json --query 'data.summary'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, plan, ok := loadPlan(cmd)
+		if !ok {
+			return
+		}
+		if planStructured(cmd) {
+			output.SuccessTable(planResult(plan), planHeaders, planRows(plan))
+			return
+		}
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		printPlan(plan, useColor(noColor))
+	},
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Bring the account in line with a desired-state file",
+	Long: `Compare a desired-state file with the live account, show the plan and
+make the changes: products first, then entitlements, policies and
+licenses, so each resource can reference those before it.
+
+` + stateFileHelp + `
+
+The plan must be approved by typing yes, or with --auto-approve in scripts.
+With --format the plan isn't shown and --auto-approve is required. apply
+stops at the first failed change and reports the changes already made;
+fix the problem and run it again to continue.
+
+Examples:
+  keygen apply -f state.yaml
+  keygen apply -f state.yaml --auto-approve
+  keygen apply -f state.yaml --auto-approve --format json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, plan, ok := loadPlan(cmd)
+		if !ok {
+			return
+		}
+		autoApprove, _ := cmd.Flags().GetBool("auto-approve")
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		structured := planStructured(cmd)
+		color := useColor(noColor)
+
+		if structured {
+			if len(plan.Changes) > 0 && !autoApprove {
+				output.ErrorDetail("apply needs --auto-approve with --format", applyPreview{planResult(plan), "use --auto-approve to apply these changes"})
+				os.Exit(1)
+			}
+		} else {
+			printPlan(plan, color)
+			if len(plan.Changes) == 0 {
+				return
+			}
+			if !autoApprove {
+				if !isTerminal(os.Stdin) {
+					exitError("no terminal to approve the plan; use --auto-approve")
+				}
+				fmt.Print("\nDo you want to perform these actions?\n  Only 'yes' will be accepted to approve.\n\n  Enter a value: ")
+				answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				if strings.TrimSpace(answer) != "yes" {
+					exitError("apply cancelled")
+				}
+				fmt.Println()
+			}
+		}
+
+		applied, err := applyPlan(client, plan, func(a appliedChange) {
+			if !structured && !quiet {
+				fmt.Println(paintAction(a.Action, color, fmt.Sprintf("%s: %s (%s)", planAddress(a.Kind, a.Address), pastTense(a.Action), a.ID)))
+			}
+		})
+		if err != nil {
+			if structured {
+				output.ErrorDetail(err.Error(), map[string]interface{}{"applied": applied})
+				os.Exit(1)
+			}
+			exitError(fmt.Sprintf("%s\n%d of %d changes were applied; fix the problem and run apply again", err, len(applied), len(plan.Changes)))
+		}
+
+		create, update := plan.Counts()
+		if structured {
+			output.SuccessTable(applyOutput{planResult(plan), applied}, planHeaders, planRows(plan))
+			return
+		}
+		if !quiet {
+			fmt.Printf("\nApply complete! Resources: %d added, %d changed.\n", create, update)
+		}
+	},
+}
+
+// loadPlan reads the -f state file and plans it against the live account.
+// Errors are reported; ok is false after one.
+func loadPlan(cmd *cobra.Command) (*api.Client, *state.Plan, bool) {
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		output.Error("-f/--file is required")
+		return nil, nil, false
+	}
+	declared, err := state.Load(path)
+	if err != nil {
+		output.Error(err.Error())
+		return nil, nil, false
+	}
+
+	cfg := loadConfigRevalidated()
+	client, err := auth.ResolveClient(cfg)
+	if err != nil {
+		output.Error(err.Error())
+		return nil, nil, false
+	}
+
+	live, err := fetchLiveState(client, declared)
+	if err != nil {
+		output.Error(err.Error())
+		return nil, nil, false
+	}
+	plan, err := state.NewPlan(declared, live)
+	if err != nil {
+		output.Error(err.Error())
+		return nil, nil, false
+	}
+	return client, plan, true
+}
+
+// fetchLiveState lists the account's products, entitlements, policies,
+// licenses and users, and the entitlements attached to the policies and
+// licenses whose entitlements the file declares.
+func fetchLiveState(client *api.Client, declared map[string][]state.Resource) (*state.Live, error) {
+	live := &state.Live{Resources: map[string][]snapshot.Resource{}, Attached: map[string][]string{}}
+	for _, name := range []string{"products", "entitlements", "policies", "licenses", "users"} {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Reading %s\n", name)
+		}
+		resources, err := listAll(func(params map[string]string) ([]api.JSONAPIResource, error) {
+			return client.ListResources("/"+name, params)
+		}, map[string]string{})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", name, err)
+		}
+		items := make([]snapshot.Resource, len(resources))
+		for i, res := range resources {
+			items[i] = snapshotResource(res)
+		}
+		if name == "users" {
+			live.Users = items
+		} else {
+			live.Resources[name] = items
+		}
+	}
+
+	managed := map[string]bool{}
+	for _, kind := range []string{"policies", "licenses"} {
+		for _, r := range declared[kind] {
+			if r.Entitlements != nil {
+				managed[kind] = true
+				managed[kind+"\x00"+r.ID] = true
+			}
+		}
+	}
+
+	codes := map[string]string{}
+	for _, e := range live.Resources["entitlements"] {
+		codes[e.ID], _ = e.Attributes["code"].(string)
+	}
+	attached := func(path string) ([]string, error) {
+		resources, err := listAll(func(params map[string]string) ([]api.JSONAPIResource, error) {
+			return client.ListResources(path+"/entitlements", params)
+		}, map[string]string{})
+		if err != nil {
+			return nil, fmt.Errorf("listing entitlements of %s: %w", strings.TrimPrefix(path, "/"), err)
+		}
+		list := make([]string, len(resources))
+		for i, res := range resources {
+			if code, ok := res.Attributes["code"].(string); ok {
+				list[i] = code
+			} else {
+				list[i] = codes[res.ID]
+			}
+		}
+		return list, nil
+	}
+
+	// A license lists its policy's entitlements too; only those attached to
+	// the license itself are managed there.
+	policyCodes := map[string][]string{}
+	for _, p := range live.Resources["policies"] {
+		if !managed["policies"] && !managed["licenses"] {
+			break
+		}
+		list, err := attached("/policies/" + p.ID)
+		if err != nil {
+			return nil, err
+		}
+		policyCodes[p.ID] = list
+		live.Attached[p.ID] = list
+	}
+	for _, l := range live.Resources["licenses"] {
+		key, _ := l.Attributes["key"].(string)
+		if !managed["licenses\x00"+key] {
+			continue
+		}
+		list, err := attached("/licenses/" + l.ID)
+		if err != nil {
+			return nil, err
+		}
+		inherited := policyCodes[l.Relationships["policy"]]
+		var own []string
+		for _, code := range list {
+			if !slices.Contains(inherited, code) {
+				own = append(own, code)
+			}
+		}
+		live.Attached[l.ID] = own
+	}
+	return live, nil
+}
+
+// appliedChange is one change made by apply.
+type appliedChange struct {
+	Kind    string `json:"kind"`
+	Action  string `json:"action"`
+	Address string `json:"address"`
+	ID      string `json:"id"`
+}
+
+// applyPlan makes the plan's changes in order. References to resources the
+// plan creates are resolved to the IDs they get. It stops at the first
+// error and returns the changes made before it.
+func applyPlan(client *api.Client, plan *state.Plan, progress func(appliedChange)) ([]appliedChange, error) {
+	applied := []appliedChange{}
+	created := map[string]string{}
+	resolve := func(ref state.Ref) (string, error) {
+		if ref.ID != "" {
+			return ref.ID, nil
+		}
+		if id, ok := created[ref.Kind+"\x00"+ref.Address]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("%s %q was not created", ref.Kind, ref.Address)
+	}
+
+	for _, c := range plan.Changes {
+		fail := func(err error) ([]appliedChange, error) {
+			return applied, fmt.Errorf("%s %s: %w", c.Action, planAddress(c.Kind, c.Address), err)
+		}
+
+		id := c.ID
+		if c.Action == "create" {
+			rels := map[string]api.RelationshipData{}
+			for name, ref := range c.Relationships {
+				relID, err := resolve(ref)
+				if err != nil {
+					return fail(err)
+				}
+				rels[name] = api.RelationshipData{Type: ref.Kind, ID: relID}
+			}
+			res, err := client.CreateResource("/"+c.Kind, c.Kind, c.Attributes, rels)
+			if err != nil {
+				return fail(err)
+			}
+			id = res.ID
+			created[c.Kind+"\x00"+c.Address] = id
+		} else {
+			if len(c.Attributes) > 0 {
+				if _, err := client.UpdateResource("/"+c.Kind+"/"+id, c.Kind, id, c.Attributes); err != nil {
+					return fail(err)
+				}
+			}
+			for _, name := range []string{"policy", "owner"} {
+				ref, ok := c.Relationships[name]
+				if !ok {
+					continue
+				}
+				relID, err := resolve(ref)
+				if err != nil {
+					return fail(err)
+				}
+				if name == "policy" {
+					_, err = client.ChangeLicensePolicy(id, relID)
+				} else {
+					_, err = client.ChangeLicenseOwner(id, relID)
+				}
+				if err != nil {
+					return fail(err)
+				}
+			}
+		}
+
+		for _, set := range []struct {
+			refs   []state.Ref
+			action func(string, []string) error
+		}{{c.Detach, client.DetachEntitlements}, {c.Attach, client.AttachEntitlements}} {
+			if len(set.refs) == 0 {
+				continue
+			}
+			ids := make([]string, len(set.refs))
+			for i, ref := range set.refs {
+				relID, err := resolve(ref)
+				if err != nil {
+					return fail(err)
+				}
+				ids[i] = relID
+			}
+			if err := set.action("/"+c.Kind+"/"+id, ids); err != nil {
+				return fail(fmt.Errorf("entitlements: %w", err))
+			}
+		}
+
+		a := appliedChange{Kind: c.Kind, Action: c.Action, Address: c.Address, ID: id}
+		applied = append(applied, a)
+		progress(a)
+	}
+	return applied, nil
+}
+
+// planStructured reports whether to return the plan as structured output
+// rather than print it, i.e. when --format or --query was given.
+func planStructured(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("format") || queryExpr != ""
+}
+
+var planHeaders = []string{"ACTION", "KIND", "ADDRESS", "ID", "CHANGES"}
+
+func planRows(plan *state.Plan) [][]string {
+	rows := make([][]string, len(plan.Changes))
+	for i, c := range plan.Changes {
+		fields := make([]string, 0, len(c.Diffs))
+		for _, d := range c.Diffs {
+			if !slices.Contains(fields, d.Field) {
+				fields = append(fields, d.Field)
+			}
+		}
+		rows[i] = []string{c.Action, c.Kind, c.Address, c.ID, strings.Join(fields, ", ")}
+	}
+	return rows
+}
+
+func planResult(plan *state.Plan) planOutput {
+	create, update := plan.Counts()
+	return planOutput{
+		Summary: planSummary{Create: create, Update: update, Unchanged: plan.Unchanged},
+		Changes: plan.Changes,
+	}
+}
+
+// printPlan prints the plan in the style of terraform plan.
+func printPlan(plan *state.Plan, color bool) {
+	if len(plan.Changes) == 0 {
+		fmt.Println("No changes. The account matches the state file.")
+		return
+	}
+	fmt.Println("keygen will perform the following actions:")
+	for _, c := range plan.Changes {
+		fmt.Println()
+		header := planAddress(c.Kind, c.Address)
+		if c.ID != "" {
+			header += " (" + c.ID + ")"
+		}
+		fmt.Println(paintAction(c.Action, color, "  "+symbol(c.Action)+" "+header))
+		for _, d := range c.Diffs {
+			var line string
+			switch {
+			case d.Field == "entitlements" && d.From == nil:
+				line = fmt.Sprintf("+ entitlement %s", diffValue(d.To))
+			case d.Field == "entitlements":
+				line = fmt.Sprintf("- entitlement %s", diffValue(d.From))
+			case c.Action == "create":
+				line = fmt.Sprintf("+ %s = %s", d.Field, diffValue(d.To))
+			default:
+				line = fmt.Sprintf("~ %s = %s -> %s", d.Field, diffValue(d.From), diffValue(d.To))
+			}
+			fmt.Println(paintAction(actionOf(line), color, "      "+line))
+		}
+	}
+	create, update := plan.Counts()
+	fmt.Printf("\nPlan: %d to add, %d to change.\n", create, update)
+}
+
+// planAddress names a resource the way plans print it, e.g. policy "App/Pro".
+func planAddress(kind, address string) string {
+	for _, k := range state.Kinds {
+		if k.Name == kind {
+			return fmt.Sprintf("%s %q", k.Singular, address)
+		}
+	}
+	return fmt.Sprintf("%s %q", kind, address)
+}
+
+func symbol(action string) string {
+	if action == "create" {
+		return "+"
+	}
+	return "~"
+}
+
+func actionOf(line string) string {
+	switch line[0] {
+	case '+':
+		return "create"
+	case '-':
+		return "remove"
+	}
+	return "update"
+}
+
+func pastTense(action string) string {
+	if action == "create" {
+		return "created"
+	}
+	return "updated"
+}
+
+func paintAction(action string, color bool, s string) string {
+	if !color {
+		return s
+	}
+	switch action {
+	case "create":
+		return colorGreen + s + colorReset
+	case "remove":
+		return colorRed + s + colorReset
+	}
+	return colorYellow + s + colorReset
+}
+
+func init() {
+	for _, c := range []*cobra.Command{planCmd, applyCmd} {
+		c.Flags().StringP("file", "f", "", "Desired-state YAML file")
+		c.Flags().Bool("no-color", false, "Don't color the plan")
+		rootCmd.AddCommand(c)
+	}
+	applyCmd.Flags().Bool("auto-approve", false, "Apply without asking for approval")
+}
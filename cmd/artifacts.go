@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "Upload, download and list release artifacts",
+}
+
+var artifactsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List artifacts",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		for _, name := range []string{"release", "product", "channel", "platform", "arch", "filetype"} {
+			if v, _ := cmd.Flags().GetString(name); v != "" {
+				params[name] = v
+			}
+		}
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		artifacts, err := client.ListArtifacts(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Artifacts, artifacts)
+	},
+}
+
+var artifactsUploadCmd = &cobra.Command{
+	Use:   "upload [file]",
+	Short: "Upload a file as a release artifact",
+	Long: `Upload a file as a release artifact.
+
+The artifact is registered with Keygen, which redirects to the storage
+provider; the file is then streamed there. A SHA-512 checksum is computed
+locally and stored with the artifact.
+
+Re-running an upload is safe: an artifact with the same filename that is
+already uploaded with a matching checksum is reported and skipped, and one
+left waiting by an interrupted upload is replaced.
+
+Failed transfers are retried up to --retries times. Upload URLs are
+single-use, so each retry registers the artifact again and sends the whole
+file; only downloads resume.
+
+Examples:
+  keygen artifacts upload dist/app-1.2.0.tar.gz --release <id> --platform linux --arch amd64
+  keygen artifacts upload app.exe --release <id> --platform windows --filename app-1.2.0.exe`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		releaseID, _ := cmd.Flags().GetString("release")
+		if releaseID == "" {
+			output.Error("--release is required")
+			return
+		}
+
+		path := args[0]
+		file, err := os.Open(path)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		filename, _ := cmd.Flags().GetString("filename")
+		if filename == "" {
+			filename = filepath.Base(path)
+		}
+
+		checksum, err := api.FileChecksum(path)
+		if err != nil {
+			output.Error("computing checksum: " + err.Error())
+			return
+		}
+
+		existing, err := client.ListArtifacts(map[string]string{"release": releaseID, "page[size]": "100"})
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		for _, a := range existing {
+			if a.Filename != filename {
+				continue
+			}
+			if strings.EqualFold(a.Status, "UPLOADED") {
+				if a.Checksum == checksum {
+					output.Success(artifactUploadOutput{
+						Uploaded: false,
+						Skipped:  "artifact already uploaded with matching checksum",
+						Artifact: a,
+					})
+				} else {
+					output.Error(fmt.Sprintf("artifact %q already exists on release %s with a different checksum", filename, releaseID))
+				}
+				return
+			}
+			// Left behind by an interrupted upload; replace it
+			if err := client.DeleteArtifact(a.ID); err != nil {
+				output.Error("removing incomplete artifact: " + err.Error())
+				return
+			}
+		}
+
+		attrs := map[string]interface{}{
+			"filename": filename,
+			"filesize": info.Size(),
+			"checksum": checksum,
+		}
+		for _, name := range []string{"platform", "arch", "filetype", "signature"} {
+			if v, _ := cmd.Flags().GetString(name); v != "" {
+				attrs[name] = v
+			}
+		}
+		if _, ok := attrs["filetype"]; !ok {
+			if ext := strings.TrimPrefix(filepath.Ext(filename), "."); ext != "" {
+				attrs["filetype"] = ext
+			}
+		}
+
+		retries, _ := cmd.Flags().GetInt("retries")
+		var artifact *api.Artifact
+		attempt := 0
+		for {
+			attempt++
+			if artifact != nil {
+				// Upload URLs are single-use; start over with a fresh artifact
+				_ = client.DeleteArtifact(artifact.ID)
+			}
+
+			var uploadURL string
+			artifact, uploadURL, err = client.CreateArtifact(releaseID, attrs)
+			if err != nil {
+				output.Error(err.Error())
+				return
+			}
+
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Uploading %s (%d bytes), attempt %d\n", filename, info.Size(), attempt)
+			}
+			err = client.UploadArtifact(uploadURL, file, info.Size())
+			if err == nil {
+				break
+			}
+			if attempt > retries {
+				output.Error(fmt.Sprintf("upload failed after %d attempts: %s", attempt, err.Error()))
+				return
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		if refreshed, _, err := client.GetArtifact(artifact.ID); err == nil {
+			artifact = refreshed
+		}
+
+		output.Success(artifactUploadOutput{
+			Uploaded: true,
+			Attempts: attempt,
+			Checksum: checksum,
+			Artifact: *artifact,
+		})
+	},
+}
+
+var artifactsDownloadCmd = &cobra.Command{
+	Use:   "download [artifact-id-or-filename]",
+	Short: "Download a release artifact",
+	Long: `Download a release artifact and verify its checksum.
+
+The artifact is downloaded to <out>.part and only renamed to the output
+file once its checksum is verified. If <out>.part is left from an earlier
+attempt, the download is resumed from where it stopped. An existing output
+file is not overwritten without --force.
+
+Examples:
+  keygen artifacts download <artifact-id>
+  keygen artifacts download app-1.2.0.tar.gz --out /tmp/app.tar.gz --force`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		retries, _ := cmd.Flags().GetInt("retries")
+		skipVerify, _ := cmd.Flags().GetBool("skip-verify")
+		dest, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+
+		var artifact *api.Artifact
+		var written int64
+		resumed := false
+		attempt := 0
+		for {
+			attempt++
+
+			// Download URLs expire, so fetch a fresh one for every attempt
+			var downloadURL string
+			artifact, downloadURL, err = client.GetArtifact(args[0])
+			if err != nil {
+				output.Error(err.Error())
+				return
+			}
+			if downloadURL == "" {
+				output.Error(fmt.Sprintf("artifact %s is not downloadable (status: %s)", artifact.ID, artifact.Status))
+				return
+			}
+			if dest == "" {
+				if dest, err = localFilename(artifact.Filename); err != nil {
+					output.Error(err.Error())
+					return
+				}
+			}
+			if attempt == 1 && !force {
+				if _, err := os.Stat(dest); err == nil {
+					output.Error(fmt.Sprintf("%s already exists; use --force to overwrite it", dest))
+					return
+				}
+			}
+
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Downloading %s to %s, attempt %d\n", artifact.Filename, dest, attempt)
+			}
+			n, r, err := client.DownloadArtifact(downloadURL, dest+".part")
+			written += n
+			resumed = resumed || r
+			if err == nil {
+				break
+			}
+			if attempt > retries {
+				output.Error(fmt.Sprintf("download failed after %d attempts: %s", attempt, err.Error()))
+				return
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		verified := false
+		if artifact.Checksum != "" && !skipVerify {
+			ok, err := api.VerifyChecksum(dest+".part", artifact.Checksum)
+			if err != nil {
+				output.Error("verifying checksum: " + err.Error())
+				return
+			}
+			if !ok {
+				// Remove the partial file so a retry doesn't resume from corrupt data
+				_ = os.Remove(dest + ".part")
+				output.Error(fmt.Sprintf("checksum mismatch for %s; download removed", artifact.Filename))
+				return
+			}
+			verified = true
+		}
+		if err := os.Rename(dest+".part", dest); err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(artifactDownloadOutput{
+			ID:       artifact.ID,
+			Filename: artifact.Filename,
+			Path:     dest,
+			Bytes:    written,
+			Resumed:  resumed,
+			Verified: verified,
+			Checksum: artifact.Checksum,
+		})
+	},
+}
+
+// localFilename is where an artifact is saved without --out: the last
+// element of its filename, so a name from the server like "../../.bashrc"
+// can't write outside the working directory.
+func localFilename(name string) (string, error) {
+	base := filepath.Base(name)
+	if name == "" || base == "." || base == ".." || base == string(filepath.Separator) {
+		return "", fmt.Errorf("artifact filename %q can't be saved as a file; use --out", name)
+	}
+	return base, nil
+}
+
+func init() {
+	artifactsListCmd.Flags().String("release", "", "Filter by release ID")
+	artifactsListCmd.Flags().String("product", "", "Filter by product ID")
+	artifactsListCmd.Flags().String("channel", "", "Filter by release channel")
+	artifactsListCmd.Flags().String("platform", "", "Filter by platform")
+	artifactsListCmd.Flags().String("arch", "", "Filter by architecture")
+	artifactsListCmd.Flags().String("filetype", "", "Filter by file type")
+	artifactsListCmd.Flags().Int("limit", 10, "Results per page")
+	artifactsListCmd.Flags().Int("page", 1, "Page number")
+
+	artifactsUploadCmd.Flags().String("release", "", "Release ID (required)")
+	artifactsUploadCmd.Flags().String("filename", "", "Artifact filename (defaults to the file's base name)")
+	artifactsUploadCmd.Flags().String("platform", "", "Target platform, e.g. linux, darwin, windows")
+	artifactsUploadCmd.Flags().String("arch", "", "Target architecture, e.g. amd64, arm64")
+	artifactsUploadCmd.Flags().String("filetype", "", "File type (defaults to the file extension)")
+	artifactsUploadCmd.Flags().String("signature", "", "Detached signature for the artifact")
+	artifactsUploadCmd.Flags().Int("retries", 3, "Retries for a failed upload")
+
+	artifactsDownloadCmd.Flags().String("out", "", "Output path (defaults to the artifact filename, without its directories)")
+	artifactsDownloadCmd.Flags().Int("retries", 3, "Retries for a failed download")
+	artifactsDownloadCmd.Flags().Bool("skip-verify", false, "Skip checksum verification")
+	artifactsDownloadCmd.Flags().Bool("force", false, "Overwrite an existing output file")
+
+	artifactsCmd.AddCommand(artifactsListCmd)
+	artifactsCmd.AddCommand(artifactsUploadCmd)
+	artifactsCmd.AddCommand(artifactsDownloadCmd)
+	rootCmd.AddCommand(artifactsCmd)
+}
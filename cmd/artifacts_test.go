@@ -0,0 +1,33 @@
+package cmd
+
+import "testing"
+
+func TestLocalFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string // empty when rejected
+	}{
+		{"app-1.2.0.tar.gz", "app-1.2.0.tar.gz"},
+		{"dist/app.tar.gz", "app.tar.gz"},
+		{"../../.bashrc", ".bashrc"},
+		{"/etc/cron.d/job", "job"},
+		{"app/", "app"},
+		{"", ""},
+		{".", ""},
+		{"..", ""},
+		{"../..", ""},
+		{"/", ""},
+	}
+	for _, tt := range tests {
+		got, err := localFilename(tt.name)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("localFilename(%q) = %q, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("localFilename(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
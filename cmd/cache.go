@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/cache"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clear the local API response cache",
+	Long: `API GET responses are cached per profile under
+~/.keygen-cli/cache/<profile>/, so repeated commands like 'users show' and
+'status' don't refetch the same resources.
+
+By default every cached response is revalidated with If-None-Match, so the
+server answers 304 Not Modified instead of resending it. Set a cache TTL
+(--cache-ttl, the profile's cache_ttl or KEYGEN_CACHE_TTL) to use responses
+for that long without asking the server. Writes made through the CLI drop
+the cached responses of the resources they change. Use --refresh to
+refetch, or --no-cache to skip the cache for one command.
+
+Commands that write based on what they read (apply, import, migrate,
+snapshot, the update, delete and activate commands) and diff never use a
+response without revalidating it; logs --follow, exporter, serve and mcp
+serve don't use the cache.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the entries cached for a profile",
+	Long: `Show how many responses are cached for the profile, how many are still
+fresh, their size and age, per collection.
+
+Examples:
+  keygen cache stats --profile prod
+  keygen cache stats --profile prod --format table`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := profileCache()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		stats := store.Stats()
+		rows := make([][]string, len(stats.Collections))
+		for i, c := range stats.Collections {
+			rows[i] = []string{c.Collection, strconv.Itoa(c.Entries), strconv.Itoa(c.Fresh), strconv.FormatInt(c.Bytes, 10)}
+		}
+		output.SuccessTable(stats, []string{"COLLECTION", "ENTRIES", "FRESH", "BYTES"}, rows)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached responses",
+	Long: `Remove the responses cached for the profile, or for every profile with
+--all.
+
+Examples:
+  keygen cache clear --profile prod
+  keygen cache clear --all`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			dir := config.CacheDir("")
+			removed := 0
+			profiles, _ := filepath.Glob(filepath.Join(dir, "*"))
+			for _, p := range profiles {
+				n, _ := cache.New(p, 0).Clear()
+				removed += n
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				output.Error(err.Error())
+				return
+			}
+			output.Success(cacheClearOutput{Message: "Cache cleared", Dir: dir, Removed: removed})
+			return
+		}
+
+		store, err := profileCache()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		removed, err := store.Clear()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		output.Success(cacheClearOutput{Message: "Cache cleared", Profile: profileName, Dir: store.Dir, Removed: removed})
+	},
+}
+
+// profileCache returns the cache store of the active profile.
+func profileCache() (*cache.Store, error) {
+	cfg := loadConfig()
+	ttl, err := cfg.CacheDuration()
+	if err != nil {
+		return nil, err
+	}
+	return cache.New(config.CacheDir(cfg.ProfileName), ttl), nil
+}
+
+func init() {
+	cacheClearCmd.Flags().Bool("all", false, "Clear the cache of every profile")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
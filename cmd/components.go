@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
 	"github.com/productivityenthusiast/keygen-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -13,18 +15,75 @@ var componentsCmd = &cobra.Command{
 	Short: "Manage machine components",
 }
 
+var componentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List a machine's components",
+	Long: `List the components registered on a machine.
+
+Examples:
+  keygen components list --machine <machine-id> --format table
+  keygen components list --machine <machine-id> --where 'name~printer'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		machineID, _ := cmd.Flags().GetString("machine")
+		if machineID == "" {
+			output.Error("--machine is required")
+			return
+		}
+
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		page, _ := cmd.Flags().GetInt("page")
+		components, err := client.ListComponents(machineID, page, limit)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Components, components)
+	},
+}
+
 var componentsCheckCmd = &cobra.Command{
 	Use:   "check [fingerprint]",
 	Short: "Check if a component fingerprint is registered",
-	Args:  cobra.ExactArgs(1),
+	Long: `Check if a component fingerprint is registered.
+
+With --local, the current host's fingerprints are derived (see 'keygen
+fingerprint') and the machine and every component are checked at once.
+
+With --offline, the fingerprints are checked against the last 'keygen sync'.
+
+Examples:
+  keygen components check <fingerprint>
+  keygen components check --local --salt com.example.app
+  keygen components check <fingerprint> --offline`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{offlineAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
+		local, _ := cmd.Flags().GetBool("local")
+		if local == (len(args) == 1) {
+			output.Error("pass either a fingerprint or --local")
+			return
+		}
+
 		cfg := loadConfig()
-		client, err := auth.ResolveClient(cfg)
+		client, err := resolveReader(cfg)
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
 
+		if local {
+			checkLocalFingerprints(cmd, client)
+			return
+		}
+
 		fingerprint := args[0]
 		comp, err := client.FindComponentByFingerprint(fingerprint)
 		if err != nil {
@@ -33,28 +92,94 @@ var componentsCheckCmd = &cobra.Command{
 		}
 
 		if comp != nil {
-			output.Success(map[string]interface{}{
-				"found":       true,
-				"fingerprint": comp.Fingerprint,
-				"id":          comp.ID,
-				"name":        comp.Name,
-				"machine_id":  comp.MachineID,
+			output.Success(componentCheckOutput{
+				Found:       true,
+				Fingerprint: comp.Fingerprint,
+				ID:          comp.ID,
+				Name:        comp.Name,
+				MachineID:   comp.MachineID,
 			})
 		} else {
-			output.Success(map[string]interface{}{
-				"found":       false,
-				"fingerprint": fingerprint,
-			})
+			output.Success(componentCheckOutput{Found: false, Fingerprint: fingerprint})
 		}
 	},
 }
 
+// checkResult is a local fingerprint and the machine or component it
+// matched, as printed by 'components check --local'.
+type checkResult struct {
+	localFingerprint
+	Found     bool   `json:"found"`
+	ID        string `json:"id,omitempty"`
+	MachineID string `json:"machine_id,omitempty"`
+	LicenseID string `json:"license_id,omitempty"`
+}
+
+// checkLocalFingerprints checks the current host's machine and component
+// fingerprints against the account.
+func checkLocalFingerprints(cmd *cobra.Command, client accountReader) {
+	machine, components, notes, err := localFingerprints(cmd)
+	if err != nil {
+		output.Error(err.Error())
+		return
+	}
+
+	var machineResult *checkResult
+	if machine != nil {
+		machineResult = &checkResult{localFingerprint: *machine}
+		machines, err := client.ListMachines(map[string]string{"fingerprint": machine.Fingerprint})
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		for _, m := range machines {
+			if m.Fingerprint == machine.Fingerprint {
+				machineResult.Found = true
+				machineResult.ID = m.ID
+				machineResult.LicenseID = m.LicenseID
+				break
+			}
+		}
+	}
+
+	fps := make([]string, len(components))
+	for i, c := range components {
+		fps[i] = c.Fingerprint
+	}
+	found, err := client.FindComponentsByFingerprints(fps)
+	if err != nil {
+		output.Error(err.Error())
+		return
+	}
+
+	results := make([]checkResult, len(components))
+	for i, c := range components {
+		results[i] = checkResult{localFingerprint: c}
+		if comp := found[c.Fingerprint]; comp != nil {
+			results[i].Found = true
+			results[i].ID = comp.ID
+			results[i].MachineID = comp.MachineID
+		}
+	}
+
+	headers := []string{"TYPE", "KIND", "NAME", "FINGERPRINT", "FOUND", "ID"}
+	var rows [][]string
+	if machineResult != nil {
+		rows = append(rows, []string{"machine", machineResult.Kind, machineResult.Name, machineResult.Fingerprint, fmt.Sprintf("%v", machineResult.Found), machineResult.ID})
+	}
+	for _, r := range results {
+		rows = append(rows, []string{"component", r.Kind, r.Name, r.Fingerprint, fmt.Sprintf("%v", r.Found), r.ID})
+	}
+
+	output.SuccessTable(componentCheckLocalOutput{Machine: machineResult, Components: results, Unreadable: notes}, headers, rows)
+}
+
 var componentsDeleteCmd = &cobra.Command{
 	Use:   "delete [fingerprint]",
 	Short: "Delete a component by fingerprint",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := loadConfig()
+		cfg := loadConfigForWrite()
 		client, err := auth.ResolveClient(cfg)
 		if err != nil {
 			output.Error(err.Error())
@@ -76,13 +201,13 @@ var componentsDeleteCmd = &cobra.Command{
 		}
 
 		if !force {
-			output.Success(map[string]interface{}{
-				"action":      "delete",
-				"fingerprint": comp.Fingerprint,
-				"id":          comp.ID,
-				"name":        comp.Name,
-				"machine_id":  comp.MachineID,
-				"confirm":     "use --force to confirm deletion",
+			output.Success(componentDeletePreview{
+				Action:      "delete",
+				Fingerprint: comp.Fingerprint,
+				ID:          comp.ID,
+				Name:        comp.Name,
+				MachineID:   comp.MachineID,
+				Confirm:     "use --force to confirm deletion",
 			})
 			return
 		}
@@ -92,19 +217,216 @@ var componentsDeleteCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"deleted":     true,
-			"fingerprint": comp.Fingerprint,
-			"id":          comp.ID,
-			"name":        comp.Name,
+		output.Success(componentDeleteOutput{
+			Deleted:     true,
+			Fingerprint: comp.Fingerprint,
+			ID:          comp.ID,
+			Name:        comp.Name,
+		})
+	},
+}
+
+var componentsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a component to a machine",
+	Long: `Add a component with the given fingerprint to a machine.
+
+Examples:
+  keygen components add --machine <machine-id> --fingerprint <fp> --name "Front desk printer"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		machineID, _ := cmd.Flags().GetString("machine")
+		fingerprint, _ := cmd.Flags().GetString("fingerprint")
+		name, _ := cmd.Flags().GetString("name")
+		if machineID == "" || fingerprint == "" || name == "" {
+			output.Error("--machine, --fingerprint and --name are required")
+			return
+		}
+
+		comp, err := client.CreateComponent(machineID, fingerprint, name)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		comp.MachineID = machineID
+
+		output.Success(comp)
+	},
+}
+
+var componentsRenameCmd = &cobra.Command{
+	Use:   "rename [fingerprint]",
+	Short: "Rename a component by fingerprint",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		if name == "" {
+			output.Error("--name is required")
+			return
+		}
+
+		comp, err := client.FindComponentByFingerprint(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		if comp == nil {
+			output.Error(fmt.Sprintf("component not found: %s", args[0]))
+			return
+		}
+
+		updated, err := client.UpdateComponent(comp.ID, map[string]interface{}{"name": name})
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(componentRenameOutput{
+			ID:          updated.ID,
+			Fingerprint: updated.Fingerprint,
+			OldName:     comp.Name,
+			Name:        updated.Name,
+			MachineID:   comp.MachineID,
+		})
+	},
+}
+
+var componentsMoveCmd = &cobra.Command{
+	Use:   "move [fingerprint]",
+	Short: "Move a component to another machine",
+	Long: `Move a component to another machine, e.g. after replacing hardware.
+
+Keygen components can't be reassigned, so the move deletes the component and
+recreates it on the target machine. If recreating fails, the component is
+restored on its original machine so the device isn't left unlicensed.
+
+Examples:
+  keygen components move <fingerprint> --to-machine <machine-id>
+  keygen components move <fingerprint> --to-machine <machine-id> --name "Replacement unit" --force`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		target, _ := cmd.Flags().GetString("to-machine")
+		if target == "" {
+			output.Error("--to-machine is required")
+			return
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		comp, err := client.FindComponentByFingerprint(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		if comp == nil {
+			output.Error(fmt.Sprintf("component not found: %s", args[0]))
+			return
+		}
+		if comp.MachineID == target {
+			output.Error(fmt.Sprintf("component %s is already on machine %s", comp.Fingerprint, target))
+			return
+		}
+
+		name := comp.Name
+		if v, _ := cmd.Flags().GetString("name"); v != "" {
+			name = v
+		}
+
+		if !force {
+			output.Success(componentMovePreview{
+				Action:      "move",
+				Fingerprint: comp.Fingerprint,
+				ID:          comp.ID,
+				Name:        name,
+				FromMachine: comp.MachineID,
+				ToMachine:   target,
+				Confirm:     "use --force to confirm the move",
+			})
+			return
+		}
+
+		if err := client.DeleteComponent(comp.ID); err != nil {
+			output.Error("delete failed: " + err.Error())
+			return
+		}
+
+		moved, err := client.CreateComponent(target, comp.Fingerprint, name)
+		if err != nil {
+			restored, rerr := client.CreateComponent(comp.MachineID, comp.Fingerprint, comp.Name)
+			if rerr != nil {
+				output.ErrorDetail("move failed and rollback failed; component is not registered", map[string]interface{}{
+					"fingerprint":    comp.Fingerprint,
+					"from_machine":   comp.MachineID,
+					"create_error":   err.Error(),
+					"rollback_error": rerr.Error(),
+				})
+				os.Exit(1)
+			}
+			output.ErrorDetail("move failed; component restored on original machine", map[string]interface{}{
+				"fingerprint":  comp.Fingerprint,
+				"machine_id":   comp.MachineID,
+				"restored_id":  restored.ID,
+				"create_error": err.Error(),
+			})
+			return
+		}
+
+		output.Success(componentMoveOutput{
+			Moved:       true,
+			Fingerprint: moved.Fingerprint,
+			OldID:       comp.ID,
+			ID:          moved.ID,
+			Name:        moved.Name,
+			FromMachine: comp.MachineID,
+			ToMachine:   target,
 		})
 	},
 }
 
 func init() {
+	componentsListCmd.Flags().String("machine", "", "Machine ID (required)")
+	componentsListCmd.Flags().Int("limit", 100, "Results per page")
+	componentsListCmd.Flags().Int("page", 1, "Page number")
+
+	componentsCheckCmd.Flags().Bool("local", false, "Check the current host's fingerprints")
+	addFingerprintFlags(componentsCheckCmd)
+
 	componentsDeleteCmd.Flags().Bool("force", false, "Skip confirmation")
 
+	componentsAddCmd.Flags().String("machine", "", "Machine ID (required)")
+	componentsAddCmd.Flags().String("fingerprint", "", "Component fingerprint (required)")
+	componentsAddCmd.Flags().String("name", "", "Component name (required)")
+
+	componentsRenameCmd.Flags().String("name", "", "New component name (required)")
+
+	componentsMoveCmd.Flags().String("to-machine", "", "Target machine ID (required)")
+	componentsMoveCmd.Flags().String("name", "", "New component name (defaults to the current name)")
+	componentsMoveCmd.Flags().Bool("force", false, "Skip confirmation")
+
+	componentsCmd.AddCommand(componentsListCmd)
 	componentsCmd.AddCommand(componentsCheckCmd)
 	componentsCmd.AddCommand(componentsDeleteCmd)
+	componentsCmd.AddCommand(componentsAddCmd)
+	componentsCmd.AddCommand(componentsRenameCmd)
+	componentsCmd.AddCommand(componentsMoveCmd)
 	rootCmd.AddCommand(componentsCmd)
 }
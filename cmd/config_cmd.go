@@ -28,14 +28,15 @@ var configShowCmd = &cobra.Command{
 			}
 		}
 
-		output.Success(map[string]interface{}{
-			"profile":      cfg.ProfileName,
-			"account_id":   cfg.AccountID,
-			"base_url":     cfg.BaseURL,
-			"token":        maskedToken,
-			"email":        cfg.Email,
-			"has_password": cfg.Password != "",
-			"token_expiry": cfg.TokenExp,
+		output.Success(configShowOutput{
+			Profile:     cfg.ProfileName,
+			AccountID:   cfg.AccountID,
+			BaseURL:     cfg.BaseURL,
+			Token:       maskedToken,
+			Email:       cfg.Email,
+			HasPassword: cfg.Password != "",
+			TokenExpiry: cfg.TokenExp,
+			PublicKey:   cfg.PublicKey,
 		})
 	},
 }
@@ -48,9 +49,9 @@ var configClearCmd = &cobra.Command{
 		if err := cfg.Clear(); err != nil {
 			fmt.Printf("Note: %v\n", err)
 		}
-		output.Success(map[string]string{
-			"message": fmt.Sprintf("Configuration for profile %q cleared", cfg.ProfileName),
-			"profile": cfg.ProfileName,
+		output.Success(messageOutput{
+			Message: fmt.Sprintf("Configuration for profile %q cleared", cfg.ProfileName),
+			Profile: cfg.ProfileName,
 		})
 	},
 }
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Compare two snapshots or profiles",
+	Long: `Compare two account states and report the licenses, users, machines and
+components that were added, removed or modified from a to b.
+
+Each side is a snapshot file from 'keygen snapshot export', or
+profile:<name> to fetch the live state of a profile's account.
+
+Resources are matched by ID, then by license key, user email, or
+fingerprint within the matched license or machine, so two different
+accounts can be compared. Modified resources list their changed fields,
+e.g. status, expiry, metadata.<key> or the policy and owner
+relationships. Timestamps and validation, check-in and heartbeat state
+are not compared; use --ignore to skip more attributes, e.g. uses.
+
+The result is JSON by default, or a unified view with --unified, colored
+when printed to a terminal.
+
+Exit status is 0 when nothing differs, 1 when something does and 2 on
+errors, so diff can be used in drift checks.
+
+Examples:
+  keygen diff before.json.gz after.json.gz
+  keygen diff snap.json.gz profile:prod --unified
+  keygen diff profile:prod profile:staging --kinds licenses --ignore uses
+  keygen diff profile:prod profile:staging --format table`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		kinds, _ := cmd.Flags().GetStringSlice("kinds")
+		for _, k := range kinds {
+			if _, ok := snapshot.Lookup(k); !ok {
+				diffError(fmt.Sprintf("unknown kind %q", k))
+			}
+		}
+		ignore, _ := cmd.Flags().GetStringSlice("ignore")
+
+		a, err := loadDiffSide(args[0])
+		if err != nil {
+			diffError(err.Error())
+		}
+		b, err := loadDiffSide(args[1])
+		if err != nil {
+			diffError(err.Error())
+		}
+
+		changes := snapshot.Diff(a, b, snapshot.DiffOptions{Kinds: kinds, Ignore: ignore})
+
+		if unified, _ := cmd.Flags().GetBool("unified"); unified {
+			noColor, _ := cmd.Flags().GetBool("no-color")
+			printUnifiedDiff(args[0], args[1], changes, useColor(noColor))
+		} else {
+			summary := map[string]int{"added": 0, "removed": 0, "modified": 0}
+			rows := make([][]string, len(changes))
+			for i, c := range changes {
+				summary[c.Op]++
+				fields := make([]string, len(c.Fields))
+				for j, f := range c.Fields {
+					fields[j] = f.Field
+				}
+				rows[i] = []string{c.Kind, c.Op, c.Label, c.AID, c.BID, strings.Join(fields, ", ")}
+			}
+			output.SuccessTable(diffOutput{
+				A:         diffSide(args[0], a),
+				B:         diffSide(args[1], b),
+				Identical: len(changes) == 0,
+				Summary:   summary,
+				Changes:   changes,
+			}, []string{"KIND", "OP", "LABEL", "A_ID", "B_ID", "FIELDS"}, rows)
+		}
+
+		if len(changes) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// loadDiffSide reads a snapshot file, or exports a profile's account for
+// profile:<name>.
+func loadDiffSide(arg string) (*snapshot.Snapshot, error) {
+	name, ok := strings.CutPrefix(arg, "profile:")
+	if !ok {
+		return snapshot.Read(arg)
+	}
+	cfg, client, err := profileClient(name)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := exportSnapshot(client, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return snap, nil
+}
+
+func diffSide(arg string, s *snapshot.Snapshot) diffSideOutput {
+	return diffSideOutput{
+		Source:    arg,
+		AccountID: s.AccountID,
+		Created:   s.Created,
+		Resources: s.Counts(),
+	}
+}
+
+// diffError reports an error with exit status 2, which drift checks can
+// tell apart from "differences found".
+func diffError(msg string) {
+	output.Error(msg)
+	os.Exit(2)
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorBold   = "\033[1m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+)
+
+// printUnifiedDiff prints changes in the style of diff -u: removed
+// resources in red, added in green and modified ones with their fields.
+func printUnifiedDiff(a, b string, changes []snapshot.Change, color bool) {
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+
+	fmt.Println(paint(colorBold, "--- a: "+a))
+	fmt.Println(paint(colorBold, "+++ b: "+b))
+	kind := ""
+	for _, c := range changes {
+		if c.Kind != kind {
+			kind = c.Kind
+			fmt.Println(paint(colorCyan, "@@ "+kind+" @@"))
+		}
+		switch c.Op {
+		case "removed":
+			fmt.Println(paint(colorRed, fmt.Sprintf("- %s (%s)", c.Label, c.AID)))
+		case "added":
+			fmt.Println(paint(colorGreen, fmt.Sprintf("+ %s (%s)", c.Label, c.BID)))
+		case "modified":
+			id := c.AID
+			if c.BID != c.AID {
+				id += " -> " + c.BID
+			}
+			fmt.Println(paint(colorYellow, fmt.Sprintf("~ %s (%s)", c.Label, id)))
+			for _, f := range c.Fields {
+				fmt.Println(paint(colorRed, fmt.Sprintf("-   %s: %s", f.Field, diffValue(f.From))))
+				fmt.Println(paint(colorGreen, fmt.Sprintf("+   %s: %s", f.Field, diffValue(f.To))))
+			}
+		}
+	}
+	if len(changes) == 0 && !quiet {
+		fmt.Println("No differences.")
+	}
+}
+
+func diffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// useColor reports whether to color output: only on a terminal, and not
+// when NO_COLOR is set.
+func useColor(noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	diffCmd.Flags().StringSlice("kinds", []string{"licenses", "users", "machines", "components"}, "Kinds to compare: products, policies, users, licenses, machines, components")
+	diffCmd.Flags().StringSlice("ignore", nil, "Attributes not to compare, e.g. uses,metadata")
+	diffCmd.Flags().Bool("unified", false, "Print a unified view instead of JSON")
+	diffCmd.Flags().Bool("no-color", false, "Don't color the unified view")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/metrics"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Serve license and usage metrics for Prometheus",
+	Long: `Serve the aggregates of 'keygen status' as Prometheus gauges on /metrics:
+licenses by product, policy and status, days to expiry, machines per
+license and quota utilization, plus scrape-health metrics for the exporter
+itself.
+
+The aggregates are refreshed every --interval, paging through every
+license. Rate-limited requests are retried after the limit resets, and a
+refresh is postponed while the rate limit is exhausted. When a refresh
+fails, the last good values are kept and keygen_exporter_up drops to 0.
+
+For a one-shot textfile, use 'keygen status --format openmetrics'.
+
+Examples:
+  keygen exporter --listen :9155 --profile prod
+  keygen exporter --listen 127.0.0.1:9155 --interval 5m --profile prod`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		// A long-running server must not answer from a stale cache
+		cfg.NoCache = true
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval < 10*time.Second {
+			output.Error("--interval must be at least 10s")
+			return
+		}
+		listen, _ := cmd.Flags().GetString("listen")
+
+		exp := &exporter{client: client, cfg: cfg}
+		exp.refresh()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go exp.run(ctx, interval)
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", exp)
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", listen)
+		}
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// exporter holds the most recent status aggregates and refresh health.
+type exporter struct {
+	client *api.Client
+	cfg    *config.Config
+
+	mu          sync.Mutex
+	families    []metrics.Family
+	up          bool
+	lastRefresh time.Time
+	lastSuccess time.Time
+	duration    time.Duration
+	successes   int
+	failures    int
+}
+
+// run refreshes the aggregates every interval, waiting longer while the
+// account's rate limit is exhausted.
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	for {
+		wait := interval
+		if rl := e.client.RateLimit(); rl.Limit > 0 && rl.Remaining == 0 {
+			if until := time.Until(rl.Reset); until > wait {
+				wait = until
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+			e.refresh()
+		}
+	}
+}
+
+func (e *exporter) refresh() {
+	start := time.Now()
+	result, details, _, err := accountStatus(e.client, e.cfg, "")
+	elapsed := time.Since(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastRefresh = start
+	e.duration = elapsed
+	e.up = err == nil
+	if err != nil {
+		e.failures++
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Error: refreshing metrics: %v\n", err)
+		}
+		return
+	}
+	e.successes++
+	e.lastSuccess = start
+	e.families = statusMetrics(result, details)
+}
+
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	families := append([]metrics.Family(nil), e.families...)
+	families = append(families, e.healthMetrics()...)
+	e.mu.Unlock()
+
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	}
+	_ = metrics.Write(w, families, openMetrics)
+}
+
+// healthMetrics describes the exporter's own refreshes. Callers hold e.mu.
+func (e *exporter) healthMetrics() []metrics.Family {
+	up := metrics.Family{Name: "keygen_exporter_up", Help: "Whether the last refresh from Keygen succeeded.", Type: metrics.Gauge}
+	up.Add(boolValue(e.up))
+	last := metrics.Family{Name: "keygen_exporter_last_refresh_timestamp_seconds", Help: "Unix time of the last refresh attempt.", Type: metrics.Gauge}
+	last.Add(unixSeconds(e.lastRefresh))
+	success := metrics.Family{Name: "keygen_exporter_last_success_timestamp_seconds", Help: "Unix time of the last successful refresh.", Type: metrics.Gauge}
+	success.Add(unixSeconds(e.lastSuccess))
+	duration := metrics.Family{Name: "keygen_exporter_refresh_duration_seconds", Help: "Time taken by the last refresh.", Type: metrics.Gauge}
+	duration.Add(e.duration.Seconds())
+	refreshes := metrics.Family{Name: "keygen_exporter_refreshes_total", Help: "Refreshes from Keygen, by result.", Type: metrics.Counter}
+	refreshes.Add(float64(e.successes), metrics.L("result", "success")...)
+	refreshes.Add(float64(e.failures), metrics.L("result", "error")...)
+
+	rl := e.client.RateLimit()
+	limit := metrics.Family{Name: "keygen_exporter_rate_limit", Help: "Keygen API rate limit per window.", Type: metrics.Gauge}
+	limit.Add(float64(rl.Limit))
+	remaining := metrics.Family{Name: "keygen_exporter_rate_limit_remaining", Help: "Requests left in the current rate limit window.", Type: metrics.Gauge}
+	remaining.Add(float64(rl.Remaining))
+	throttled := metrics.Family{Name: "keygen_exporter_rate_limited_total", Help: "Requests that were rate limited and retried.", Type: metrics.Counter}
+	throttled.Add(float64(rl.Throttled))
+
+	return []metrics.Family{up, last, success, duration, refreshes, limit, remaining, throttled}
+}
+
+// statusMetrics turns the aggregates of 'keygen status' into gauges.
+func statusMetrics(result *statusOutput, details []licenseDetail) []metrics.Family {
+	licenses := metrics.Family{Name: "keygen_licenses", Help: "Licenses by product, policy and status.", Type: metrics.Gauge}
+	expiry := metrics.Family{Name: "keygen_license_days_to_expiry", Help: "Days until a license expires; absent for licenses without expiry.", Type: metrics.Gauge}
+	machines := metrics.Family{Name: "keygen_license_machines", Help: "Machines activated on a license.", Type: metrics.Gauge}
+	used := metrics.Family{Name: "keygen_license_quota_used", Help: "Quota used by a license, by resource.", Type: metrics.Gauge}
+	limit := metrics.Family{Name: "keygen_license_quota_limit", Help: "Quota limit of a license, by resource.", Type: metrics.Gauge}
+	utilization := metrics.Family{Name: "keygen_license_quota_utilization", Help: "Quota used divided by its limit, by resource.", Type: metrics.Gauge}
+
+	counts := map[[3]string]int{}
+	for _, d := range details {
+		counts[[3]string{d.ProductID, d.PolicyID, d.Status}]++
+
+		labels := metrics.L("license", d.ID, "name", d.Name, "product", d.ProductID, "policy", d.PolicyID, "status", d.Status)
+		if d.Expiry != "" {
+			expiry.Add(float64(d.DaysRemaining), labels...)
+		}
+		machines.Add(float64(d.Machines), labels...)
+
+		for _, q := range d.quotas() {
+			ql := append(append([]metrics.Label(nil), labels...), metrics.Label{Name: "resource", Value: q.Resource})
+			used.Add(float64(q.Used), ql...)
+			limit.Add(q.Limit, ql...)
+			utilization.Add(q.Utilization(), ql...)
+		}
+	}
+	for k, n := range counts {
+		licenses.Add(float64(n), metrics.L("product", k[0], "policy", k[1], "status", k[2])...)
+	}
+
+	families := []metrics.Family{licenses, expiry, machines, used, limit, utilization}
+	totals := []struct {
+		value      *int
+		name, help string
+	}{
+		{result.TotalUsers, "keygen_users", "Users in the account."},
+		{result.TotalProducts, "keygen_products", "Products in the account."},
+		{&result.TotalMachines, "keygen_machines", "Machines across all licenses."},
+		{&result.TotalComponents, "keygen_components", "Components across all machines."},
+	}
+	for _, t := range totals {
+		if t.value != nil {
+			f := metrics.Family{Name: t.name, Help: t.help, Type: metrics.Gauge}
+			f.Add(float64(*t.value))
+			families = append(families, f)
+		}
+	}
+	return families
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.UnixNano()) / 1e9
+}
+
+func init() {
+	exporterCmd.Flags().String("listen", ":9155", "Address to serve /metrics on")
+	exporterCmd.Flags().Duration("interval", time.Minute, "How often to refresh from Keygen")
+	rootCmd.AddCommand(exporterCmd)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/fingerprint"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var fingerprintCmd = &cobra.Command{
+	Use:   "fingerprint",
+	Short: "Derive this host's machine and component fingerprints",
+	Long: `Derive the machine and component fingerprints for the current host,
+as the client SDK computes them. Linux only.
+
+The machine fingerprint comes from /etc/machine-id, falling back to the
+DMI product UUID (usually readable only by root). Component fingerprints
+come from the DMI product UUID, physical network interface MAC addresses
+and disk serial numbers, where readable.
+
+Hashing schemes (--scheme):
+  hmac-sha256  HMAC-SHA256 keyed by the identifier over --salt; with the
+               app ID as salt this is the SDKs' "protected ID" (default)
+  sha256       SHA-256 of salt + identifier
+  sha512       SHA-512 of salt + identifier
+  raw          the identifier itself
+
+The printed fingerprints are exactly what 'keygen components check'
+expects; 'keygen components check --local' checks them all at once.
+
+Examples:
+  keygen fingerprint --salt com.example.app
+  keygen fingerprint --scheme sha256 --salt s3cret --format table
+  keygen fingerprint --machine-source product-uuid --show-values`,
+	Run: func(cmd *cobra.Command, args []string) {
+		machine, components, notes, err := localFingerprints(cmd)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		headers := []string{"TYPE", "KIND", "NAME", "FINGERPRINT"}
+		var rows [][]string
+		if machine != nil {
+			rows = append(rows, []string{"machine", machine.Kind, machine.Name, machine.Fingerprint})
+		}
+		for _, c := range components {
+			rows = append(rows, []string{"component", c.Kind, c.Name, c.Fingerprint})
+		}
+
+		scheme, _ := cmd.Flags().GetString("scheme")
+		salt, _ := cmd.Flags().GetString("salt")
+		output.SuccessTable(fingerprintOutput{
+			Scheme:     scheme,
+			Salted:     salt != "",
+			Machine:    machine,
+			Components: components,
+			Unreadable: notes,
+		}, headers, rows)
+	},
+}
+
+// localFingerprint is a fingerprint derived from one host identifier.
+type localFingerprint struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Value       string `json:"value,omitempty"`
+}
+
+// addFingerprintFlags registers the hashing flags shared by 'fingerprint'
+// and 'components check --local'.
+func addFingerprintFlags(cmd *cobra.Command) {
+	cmd.Flags().String("scheme", "hmac-sha256", "Hashing scheme: "+strings.Join(fingerprint.Schemes, ", "))
+	cmd.Flags().String("salt", "", "Salt (for hmac-sha256, the app ID)")
+	cmd.Flags().String("machine-source", "", "Identifier for the machine fingerprint: machine-id or product-uuid")
+	cmd.Flags().Bool("show-values", false, "Include the raw identifiers in the output")
+}
+
+// localFingerprints collects this host's identifiers and hashes them with
+// the scheme and salt from the command's flags.
+func localFingerprints(cmd *cobra.Command) (*localFingerprint, []localFingerprint, []string, error) {
+	scheme, _ := cmd.Flags().GetString("scheme")
+	salt, _ := cmd.Flags().GetString("salt")
+	machineSource, _ := cmd.Flags().GetString("machine-source")
+	showValues, _ := cmd.Flags().GetBool("show-values")
+
+	sources, notes, err := fingerprint.Collect()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	derive := func(s fingerprint.Source) (localFingerprint, error) {
+		fp, err := fingerprint.Hash(s.Value, scheme, salt)
+		if err != nil {
+			return localFingerprint{}, err
+		}
+		lf := localFingerprint{Kind: s.Kind, Name: s.Name, Fingerprint: fp}
+		if showValues {
+			lf.Value = s.Value
+		}
+		return lf, nil
+	}
+
+	var machine *localFingerprint
+	if src, err := fingerprint.MachineSource(sources, machineSource); err == nil {
+		m, err := derive(*src)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		machine = &m
+	} else {
+		notes = append(notes, "machine: "+err.Error())
+	}
+
+	var components []localFingerprint
+	for _, s := range fingerprint.ComponentSources(sources) {
+		c, err := derive(s)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		components = append(components, c)
+	}
+
+	return machine, components, notes, nil
+}
+
+func init() {
+	addFingerprintFlags(fingerprintCmd)
+	rootCmd.AddCommand(fingerprintCmd)
+}
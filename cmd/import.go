@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/importer"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file.csv>",
+	Short: "Create users or licenses from a CSV file",
+	Long: `Create users or licenses from the rows of a CSV file, e.g. a reseller's
+customer spreadsheet.
+
+Columns are matched to fields by their header ("Email", "First Name",
+"max_machines", "metadata.reseller"), or by a --mapping YAML file that
+names the column of each field and defaults for empty cells:
+
+  columns:
+    email: E-mail address
+    first_name: Given name
+    metadata.reseller: Partner
+  defaults:
+    role: user
+
+Fields for --type users: email (required), first_name, last_name, role,
+password. Fields for --type licenses: policy (required, a policy ID), key,
+name, owner (an existing user's email or ID), expiry (YYYY-MM-DD or
+RFC 3339), max_machines, max_cores, max_uses, protected, suspended. Both
+take metadata.<key>; whole numbers are stored as numbers.
+
+Every row is validated before anything is written: email format, required
+fields, policy IDs and owners that exist in the account, and license keys
+used twice in the file. If any row is invalid, nothing is imported.
+
+Users whose email already exists in the account, or appears earlier in the
+file, are not created again; licenses whose key already exists are skipped.
+So an import can be rerun after a failure, as long as licenses have keys.
+
+The results are written to --results (default <file>-results.csv): the
+original columns plus status, id (owner_id for licenses) and error.
+
+Examples:
+  keygen import reseller-users.csv --type users --mapping reseller.yaml
+  keygen import reseller-licenses.csv --type licenses --dry-run
+  keygen import licenses.csv --type licenses --results out.csv --format table`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		typeName, _ := cmd.Flags().GetString("type")
+		t, ok := importer.Lookup(typeName)
+		if !ok {
+			output.Error("--type must be users or licenses")
+			return
+		}
+		var mapping *importer.Mapping
+		if path, _ := cmd.Flags().GetString("mapping"); path != "" {
+			var err error
+			if mapping, err = importer.LoadMapping(path); err != nil {
+				output.Error(err.Error())
+				return
+			}
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		resultsPath, _ := cmd.Flags().GetString("results")
+		if resultsPath == "" {
+			resultsPath = strings.TrimSuffix(args[0], filepath.Ext(args[0])) + "-results.csv"
+		}
+
+		in, err := os.Open(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		file, err := importer.Read(in, t, mapping)
+		in.Close()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		file.Validate(t)
+
+		cfg := loadConfigRevalidated()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		var check func(*api.Client, *importer.File) error
+		var create func(*api.Client, *importer.Row) error
+		if t.Name == "users" {
+			check, create = checkUserRows, createUser
+		} else {
+			check, create = checkLicenseRows, createLicense
+		}
+		if err := check(client, file); err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if invalid := file.Invalid(); invalid > 0 {
+			var rows []*importer.Row
+			for _, row := range file.Rows {
+				if len(row.Errors) > 0 {
+					row.Status = "invalid"
+					rows = append(rows, row)
+				}
+			}
+			detail := map[string]interface{}{"invalid": rows}
+			if err := writeImportResults(resultsPath, file, t); err != nil {
+				detail["results_error"] = err.Error()
+			} else {
+				detail["results"] = resultsPath
+			}
+			output.ErrorDetail(fmt.Sprintf("%d of %d rows are invalid; nothing was imported", invalid, len(file.Rows)), detail)
+			os.Exit(1)
+		}
+
+		for i, row := range file.Rows {
+			if row.First != nil {
+				row.ID = row.First.ID
+			}
+			if row.Status != "" {
+				continue
+			}
+			if dryRun {
+				row.Status = "create"
+				continue
+			}
+			if err := create(client, row); err != nil {
+				row.Status = "failed"
+				row.Errorf("%s", err)
+			} else {
+				row.Status = "created"
+			}
+			if verbose {
+				fmt.Fprintf(os.Stderr, "[%d/%d] line %d: %s %s\n", i+1, len(file.Rows), row.Line, row.Status, row.ID)
+			}
+		}
+
+		summary := map[string]int{}
+		for _, row := range file.Rows {
+			summary[row.Status]++
+		}
+		result := importOutput{
+			File:    args[0],
+			Type:    t.Name,
+			DryRun:  dryRun,
+			Rows:    len(file.Rows),
+			Summary: summary,
+			Results: file.Rows,
+		}
+		if err := writeImportResults(resultsPath, file, t); err != nil {
+			output.ErrorDetail("writing results: "+err.Error(), result)
+			os.Exit(1)
+		}
+		result.ResultsFile = resultsPath
+
+		label := "EMAIL"
+		if t.Name == "licenses" {
+			label = "KEY"
+		}
+		rows := make([][]string, len(file.Rows))
+		for i, row := range file.Rows {
+			value := row.Fields["email"]
+			if t.Name == "licenses" {
+				value = row.Fields["key"]
+			}
+			rows[i] = []string{strconv.Itoa(row.Line), row.Status, row.ID, value, strings.Join(row.Errors, "; ")}
+		}
+
+		if summary["failed"] > 0 {
+			output.ErrorDetail(fmt.Sprintf("%d of %d rows failed; rerun to retry them", summary["failed"], len(file.Rows)), result)
+			os.Exit(1)
+		}
+		output.SuccessTable(result, []string{"LINE", "STATUS", "ID", label, "ERROR"}, rows)
+	},
+}
+
+// checkUserRows looks up each email in the account. Existing users and
+// emails repeated in the file are marked so they aren't created twice.
+func checkUserRows(client *api.Client, file *importer.File) error {
+	seen := map[string]*importer.Row{}
+	for _, row := range file.Rows {
+		if len(row.Errors) > 0 {
+			continue
+		}
+		email := strings.ToLower(row.Fields["email"])
+		if first, ok := seen[email]; ok {
+			row.Status = "duplicate"
+			row.First = first
+			continue
+		}
+		seen[email] = row
+
+		u, err := client.FindUserByEmail(email)
+		switch {
+		case errors.Is(err, api.ErrUserNotFound):
+		case err != nil:
+			return fmt.Errorf("looking up %s: %w", email, err)
+		default:
+			row.Status = "existing"
+			row.ID = u.ID
+		}
+	}
+	return nil
+}
+
+// checkLicenseRows checks that policies and owners exist, and marks rows
+// whose key is already in the account.
+func checkLicenseRows(client *api.Client, file *importer.File) error {
+	policies, err := listAll(client.ListPolicies, map[string]string{})
+	if err != nil {
+		return fmt.Errorf("listing policies: %w", err)
+	}
+	policyIDs := map[string]bool{}
+	for _, p := range policies {
+		policyIDs[p.ID] = true
+	}
+
+	keys := map[string]string{}
+	for _, row := range file.Rows {
+		if row.Fields["key"] != "" {
+			licenses, err := listAll(client.ListLicenses, map[string]string{})
+			if err != nil {
+				return fmt.Errorf("listing licenses: %w", err)
+			}
+			for _, l := range licenses {
+				keys[l.Key] = l.ID
+			}
+			break
+		}
+	}
+
+	owners := map[string]string{}
+	for _, row := range file.Rows {
+		if policy := row.Fields["policy"]; policy != "" && !policyIDs[policy] {
+			row.Errorf("policy %q not found", policy)
+		}
+		if owner := row.Fields["owner"]; owner != "" && (!strings.Contains(owner, "@") || importer.ValidEmail(owner)) {
+			id, ok := owners[strings.ToLower(owner)]
+			if !ok {
+				var u *api.User
+				if strings.Contains(owner, "@") {
+					u, err = client.FindUserByEmail(owner)
+				} else {
+					u, err = client.GetUser(owner)
+				}
+				var apiErr *api.APIError
+				switch {
+				case errors.Is(err, api.ErrUserNotFound) || errors.As(err, &apiErr) && apiErr.StatusCode == 404:
+				case err != nil:
+					return fmt.Errorf("looking up owner %s: %w", owner, err)
+				default:
+					id = u.ID
+				}
+				owners[strings.ToLower(owner)] = id
+			}
+			if id == "" {
+				row.Errorf("owner %q not found; import users first", owner)
+			}
+			row.OwnerID = id
+		}
+		if id, ok := keys[row.Fields["key"]]; ok && len(row.Errors) == 0 {
+			row.Status = "existing"
+			row.ID = id
+		}
+	}
+	return nil
+}
+
+func createUser(client *api.Client, row *importer.Row) error {
+	res, err := client.CreateResource("/users", "users", importer.Attributes(row), nil)
+	if err != nil {
+		return err
+	}
+	row.ID = res.ID
+	return nil
+}
+
+func createLicense(client *api.Client, row *importer.Row) error {
+	rels := map[string]api.RelationshipData{
+		"policy": {Type: "policies", ID: row.Fields["policy"]},
+	}
+	if row.OwnerID != "" {
+		rels["owner"] = api.RelationshipData{Type: "users", ID: row.OwnerID}
+	}
+	res, err := client.CreateResource("/licenses", "licenses", importer.Attributes(row), rels)
+	if err != nil {
+		return err
+	}
+	row.ID = res.ID
+	return nil
+}
+
+func writeImportResults(path string, file *importer.File, t importer.Type) error {
+	var buf bytes.Buffer
+	if err := file.WriteResults(&buf, t); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+func init() {
+	importCmd.Flags().String("type", "", "What the rows are: users or licenses")
+	importCmd.Flags().String("mapping", "", "YAML file mapping fields to CSV columns")
+	importCmd.Flags().String("results", "", "Results CSV (default <file>-results.csv)")
+	importCmd.Flags().Bool("dry-run", false, "Validate and show what would be created")
+	rootCmd.AddCommand(importCmd)
+}
@@ -3,10 +3,13 @@ package cmd
 import (
 	"fmt"
 	"math"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
 	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
 	"github.com/productivityenthusiast/keygen-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -47,33 +50,58 @@ var licensesListCmd = &cobra.Command{
 			params["page[number]"] = fmt.Sprintf("%d", v)
 		}
 
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			// ndjson and template print each page as it arrives unless the
+			// whole list has to be sorted first
+			stream := output.Streaming() && len(listOpts.Sort) == 0
+			var licenses []api.License
+			params["page[size]"] = "100"
+			for page := 1; ; page++ {
+				params["page[number]"] = fmt.Sprintf("%d", page)
+				batch, err := client.ListLicenses(params)
+				if err != nil {
+					output.Error(err.Error())
+					return
+				}
+				if stream {
+					printList(columns.Licenses, batch)
+				} else {
+					licenses = append(licenses, batch...)
+				}
+				if len(batch) < 100 {
+					break
+				}
+			}
+			if !stream {
+				printList(columns.Licenses, licenses)
+			}
+			return
+		}
+
 		licenses, err := client.ListLicenses(params)
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"ID", "KEY", "NAME", "STATUS", "EXPIRY", "OWNER_ID"}
-			rows := make([][]string, len(licenses))
-			for i, l := range licenses {
-				rows[i] = []string{l.ID, l.Key, l.Name, strings.ToUpper(l.Status), l.Expiry, l.OwnerID}
-			}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.SuccessList(licenses, len(licenses))
-		}
+		printList(columns.Licenses, licenses)
 	},
 }
 
 var licensesShowCmd = &cobra.Command{
 	Use:   "show [license-id]",
 	Short: "Show license details",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show a license's details. With --offline, the license is looked up by ID
+or key in the last 'keygen sync'.
+
+Examples:
+  keygen licenses show <license-id>
+  keygen licenses show <license-key> --offline`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{offlineAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := loadConfig()
-		client, err := auth.ResolveClient(cfg)
+		client, err := resolveReader(cfg)
 		if err != nil {
 			output.Error(err.Error())
 			return
@@ -92,71 +120,49 @@ var licensesShowCmd = &cobra.Command{
 var licensesStatusCmd = &cobra.Command{
 	Use:   "status [license-id]",
 	Short: "Check license status with validation",
-	Args:  cobra.ExactArgs(1),
+	Long: `Validate a license and summarize its machines, expiry and usage.
+
+With --offline, the license is looked up by ID or key in the last 'keygen
+sync' and validated locally: its status, its expiry against the current
+time and its machine count. Heartbeats and scopes are not checked.
+
+Examples:
+  keygen licenses status <license-id> --format table
+  keygen licenses status <license-key> --offline`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{offlineAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := loadConfig()
-		client, err := auth.ResolveClient(cfg)
+		client, err := resolveReader(cfg)
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
 
-		validation, license, err := client.ValidateLicense(args[0])
+		result, license, err := licenseStatus(client, args[0])
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
-
-		machines, _ := client.GetLicenseMachines(args[0])
-		machineCount := 0
-		componentCount := 0
-		if machines != nil {
-			machineCount = len(machines)
-			for _, m := range machines {
-				componentCount += len(m.Components)
-			}
-		}
-
-		daysRemaining := 0.0
-		if license != nil && license.Expiry != "" {
-			if t, err := time.Parse(time.RFC3339, license.Expiry); err == nil {
-				daysRemaining = math.Max(0, time.Until(t).Hours()/24)
-			}
-		}
-
-		result := map[string]interface{}{
-			"license_id":     args[0],
-			"valid":          validation.Valid,
-			"status":         "",
-			"detail":         validation.Detail,
-			"code":           validation.Code,
-			"machines":       machineCount,
-			"components":     componentCount,
-			"days_remaining": int(daysRemaining),
-		}
-
+		usage := ""
 		if license != nil {
-			result["status"] = license.Status
-			result["key"] = license.Key
-			result["name"] = license.Name
-			result["expiry"] = license.Expiry
-		}
-
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"LICENSE_ID", "VALID", "STATUS", "DAYS_LEFT", "MACHINES", "COMPONENTS"}
-			rows := [][]string{{
-				args[0],
-				fmt.Sprintf("%v", validation.Valid),
-				fmt.Sprintf("%v", result["status"]),
-				fmt.Sprintf("%d", int(daysRemaining)),
-				fmt.Sprintf("%d", machineCount),
-				fmt.Sprintf("%d", componentCount),
-			}}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.Success(result)
-		}
+			usage = formatUsage(license)
+		}
+		if result.UsageWarning != "" && !quiet {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", result.UsageWarning)
+		}
+
+		headers := []string{"LICENSE_ID", "VALID", "STATUS", "DAYS_LEFT", "MACHINES", "COMPONENTS", "USES"}
+		rows := [][]string{{
+			args[0],
+			fmt.Sprintf("%v", result.Valid),
+			result.Status,
+			fmt.Sprintf("%d", result.DaysRemaining),
+			fmt.Sprintf("%d", result.Machines),
+			fmt.Sprintf("%d", result.Components),
+			usage,
+		}}
+		output.SuccessTable(result, headers, rows)
 	},
 }
 
@@ -165,7 +171,7 @@ var licensesRenewCmd = &cobra.Command{
 	Short: "Renew a license",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := loadConfig()
+		cfg := loadConfigForWrite()
 		client, err := auth.ResolveClient(cfg)
 		if err != nil {
 			output.Error(err.Error())
@@ -185,13 +191,13 @@ var licensesRenewCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"license_id": renewed.ID,
-			"key":        renewed.Key,
-			"name":       renewed.Name,
-			"old_expiry": oldLicense.Expiry,
-			"new_expiry": renewed.Expiry,
-			"status":     renewed.Status,
+		output.Success(licenseRenewOutput{
+			LicenseID: renewed.ID,
+			Key:       renewed.Key,
+			Name:      renewed.Name,
+			OldExpiry: oldLicense.Expiry,
+			NewExpiry: renewed.Expiry,
+			Status:    renewed.Status,
 		})
 	},
 }
@@ -214,18 +220,10 @@ var licensesComponentsCmd = &cobra.Command{
 			return
 		}
 
-		type componentInfo struct {
-			ID          string `json:"id"`
-			Fingerprint string `json:"fingerprint"`
-			Name        string `json:"name"`
-			MachineID   string `json:"machine_id"`
-			MachineFP   string `json:"machine_fingerprint"`
-		}
-
-		var allComponents []componentInfo
+		var allComponents []licenseComponent
 		for _, m := range machines {
 			for _, c := range m.Components {
-				allComponents = append(allComponents, componentInfo{
+				allComponents = append(allComponents, licenseComponent{
 					ID:          c.ID,
 					Fingerprint: c.Fingerprint,
 					Name:        c.Name,
@@ -235,26 +233,30 @@ var licensesComponentsCmd = &cobra.Command{
 			}
 		}
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"ID", "FINGERPRINT", "NAME", "MACHINE_ID", "MACHINE_FP"}
-			rows := make([][]string, len(allComponents))
-			for i, c := range allComponents {
-				rows[i] = []string{c.ID, c.Fingerprint, c.Name, c.MachineID, c.MachineFP}
-			}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.SuccessList(allComponents, len(allComponents))
-		}
+		printList(licenseComponentColumns, allComponents)
 	},
 }
 
+// licenseComponent is a component listed by 'licenses components', with the
+// machine it belongs to.
+type licenseComponent struct {
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint"`
+	Name        string `json:"name"`
+	MachineID   string `json:"machine_id"`
+	MachineFP   string `json:"machine_fingerprint"`
+}
+
+var licenseComponentColumns = columns.NewSet([]string{"id", "fingerprint", "name", "machine_id", "machine_fingerprint"},
+	columns.Column[licenseComponent]{Name: "machine_fingerprint", Header: "MACHINE_FP", Value: func(c licenseComponent) interface{} { return c.MachineFP }},
+)
+
 var licensesUpdateCmd = &cobra.Command{
 	Use:   "update [license-id]",
 	Short: "Update license metadata (maxDevices, maxPrinters, maxServers)",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := loadConfig()
+		cfg := loadConfigForWrite()
 		client, err := auth.ResolveClient(cfg)
 		if err != nil {
 			output.Error(err.Error())
@@ -309,28 +311,406 @@ var licensesUpdateCmd = &cobra.Command{
 			return
 		}
 
-		result := map[string]interface{}{
-			"license_id": updated.ID,
-			"key":        updated.Key,
-			"name":       updated.Name,
-			"status":     updated.Status,
-			"metadata":   updated.Metadata,
+		result := licenseUpdateOutput{
+			LicenseID: updated.ID,
+			Key:       updated.Key,
+			Name:      updated.Name,
+			Status:    updated.Status,
+			Metadata:  updated.Metadata,
+		}
+
+		maxD := fmt.Sprintf("%v", updated.Metadata["maxDevices"])
+		maxP := fmt.Sprintf("%v", updated.Metadata["maxPrinters"])
+		maxS := fmt.Sprintf("%v", updated.Metadata["maxServers"])
+		headers := []string{"LICENSE_ID", "KEY", "NAME", "MAX_DEVICES", "MAX_PRINTERS", "MAX_SERVERS"}
+		rows := [][]string{{updated.ID, updated.Key, updated.Name, maxD, maxP, maxS}}
+		output.SuccessTable(result, headers, rows)
+	},
+}
+
+var licensesChangePolicyCmd = &cobra.Command{
+	Use:   "change-policy [license-id]",
+	Short: "Move a license to another policy",
+	Long: `Move a license to another policy, e.g. to upgrade a customer's tier.
+
+Warns when the new policy allows fewer machines than are currently
+activated on the license. Use --dry-run to preview the change.
+
+Examples:
+  keygen licenses change-policy <license-id> --policy <policy-id> --dry-run
+  keygen licenses change-policy <license-id> --policy <policy-id>`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		policyID, _ := cmd.Flags().GetString("policy")
+		if policyID == "" {
+			output.Error("--policy is required")
+			return
+		}
+
+		license, err := client.GetLicense(args[0])
+		if err != nil {
+			output.Error("failed to get license: " + err.Error())
+			return
+		}
+
+		policy, err := client.GetPolicy(policyID)
+		if err != nil {
+			output.Error("failed to get policy: " + err.Error())
+			return
+		}
+
+		after := licenseRelations(license)
+		after.PolicyID = policy.ID
+		if policy.ProductID != "" {
+			after.ProductID = policy.ProductID
+		}
+
+		warnings := machineLimitWarnings(client, license.ID, policy)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		applyLicenseChange(license, after, warnings, dryRun, func() (*api.License, error) {
+			return client.ChangeLicensePolicy(license.ID, policy.ID)
+		})
+	},
+}
+
+var licensesTransferCmd = &cobra.Command{
+	Use:   "transfer [license-id]",
+	Short: "Transfer a license to another product",
+	Long: `Transfer a license to another product.
+
+A license's product is determined by its policy, so the transfer moves the
+license to a policy of the target product. Pass --policy to choose one; it
+can be omitted when the product has exactly one policy.
+
+Examples:
+  keygen licenses transfer <license-id> --product <product-id> --dry-run
+  keygen licenses transfer <license-id> --product <product-id> --policy <policy-id>`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		productID, _ := cmd.Flags().GetString("product")
+		if productID == "" {
+			output.Error("--product is required")
+			return
+		}
+
+		license, err := client.GetLicense(args[0])
+		if err != nil {
+			output.Error("failed to get license: " + err.Error())
+			return
+		}
+
+		var policy *api.Policy
+		if policyID, _ := cmd.Flags().GetString("policy"); policyID != "" {
+			policy, err = client.GetPolicy(policyID)
+			if err != nil {
+				output.Error("failed to get policy: " + err.Error())
+				return
+			}
+			if policy.ProductID != "" && policy.ProductID != productID {
+				output.Error(fmt.Sprintf("policy %s belongs to product %s, not %s", policy.ID, policy.ProductID, productID))
+				return
+			}
+		} else {
+			policies, err := client.ListPolicies(map[string]string{"product": productID, "page[size]": "100"})
+			if err != nil {
+				output.Error("failed to list policies: " + err.Error())
+				return
+			}
+			if len(policies) != 1 {
+				output.Error(fmt.Sprintf("product %s has %d policies; choose one with --policy", productID, len(policies)))
+				return
+			}
+			policy = &policies[0]
+		}
+
+		after := licenseRelations(license)
+		after.PolicyID = policy.ID
+		after.ProductID = productID
+
+		warnings := machineLimitWarnings(client, license.ID, policy)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		applyLicenseChange(license, after, warnings, dryRun, func() (*api.License, error) {
+			return client.ChangeLicensePolicy(license.ID, policy.ID)
+		})
+	},
+}
+
+var licensesSetOwnerCmd = &cobra.Command{
+	Use:   "set-owner [license-id]",
+	Short: "Reassign a license to another user",
+	Long: `Reassign a license to another user.
+
+Examples:
+  keygen licenses set-owner <license-id> --user alice@example.com --dry-run
+  keygen licenses set-owner <license-id> --user <user-id>`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		identifier, _ := cmd.Flags().GetString("user")
+		if identifier == "" {
+			output.Error("--user is required")
+			return
 		}
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			maxD := fmt.Sprintf("%v", updated.Metadata["maxDevices"])
-			maxP := fmt.Sprintf("%v", updated.Metadata["maxPrinters"])
-			maxS := fmt.Sprintf("%v", updated.Metadata["maxServers"])
-			headers := []string{"LICENSE_ID", "KEY", "NAME", "MAX_DEVICES", "MAX_PRINTERS", "MAX_SERVERS"}
-			rows := [][]string{{updated.ID, updated.Key, updated.Name, maxD, maxP, maxS}}
-			output.FormatTable(f, headers, rows)
+		var user *api.User
+		if strings.Contains(identifier, "@") {
+			user, err = client.FindUserByEmail(identifier)
 		} else {
-			output.Success(result)
+			user, err = client.GetUser(identifier)
+		}
+		if err != nil {
+			output.Error("user not found: " + err.Error())
+			return
 		}
+
+		license, err := client.GetLicense(args[0])
+		if err != nil {
+			output.Error("failed to get license: " + err.Error())
+			return
+		}
+
+		after := licenseRelations(license)
+		after.OwnerID = user.ID
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		applyLicenseChange(license, after, nil, dryRun, func() (*api.License, error) {
+			return client.ChangeLicenseOwner(license.ID, user.ID)
+		})
 	},
 }
 
+var licensesUsageCmd = &cobra.Command{
+	Use:   "usage [license-id] [increment|decrement|reset]",
+	Short: "Change a license's usage counter",
+	Long: `Increment, decrement or reset a license's "uses" counter, as used by
+metered licensing.
+
+Examples:
+  keygen licenses usage <license-id> increment
+  keygen licenses usage <license-id> increment --by 5
+  keygen licenses usage <license-id> decrement --by 2
+  keygen licenses usage <license-id> reset`,
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: []string{"increment", "decrement", "reset"},
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		licenseID := args[0]
+		action := strings.ToLower(args[1])
+		by, _ := cmd.Flags().GetInt("by")
+		switch action {
+		case "increment", "decrement":
+			if by < 1 {
+				output.Error("--by must be at least 1")
+				return
+			}
+		case "reset":
+		default:
+			output.Error(fmt.Sprintf("unknown usage action %q (use increment, decrement or reset)", args[1]))
+			return
+		}
+
+		before, err := client.GetLicense(licenseID)
+		if err != nil {
+			output.Error("failed to get license: " + err.Error())
+			return
+		}
+
+		var updated *api.License
+		switch action {
+		case "increment":
+			updated, err = client.IncrementLicenseUsage(licenseID, by)
+		case "decrement":
+			updated, err = client.DecrementLicenseUsage(licenseID, by)
+		case "reset":
+			updated, err = client.ResetLicenseUsage(licenseID)
+		}
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		result := licenseUsageOutput{
+			LicenseID:    updated.ID,
+			Key:          updated.Key,
+			Action:       action,
+			UsesBefore:   before.Uses,
+			Uses:         updated.Uses,
+			MaxUses:      updated.MaxUses,
+			UsageWarning: usageWarning(updated),
+		}
+
+		headers := []string{"LICENSE_ID", "KEY", "ACTION", "BEFORE", "USES"}
+		rows := [][]string{{updated.ID, updated.Key, action, fmt.Sprintf("%d", before.Uses), formatUsage(updated)}}
+		output.SuccessTable(result, headers, rows)
+	},
+}
+
+// usageWarnRatio is the share of maxUses at which usage is flagged.
+const usageWarnRatio = 0.9
+
+// licenseStatus validates a license and summarizes its machines, expiry and
+// usage, as shown by 'licenses status'.
+func licenseStatus(client accountReader, id string) (*licenseStatusOutput, *api.License, error) {
+	validation, license, err := client.ValidateLicense(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	machines, _ := client.GetLicenseMachines(id)
+	machineCount := 0
+	componentCount := 0
+	if machines != nil {
+		machineCount = len(machines)
+		for _, m := range machines {
+			componentCount += len(m.Components)
+		}
+	}
+
+	daysRemaining := 0.0
+	if license != nil && license.Expiry != "" {
+		if t, err := time.Parse(time.RFC3339, license.Expiry); err == nil {
+			daysRemaining = math.Max(0, time.Until(t).Hours()/24)
+		}
+	}
+
+	result := &licenseStatusOutput{
+		LicenseID:     id,
+		Valid:         validation.Valid,
+		Detail:        validation.Detail,
+		Code:          validation.Code,
+		Machines:      machineCount,
+		Components:    componentCount,
+		DaysRemaining: int(daysRemaining),
+	}
+
+	if license != nil {
+		result.Status = license.Status
+		result.Key = license.Key
+		result.Name = license.Name
+		result.Expiry = license.Expiry
+		result.Uses = license.Uses
+		result.MaxUses = license.MaxUses
+		result.LastValidated = license.LastValidated
+		result.UsageWarning = usageWarning(license)
+	}
+
+	return result, license, nil
+}
+
+// usageWarning returns a warning when a license's usage is at or near its
+// maxUses limit, or "" when it is unlimited or well below it.
+func usageWarning(l *api.License) string {
+	if l.MaxUses <= 0 {
+		return ""
+	}
+	if l.Uses >= l.MaxUses {
+		return fmt.Sprintf("license %s has reached its usage limit (%d/%d)", l.ID, l.Uses, l.MaxUses)
+	}
+	if float64(l.Uses) >= usageWarnRatio*float64(l.MaxUses) {
+		return fmt.Sprintf("license %s is approaching its usage limit (%d/%d)", l.ID, l.Uses, l.MaxUses)
+	}
+	return ""
+}
+
+// formatUsage renders uses as "n/max", or just "n" when unlimited.
+func formatUsage(l *api.License) string {
+	if l.MaxUses > 0 {
+		return fmt.Sprintf("%d/%d", l.Uses, l.MaxUses)
+	}
+	return fmt.Sprintf("%d", l.Uses)
+}
+
+// licenseRelationIDs is the before/after view shown for relationship changes.
+type licenseRelationIDs struct {
+	PolicyID  string `json:"policy_id"`
+	ProductID string `json:"product_id"`
+	OwnerID   string `json:"owner_id"`
+}
+
+func licenseRelations(l *api.License) licenseRelationIDs {
+	return licenseRelationIDs{PolicyID: l.PolicyID, ProductID: l.ProductID, OwnerID: l.OwnerID}
+}
+
+// machineLimitWarnings warns when a policy allows fewer machines than the
+// license currently has activated.
+func machineLimitWarnings(client *api.Client, licenseID string, policy *api.Policy) []string {
+	if policy.MaxMachines <= 0 {
+		return nil
+	}
+	machines, err := client.GetLicenseMachines(licenseID)
+	if err != nil {
+		return []string{"could not count machines: " + err.Error()}
+	}
+	if len(machines) > policy.MaxMachines {
+		return []string{fmt.Sprintf("policy %s allows %d machines but the license has %d activated", policy.ID, policy.MaxMachines, len(machines))}
+	}
+	return nil
+}
+
+// applyLicenseChange prints the before/after relationship IDs and, unless
+// dryRun is set, performs the change and reports the server's result.
+func applyLicenseChange(license *api.License, after licenseRelationIDs, warnings []string, dryRun bool, apply func() (*api.License, error)) {
+	before := licenseRelations(license)
+
+	if !quiet {
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+	}
+
+	if !dryRun {
+		updated, err := apply()
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		after = licenseRelations(updated)
+	}
+
+	result := licenseChangeOutput{
+		LicenseID: license.ID,
+		Key:       license.Key,
+		DryRun:    dryRun,
+		Before:    before,
+		After:     after,
+		Warnings:  warnings,
+	}
+
+	headers := []string{"FIELD", "BEFORE", "AFTER"}
+	rows := [][]string{
+		{"POLICY_ID", before.PolicyID, after.PolicyID},
+		{"PRODUCT_ID", before.ProductID, after.ProductID},
+		{"OWNER_ID", before.OwnerID, after.OwnerID},
+	}
+	output.SuccessTable(result, headers, rows)
+}
+
 func init() {
 	licensesListCmd.Flags().String("user", "", "Filter by user ID")
 	licensesListCmd.Flags().String("product", "", "Filter by product ID")
@@ -338,16 +718,33 @@ func init() {
 	licensesListCmd.Flags().String("status", "", "Filter by status")
 	licensesListCmd.Flags().Int("limit", 10, "Results per page")
 	licensesListCmd.Flags().Int("page", 1, "Page number")
+	licensesListCmd.Flags().Bool("all", false, "Fetch every page (ignores --limit and --page)")
 
 	licensesUpdateCmd.Flags().Int("max-devices", 0, "Maximum number of devices")
 	licensesUpdateCmd.Flags().Int("max-printers", 0, "Maximum number of printers")
 	licensesUpdateCmd.Flags().Int("max-servers", 0, "Maximum number of servers")
 
+	licensesChangePolicyCmd.Flags().String("policy", "", "New policy ID (required)")
+	licensesChangePolicyCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+
+	licensesTransferCmd.Flags().String("product", "", "Target product ID (required)")
+	licensesTransferCmd.Flags().String("policy", "", "Policy of the target product to use")
+	licensesTransferCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+
+	licensesSetOwnerCmd.Flags().String("user", "", "New owner's user ID or email (required)")
+	licensesSetOwnerCmd.Flags().Bool("dry-run", false, "Show the change without applying it")
+
+	licensesUsageCmd.Flags().Int("by", 1, "Amount to increment or decrement by")
+
 	licensesCmd.AddCommand(licensesListCmd)
 	licensesCmd.AddCommand(licensesShowCmd)
 	licensesCmd.AddCommand(licensesStatusCmd)
 	licensesCmd.AddCommand(licensesRenewCmd)
 	licensesCmd.AddCommand(licensesComponentsCmd)
 	licensesCmd.AddCommand(licensesUpdateCmd)
+	licensesCmd.AddCommand(licensesChangePolicyCmd)
+	licensesCmd.AddCommand(licensesTransferCmd)
+	licensesCmd.AddCommand(licensesSetOwnerCmd)
+	licensesCmd.AddCommand(licensesUsageCmd)
 	rootCmd.AddCommand(licensesCmd)
 }
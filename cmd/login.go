@@ -49,11 +49,11 @@ var loginTokenCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message":    "Login successful",
-			"profile":    cfg.ProfileName,
-			"account_id": cfg.AccountID,
-			"base_url":   cfg.BaseURL,
+		output.Success(loginTokenOutput{
+			Message:   "Login successful",
+			Profile:   cfg.ProfileName,
+			AccountID: cfg.AccountID,
+			BaseURL:   cfg.BaseURL,
 		})
 	},
 }
@@ -98,12 +98,12 @@ var loginPasswordCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message":    "Login successful",
-			"profile":    cfg.ProfileName,
-			"token_id":   token.ID,
-			"expiry":     token.Expiry,
-			"account_id": cfg.AccountID,
+		output.Success(loginPasswordOutput{
+			Message:   "Login successful",
+			Profile:   cfg.ProfileName,
+			TokenID:   token.ID,
+			Expiry:    token.Expiry,
+			AccountID: cfg.AccountID,
 		})
 	},
 }
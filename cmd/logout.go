@@ -17,15 +17,15 @@ Use --profile to logout from a specific profile:
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := loadConfig()
 		if err := cfg.Clear(); err != nil {
-			output.Success(map[string]string{
-				"message": "No saved config to clear",
-				"profile": cfg.ProfileName,
+			output.Success(messageOutput{
+				Message: "No saved config to clear",
+				Profile: cfg.ProfileName,
 			})
 			return
 		}
-		output.Success(map[string]string{
-			"message": fmt.Sprintf("Logged out — profile %q removed", cfg.ProfileName),
-			"profile": cfg.ProfileName,
+		output.Success(messageOutput{
+			Message: fmt.Sprintf("Logged out — profile %q removed", cfg.ProfileName),
+			Profile: cfg.ProfileName,
 		})
 	},
 }
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Browse request and event logs for audits",
+	Long: `Browse Keygen's request and event logs.
+
+Date flags accept a date (2024-05-01), an RFC 3339 timestamp or a
+relative duration such as 24h or 7d. Use --follow to keep polling for new
+entries; JSON output is then streamed one entry per line.
+
+Examples:
+  keygen logs requests --license <id> --since 30d --format csv > audit.csv
+  keygen logs requests --ip 203.0.113.7 --status 422
+  keygen logs events --machine <id> --event machine.deleted
+  keygen logs events --user alice@example.com --follow`,
+}
+
+var logsRequestsCmd = &cobra.Command{
+	Use:   "requests",
+	Short: "List API request logs",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := logsConfig(cmd)
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params, err := logFilterParams(cmd, client, "requestor")
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		for _, name := range []string{"ip", "status", "method"} {
+			if v, _ := cmd.Flags().GetString(name); v != "" {
+				params[name] = v
+			}
+		}
+		ip, _ := cmd.Flags().GetString("ip")
+		status, _ := cmd.Flags().GetString("status")
+		method, _ := cmd.Flags().GetString("method")
+
+		fetch := func(p map[string]string) ([]logRecord, error) {
+			logs, err := client.ListRequestLogs(p)
+			if err != nil {
+				return nil, err
+			}
+			var records []logRecord
+			for _, l := range logs {
+				// Re-check server-side filters in case the server ignores them
+				if (ip != "" && l.IP != ip) || (status != "" && l.Status != status) || (method != "" && !strings.EqualFold(l.Method, method)) {
+					continue
+				}
+				records = append(records, logRecord{
+					id:      l.ID,
+					created: l.Created,
+					row:     []string{l.Created, l.Method, l.URL, l.Status, l.IP, l.RequestorType + "/" + l.RequestorID, l.ResourceType + "/" + l.ResourceID},
+					value:   l,
+				})
+			}
+			return records, nil
+		}
+
+		headers := []string{"CREATED", "METHOD", "URL", "STATUS", "IP", "REQUESTOR", "RESOURCE"}
+		runLogs(cmd, params, headers, fetch)
+	},
+}
+
+var logsEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List event logs",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := logsConfig(cmd)
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params, err := logFilterParams(cmd, client, "whodunnit")
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		event, _ := cmd.Flags().GetString("event")
+		if event != "" {
+			params["event"] = event
+		}
+
+		fetch := func(p map[string]string) ([]logRecord, error) {
+			logs, err := client.ListEventLogs(p)
+			if err != nil {
+				return nil, err
+			}
+			var records []logRecord
+			for _, l := range logs {
+				if event != "" && !matchesEventFilter(l.Event, splitList(event)) {
+					continue
+				}
+				records = append(records, logRecord{
+					id:      l.ID,
+					created: l.Created,
+					row:     []string{l.Created, l.Event, l.WhodunnitType + "/" + l.WhodunnitID, l.ResourceType + "/" + l.ResourceID, l.RequestLogID},
+					value:   l,
+				})
+			}
+			return records, nil
+		}
+
+		headers := []string{"CREATED", "EVENT", "WHODUNNIT", "RESOURCE", "REQUEST_LOG_ID"}
+		runLogs(cmd, params, headers, fetch)
+	},
+}
+
+// logRecord is a format-independent view of a request or event log entry.
+type logRecord struct {
+	id      string
+	created string
+	row     []string
+	value   interface{}
+}
+
+// logFilterParams builds the date, resource and actor filters shared by both
+// log types. actor is "requestor" for request logs and "whodunnit" for events.
+func logFilterParams(cmd *cobra.Command, client *api.Client, actor string) (map[string]string, error) {
+	params := make(map[string]string)
+
+	if v, _ := cmd.Flags().GetString("since"); v != "" {
+		t, err := parseTimeFlag(v)
+		if err != nil {
+			return nil, fmt.Errorf("--since: %w", err)
+		}
+		params["date[start]"] = t.Format("2006-01-02")
+	}
+	if v, _ := cmd.Flags().GetString("until"); v != "" {
+		t, err := parseTimeFlag(v)
+		if err != nil {
+			return nil, fmt.Errorf("--until: %w", err)
+		}
+		params["date[end]"] = t.Format("2006-01-02")
+	}
+	// Keygen requires both ends of a date range
+	if params["date[start]"] != "" && params["date[end]"] == "" {
+		params["date[end]"] = time.Now().UTC().Format("2006-01-02")
+	}
+	if params["date[end]"] != "" && params["date[start]"] == "" {
+		params["date[start]"] = "1970-01-01"
+	}
+
+	license, _ := cmd.Flags().GetString("license")
+	machine, _ := cmd.Flags().GetString("machine")
+	if license != "" && machine != "" {
+		return nil, fmt.Errorf("use only one of --license and --machine")
+	}
+	if license != "" {
+		params["resource[type]"] = "licenses"
+		params["resource[id]"] = license
+	}
+	if machine != "" {
+		params["resource[type]"] = "machines"
+		params["resource[id]"] = machine
+	}
+
+	if v, _ := cmd.Flags().GetString("user"); v != "" {
+		userID := v
+		if strings.Contains(v, "@") {
+			u, err := client.FindUserByEmail(v)
+			if err != nil {
+				return nil, err
+			}
+			userID = u.ID
+		}
+		params[actor+"[type]"] = "users"
+		params[actor+"[id]"] = userID
+	}
+
+	if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+		params["page[size]"] = fmt.Sprintf("%d", v)
+	}
+	if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+		params["page[number]"] = fmt.Sprintf("%d", v)
+	}
+
+	return params, nil
+}
+
+// logsConfig loads the profile. --follow polls the same newest page, so its
+// reads must never be answered from the cache.
+func logsConfig(cmd *cobra.Command) *config.Config {
+	cfg := loadConfig()
+	if follow, _ := cmd.Flags().GetBool("follow"); follow {
+		cfg.NoCache = true
+	}
+	return cfg
+}
+
+// runLogs prints one page of logs, or with --follow keeps polling and prints
+// entries newer than the last one seen.
+func runLogs(cmd *cobra.Command, params map[string]string, headers []string, fetch func(map[string]string) ([]logRecord, error)) {
+	records, err := fetch(params)
+	if err != nil {
+		output.Error(err.Error())
+		return
+	}
+
+	f := getFormat()
+	follow, _ := cmd.Flags().GetBool("follow")
+	if !follow {
+		rows := make([][]string, len(records))
+		values := make([]interface{}, len(records))
+		for i, r := range records {
+			rows[i] = r.row
+			values[i] = r.value
+		}
+		output.SuccessListTable(values, len(values), headers, rows)
+		return
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+
+	// Always poll the newest page while following
+	params["page[number]"] = "1"
+	params["page[size]"] = "100"
+
+	var cursor logCursor
+	first := true
+	for {
+		batch := cursor.advance(records)
+		if len(batch) > 0 || first {
+			printLogBatch(f, headers, batch, first)
+			first = false
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+
+		records, err = fetch(params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			records = nil
+		}
+	}
+}
+
+// logCursor tracks the newest entry already printed while following.
+type logCursor struct {
+	created string
+	seen    map[string]bool
+}
+
+// advance returns the records newer than the cursor in chronological order
+// and moves the cursor past them.
+func (c *logCursor) advance(records []logRecord) []logRecord {
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+
+	var fresh []logRecord
+	for _, r := range records {
+		if r.created < c.created || c.seen[r.id] {
+			continue
+		}
+		fresh = append(fresh, r)
+	}
+
+	sort.SliceStable(fresh, func(i, j int) bool { return fresh[i].created < fresh[j].created })
+
+	for _, r := range fresh {
+		if r.created > c.created {
+			c.created = r.created
+			c.seen = make(map[string]bool)
+		}
+		c.seen[r.id] = true
+	}
+
+	return fresh
+}
+
+func printLogBatch(f string, headers []string, batch []logRecord, first bool) {
+	rows := make([][]string, len(batch))
+	for i, r := range batch {
+		rows[i] = r.row
+	}
+
+	switch f {
+	case "table", "markdown", "html":
+		if len(rows) > 0 {
+			output.FormatTable(f, headers, rows)
+		}
+	case "csv":
+		if first {
+			output.CSV(headers, rows)
+		} else {
+			output.CSVRows(rows)
+		}
+	default:
+		for _, r := range batch {
+			output.Item(r.value)
+		}
+	}
+}
+
+// parseTimeFlag parses an absolute date/timestamp or a duration ago, e.g.
+// "2024-05-01", "2024-05-01T10:00:00Z", "36h" or "7d".
+func parseTimeFlag(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	d, err := parseDays(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date, timestamp or duration like 7d: %q", s)
+	}
+	return time.Now().UTC().Add(-d), nil
+}
+
+// parseDays parses a duration that may use a "d" suffix for days.
+func parseDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{logsRequestsCmd, logsEventsCmd} {
+		c.Flags().String("since", "", "Start of the date range (date, timestamp, or duration ago like 7d)")
+		c.Flags().String("until", "", "End of the date range")
+		c.Flags().String("license", "", "Filter by license ID")
+		c.Flags().String("machine", "", "Filter by machine ID")
+		c.Flags().String("user", "", "Filter by acting user ID or email")
+		c.Flags().Int("limit", 25, "Results per page")
+		c.Flags().Int("page", 1, "Page number")
+		c.Flags().Bool("follow", false, "Keep polling for new entries")
+		c.Flags().Duration("interval", 5*time.Second, "Polling interval for --follow")
+	}
+
+	logsRequestsCmd.Flags().String("ip", "", "Filter by client IP")
+	logsRequestsCmd.Flags().String("status", "", "Filter by HTTP status code")
+	logsRequestsCmd.Flags().String("method", "", "Filter by HTTP method")
+
+	logsEventsCmd.Flags().String("event", "", "Filter by event type, e.g. license.validation.failed")
+
+	logsCmd.AddCommand(logsRequestsCmd)
+	logsCmd.AddCommand(logsEventsCmd)
+	rootCmd.AddCommand(logsCmd)
+}
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var machinesCmd = &cobra.Command{
+	Use:   "machines",
+	Short: "Manage machines",
+}
+
+var machinesStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "Find machines with a dead or stale heartbeat",
+	Long: `List machines whose heartbeat is dead, or whose last heartbeat is
+older than --older-than. Machines that never sent a heartbeat are included
+when they were created before the cutoff and require heartbeats.
+
+With --force the listed machines are deactivated, freeing their seats on
+floating licenses.
+
+Examples:
+  keygen machines stale --older-than 7d
+  keygen machines stale --older-than 30d --license <id> --format table
+  keygen machines stale --older-than 7d --force
+  keygen machines stale --older-than 7d --where 'license_id=<id>' --force`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		age, err := parseDays(olderThan)
+		if err != nil {
+			output.Error("--older-than: " + err.Error())
+			return
+		}
+		cutoff := time.Now().Add(-age)
+
+		params := map[string]string{}
+		if v, _ := cmd.Flags().GetString("license"); v != "" {
+			params["license"] = v
+		}
+		if v, _ := cmd.Flags().GetString("product"); v != "" {
+			params["product"] = v
+		}
+
+		stale, err := findStaleMachines(client, params, cutoff)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		// Narrow with --where before anything is deactivated
+		fields := []string{"id", "fingerprint", "name", "heartbeat", "last_heartbeat", "license_id", "reason"}
+		force, _ := cmd.Flags().GetBool("force")
+		if force {
+			fields = append(fields, "deactivated")
+		}
+		set := columns.NewSet(fields,
+			columns.Column[staleMachine]{Name: "heartbeat", Header: "HEARTBEAT", Value: func(m staleMachine) interface{} { return strings.ToUpper(m.HeartbeatStatus) }},
+			columns.Column[staleMachine]{Name: "deactivated", Header: "DEACTIVATED", Value: func(m staleMachine) interface{} { return m.Deactivated }},
+		)
+		stale, err = set.Filter(stale, listOpts)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if force {
+			for i := range stale {
+				if err := client.DeactivateMachine(stale[i].ID); err != nil {
+					stale[i].Error = err.Error()
+				} else {
+					stale[i].Deactivated = true
+				}
+			}
+		}
+
+		if !force {
+			res, err := set.Apply(stale, listOpts)
+			if err != nil {
+				output.Error(err.Error())
+				return
+			}
+			output.SuccessTable(machinesStalePreview{
+				OlderThan: olderThan,
+				Count:     len(res.Items),
+				Machines:  res.Items,
+				Confirm:   "use --force to deactivate these machines",
+			}, res.Headers, res.Rows)
+			return
+		}
+
+		printList(set, stale)
+	},
+}
+
+var machinesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List machines",
+	Long: `List machines.
+
+Examples:
+  keygen machines list --license <id> --format table
+  keygen machines list --where 'heartbeat=DEAD' --fields id,fingerprint,last_heartbeat`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		for _, f := range []string{"license", "user", "product", "fingerprint"} {
+			if v, _ := cmd.Flags().GetString(f); v != "" {
+				params[f] = v
+			}
+		}
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		machines, err := client.ListMachines(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Machines, machines)
+	},
+}
+
+var machinesActivateCmd = &cobra.Command{
+	Use:   "activate",
+	Short: "Activate a machine for a license",
+	Long: `Activate a machine for a license, e.g. from a provisioning script.
+
+Authenticates with the profile's admin token by default, or as the license
+itself with --license-key or --license-token. --license accepts a license ID
+or key and may be omitted when --license-key is given.
+
+--components points to a JSON file with an array of components:
+  [{"fingerprint": "...", "name": "..."}]
+
+Activation is idempotent: if the fingerprint is already activated on the
+license, the existing machine is reported instead of failing. The result
+includes the license validated against the fingerprint.
+
+Examples:
+  keygen machines activate --license <id> --fingerprint <fp> --name kiosk-01 --profile prod
+  keygen machines activate --license-key ABCD-1234 --fingerprint <fp> --platform linux --hostname kiosk-01 --cores 4 --profile prod
+  keygen machines activate --license <id> --fingerprint <fp> --components components.json --profile prod`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+
+		licenseKey, _ := cmd.Flags().GetString("license-key")
+		licenseToken, _ := cmd.Flags().GetString("license-token")
+
+		var client *api.Client
+		var err error
+		if licenseKey != "" || licenseToken != "" {
+			client, err = auth.ResolveLicenseClient(cfg, licenseKey, licenseToken)
+		} else {
+			client, err = auth.ResolveClient(cfg)
+		}
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		licenseRef, _ := cmd.Flags().GetString("license")
+		if licenseRef == "" {
+			licenseRef = licenseKey
+		}
+		fingerprint, _ := cmd.Flags().GetString("fingerprint")
+		if licenseRef == "" || fingerprint == "" {
+			output.Error("--license (or --license-key) and --fingerprint are required")
+			return
+		}
+
+		var components []api.Component
+		if path, _ := cmd.Flags().GetString("components"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				output.Error(err.Error())
+				return
+			}
+			if err := json.Unmarshal(data, &components); err != nil {
+				output.Error("parsing components file: " + err.Error())
+				return
+			}
+			for _, c := range components {
+				if c.Fingerprint == "" {
+					output.Error("every component in the components file needs a fingerprint")
+					return
+				}
+			}
+		}
+
+		// Keygen accepts a license ID or key in the path
+		license, err := client.GetLicense(url.PathEscape(licenseRef))
+		if err != nil {
+			output.Error("failed to get license: " + err.Error())
+			return
+		}
+
+		machines, err := client.ListMachines(map[string]string{"license": license.ID, "fingerprint": fingerprint})
+		if err != nil {
+			output.Error("failed to look up existing machines: " + err.Error())
+			return
+		}
+
+		var machine *api.Machine
+		alreadyActivated := false
+		for i := range machines {
+			if machines[i].Fingerprint == fingerprint {
+				machine = &machines[i]
+				alreadyActivated = true
+				break
+			}
+		}
+
+		if machine == nil {
+			attrs := map[string]interface{}{"fingerprint": fingerprint}
+			for _, name := range []string{"name", "platform", "hostname"} {
+				if v, _ := cmd.Flags().GetString(name); v != "" {
+					attrs[name] = v
+				}
+			}
+			if v, _ := cmd.Flags().GetInt("cores"); v > 0 {
+				attrs["cores"] = v
+			}
+
+			machine, err = client.ActivateMachine(license.ID, attrs, components)
+			if err != nil {
+				output.Error("activation failed: " + err.Error())
+				return
+			}
+		}
+
+		result := machineActivateOutput{
+			LicenseID:        license.ID,
+			Activated:        !alreadyActivated,
+			AlreadyActivated: alreadyActivated,
+			Machine:          machine,
+		}
+
+		validation, _, err := client.ValidateLicenseFingerprint(license.ID, fingerprint)
+		if err != nil {
+			result.ValidationError = err.Error()
+		} else {
+			result.Validation = validation
+		}
+
+		valid, code := "", ""
+		if validation != nil {
+			valid = fmt.Sprintf("%v", validation.Valid)
+			code = validation.Code
+		}
+		headers := []string{"MACHINE_ID", "FINGERPRINT", "LICENSE_ID", "ACTIVATED", "COMPONENTS", "VALID", "CODE"}
+		rows := [][]string{{machine.ID, machine.Fingerprint, license.ID, fmt.Sprintf("%v", !alreadyActivated), fmt.Sprintf("%d", len(machine.Components)), valid, code}}
+		output.SuccessTable(result, headers, rows)
+	},
+}
+
+// staleMachine is a machine flagged by 'machines stale' and, with --force,
+// the outcome of deactivating it.
+type staleMachine struct {
+	api.Machine
+	Reason      string `json:"reason"`
+	Deactivated bool   `json:"deactivated,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// findStaleMachines pages through the machines matching params and returns
+// those whose heartbeat is dead or older than cutoff.
+func findStaleMachines(client *api.Client, params map[string]string, cutoff time.Time) ([]staleMachine, error) {
+	params["page[size]"] = "100"
+	stale := []staleMachine{}
+	for page := 1; ; page++ {
+		params["page[number]"] = fmt.Sprintf("%d", page)
+		machines, err := client.ListMachines(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range machines {
+			if reason := staleReason(m, cutoff); reason != "" {
+				stale = append(stale, staleMachine{Machine: m, Reason: reason})
+			}
+		}
+		if len(machines) < 100 {
+			return stale, nil
+		}
+	}
+}
+
+// staleReason explains why a machine counts as stale, or returns "".
+func staleReason(m api.Machine, cutoff time.Time) string {
+	if strings.EqualFold(m.HeartbeatStatus, "DEAD") {
+		return "heartbeat dead"
+	}
+	if m.LastHeartbeat != "" {
+		if t, err := time.Parse(time.RFC3339, m.LastHeartbeat); err == nil && t.Before(cutoff) {
+			return "last heartbeat " + t.Format("2006-01-02")
+		}
+		return ""
+	}
+	if m.RequireHeartbeat {
+		if t, err := time.Parse(time.RFC3339, m.Created); err == nil && t.Before(cutoff) {
+			return "no heartbeat since " + t.Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+func init() {
+	machinesListCmd.Flags().String("license", "", "Filter by license ID")
+	machinesListCmd.Flags().String("user", "", "Filter by user ID")
+	machinesListCmd.Flags().String("product", "", "Filter by product ID")
+	machinesListCmd.Flags().String("fingerprint", "", "Filter by fingerprint")
+	machinesListCmd.Flags().Int("limit", 10, "Results per page")
+	machinesListCmd.Flags().Int("page", 1, "Page number")
+
+	machinesStaleCmd.Flags().String("older-than", "7d", "Heartbeat age after which a machine is stale (e.g. 36h, 7d)")
+	machinesStaleCmd.Flags().String("license", "", "Only check machines for this license ID")
+	machinesStaleCmd.Flags().String("product", "", "Only check machines for this product ID")
+	machinesStaleCmd.Flags().Bool("force", false, "Deactivate the stale machines")
+
+	machinesActivateCmd.Flags().String("license", "", "License ID or key")
+	machinesActivateCmd.Flags().String("license-key", "", "Authenticate as the license with its key")
+	machinesActivateCmd.Flags().String("license-token", "", "Authenticate with a license token")
+	machinesActivateCmd.Flags().String("fingerprint", "", "Machine fingerprint (required)")
+	machinesActivateCmd.Flags().String("name", "", "Machine name")
+	machinesActivateCmd.Flags().String("platform", "", "Machine platform, e.g. linux")
+	machinesActivateCmd.Flags().String("hostname", "", "Machine hostname")
+	machinesActivateCmd.Flags().Int("cores", 0, "Number of CPU cores")
+	machinesActivateCmd.Flags().String("components", "", "JSON file with components to register")
+
+	machinesCmd.AddCommand(machinesListCmd)
+	machinesCmd.AddCommand(machinesActivateCmd)
+	machinesCmd.AddCommand(machinesStaleCmd)
+	rootCmd.AddCommand(machinesCmd)
+}
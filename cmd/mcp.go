@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/mcp"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Model Context Protocol server for AI assistants",
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve license, machine, component, user and status tools over stdio",
+	Long: `Run a Model Context Protocol server on stdin/stdout so AI assistants can
+call keygen operations as typed tools instead of shelling out.
+
+Tools that delete, suspend or prune are disabled unless the profile allows
+them:
+  keygen profile edit prod --allow-destructive
+
+Configure your assistant to launch the server, e.g.:
+  {"command": "keygen", "args": ["mcp", "serve", "--profile", "prod"]}
+
+Examples:
+  keygen mcp serve --profile prod
+  keygen mcp serve --profile testing --base-url http://localhost:3000`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		// A long-running server must not answer from a stale cache
+		cfg.NoCache = true
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		server := mcp.NewServer("keygen", Version, mcpTools(client, cfg), cfg.AllowDestructive)
+		if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+			exitError(err.Error())
+		}
+	},
+}
+
+// mcpTool builds a tool whose arguments decode into In. The input schema is
+// derived from In, so fields without omitempty are required.
+func mcpTool[In any](name, description string, fn func(In) (interface{}, error)) mcp.Tool {
+	var zero In
+	input := schema.For(zero)
+	delete(input, "title")
+	input["additionalProperties"] = false
+	if _, ok := input["properties"]; !ok {
+		input["properties"] = map[string]interface{}{}
+	}
+
+	return mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: input,
+		Call: func(raw json.RawMessage) (interface{}, error) {
+			if len(raw) == 0 || string(raw) == "null" {
+				raw = json.RawMessage("{}")
+			}
+			var present map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &present); err != nil {
+				return nil, fmt.Errorf("invalid arguments: %w", err)
+			}
+			if required, ok := input["required"].([]string); ok {
+				for _, r := range required {
+					if _, ok := present[r]; !ok {
+						return nil, fmt.Errorf("missing required argument %q", r)
+					}
+				}
+			}
+
+			var in In
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&in); err != nil {
+				return nil, fmt.Errorf("invalid arguments: %w", err)
+			}
+			return fn(in)
+		},
+	}
+}
+
+func readOnly(t mcp.Tool) mcp.Tool {
+	t.ReadOnly = true
+	return t
+}
+
+func destructive(t mcp.Tool) mcp.Tool {
+	t.Destructive = true
+	return t
+}
+
+type mcpIDInput struct {
+	ID string `json:"id" desc:"Resource ID"`
+}
+
+type mcpPageInput struct {
+	Limit int `json:"limit,omitempty" desc:"Results per page (default 10)"`
+	Page  int `json:"page,omitempty" desc:"Page number (default 1)"`
+}
+
+func (in mcpPageInput) params() map[string]string {
+	params := map[string]string{"page[size]": "10", "page[number]": "1"}
+	if in.Limit > 0 {
+		params["page[size]"] = fmt.Sprintf("%d", in.Limit)
+	}
+	if in.Page > 0 {
+		params["page[number]"] = fmt.Sprintf("%d", in.Page)
+	}
+	return params
+}
+
+type mcpLicensesInput struct {
+	mcpPageInput
+	Status  string `json:"status,omitempty" desc:"Filter by status, e.g. ACTIVE or EXPIRED"`
+	Product string `json:"product,omitempty" desc:"Filter by product ID"`
+	Policy  string `json:"policy,omitempty" desc:"Filter by policy ID"`
+	User    string `json:"user,omitempty" desc:"Filter by owner user ID"`
+}
+
+type mcpValidateInput struct {
+	ID          string `json:"id" desc:"License ID"`
+	Fingerprint string `json:"fingerprint,omitempty" desc:"Machine fingerprint to validate against"`
+}
+
+type mcpMachinesInput struct {
+	mcpPageInput
+	License     string `json:"license,omitempty" desc:"Filter by license ID"`
+	User        string `json:"user,omitempty" desc:"Filter by user ID"`
+	Fingerprint string `json:"fingerprint,omitempty" desc:"Filter by fingerprint"`
+}
+
+type mcpStaleInput struct {
+	OlderThan string `json:"older_than,omitempty" desc:"Heartbeat age after which a machine is stale, e.g. 36h or 7d (default 7d)"`
+	License   string `json:"license,omitempty" desc:"Only check machines for this license ID"`
+}
+
+type mcpMachineInput struct {
+	Machine string `json:"machine" desc:"Machine ID"`
+}
+
+type mcpFingerprintInput struct {
+	Fingerprint string `json:"fingerprint" desc:"Component fingerprint"`
+}
+
+type mcpComponentAddInput struct {
+	Machine     string `json:"machine" desc:"Machine ID"`
+	Fingerprint string `json:"fingerprint" desc:"Component fingerprint"`
+	Name        string `json:"name" desc:"Component name"`
+}
+
+type mcpComponentRenameInput struct {
+	Fingerprint string `json:"fingerprint" desc:"Component fingerprint"`
+	Name        string `json:"name" desc:"New component name"`
+}
+
+type mcpUsersInput struct {
+	mcpPageInput
+	Status string `json:"status,omitempty" desc:"Filter by status, e.g. ACTIVE or BANNED"`
+}
+
+type mcpUserInput struct {
+	User string `json:"user" desc:"User ID or email"`
+}
+
+type mcpStatusInput struct {
+	User string `json:"user,omitempty" desc:"Scope to one user's licenses (ID or email)"`
+}
+
+// mcpTools lists the tools served by 'keygen mcp serve'. Results are the
+// same data payloads the matching commands print.
+func mcpTools(client *api.Client, cfg *config.Config) []mcp.Tool {
+	findComponent := func(fingerprint string) (*api.Component, error) {
+		comp, err := client.FindComponentByFingerprint(fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if comp == nil {
+			return nil, fmt.Errorf("component not found: %s", fingerprint)
+		}
+		return comp, nil
+	}
+	staleMachines := func(in mcpStaleInput) ([]staleMachine, error) {
+		if in.OlderThan == "" {
+			in.OlderThan = "7d"
+		}
+		age, err := parseDays(in.OlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("older_than: %w", err)
+		}
+		params := map[string]string{}
+		if in.License != "" {
+			params["license"] = in.License
+		}
+		return findStaleMachines(client, params, time.Now().Add(-age))
+	}
+
+	return []mcp.Tool{
+		readOnly(mcpTool("status", "Account summary: license counts by status, machines, components and per-license usage.", func(in mcpStatusInput) (interface{}, error) {
+			result, details, _, err := accountStatus(client, cfg, in.User)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range details {
+				result.Licenses = append(result.Licenses, d)
+			}
+			return result, nil
+		})),
+
+		readOnly(mcpTool("licenses_list", "List licenses, optionally filtered by status, product, policy or owner.", func(in mcpLicensesInput) (interface{}, error) {
+			params := in.params()
+			for k, v := range map[string]string{"status": in.Status, "product": in.Product, "policy": in.Policy, "user": in.User} {
+				if v != "" {
+					params[k] = v
+				}
+			}
+			return client.ListLicenses(params)
+		})),
+		readOnly(mcpTool("license_show", "Show a license by ID.", func(in mcpIDInput) (interface{}, error) {
+			return client.GetLicense(in.ID)
+		})),
+		readOnly(mcpTool("license_validate", "Validate a license, optionally scoped to a machine fingerprint.", func(in mcpValidateInput) (interface{}, error) {
+			var validation *api.LicenseValidation
+			var license *api.License
+			var err error
+			if in.Fingerprint != "" {
+				validation, license, err = client.ValidateLicenseFingerprint(in.ID, in.Fingerprint)
+			} else {
+				validation, license, err = client.ValidateLicense(in.ID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"validation": validation, "license": license}, nil
+		})),
+		mcpTool("license_renew", "Renew a license, extending its expiry by its policy duration.", func(in mcpIDInput) (interface{}, error) {
+			return client.RenewLicense(in.ID)
+		}),
+		mcpTool("license_reinstate", "Reinstate a suspended license.", func(in mcpIDInput) (interface{}, error) {
+			return client.ReinstateLicense(in.ID)
+		}),
+		destructive(mcpTool("license_suspend", "Suspend a license so it no longer validates.", func(in mcpIDInput) (interface{}, error) {
+			return client.SuspendLicense(in.ID)
+		})),
+
+		readOnly(mcpTool("machines_list", "List machines, optionally filtered by license, user or fingerprint.", func(in mcpMachinesInput) (interface{}, error) {
+			params := in.params()
+			for k, v := range map[string]string{"license": in.License, "user": in.User, "fingerprint": in.Fingerprint} {
+				if v != "" {
+					params[k] = v
+				}
+			}
+			return client.ListMachines(params)
+		})),
+		readOnly(mcpTool("machine_show", "Show a machine and its components by ID.", func(in mcpIDInput) (interface{}, error) {
+			return client.GetMachine(in.ID)
+		})),
+		readOnly(mcpTool("machines_stale", "Find machines with a dead or stale heartbeat.", func(in mcpStaleInput) (interface{}, error) {
+			return staleMachines(in)
+		})),
+		destructive(mcpTool("machine_deactivate", "Deactivate (delete) a machine by ID.", func(in mcpIDInput) (interface{}, error) {
+			if err := client.DeactivateMachine(in.ID); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"deactivated": true, "id": in.ID}, nil
+		})),
+		destructive(mcpTool("machines_prune", "Deactivate every machine with a dead or stale heartbeat.", func(in mcpStaleInput) (interface{}, error) {
+			stale, err := staleMachines(in)
+			if err != nil {
+				return nil, err
+			}
+			for i := range stale {
+				if err := client.DeactivateMachine(stale[i].ID); err != nil {
+					stale[i].Error = err.Error()
+				} else {
+					stale[i].Deactivated = true
+				}
+			}
+			return stale, nil
+		})),
+
+		readOnly(mcpTool("components_list", "List a machine's components.", func(in mcpMachineInput) (interface{}, error) {
+			return client.ListComponents(in.Machine, 1, 100)
+		})),
+		readOnly(mcpTool("component_check", "Check whether a component fingerprint is registered.", func(in mcpFingerprintInput) (interface{}, error) {
+			comp, err := client.FindComponentByFingerprint(in.Fingerprint)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"fingerprint": in.Fingerprint, "registered": comp != nil, "component": comp}, nil
+		})),
+		mcpTool("component_add", "Add a component to a machine.", func(in mcpComponentAddInput) (interface{}, error) {
+			comp, err := client.CreateComponent(in.Machine, in.Fingerprint, in.Name)
+			if err != nil {
+				return nil, err
+			}
+			comp.MachineID = in.Machine
+			return comp, nil
+		}),
+		mcpTool("component_rename", "Rename a component by fingerprint.", func(in mcpComponentRenameInput) (interface{}, error) {
+			comp, err := findComponent(in.Fingerprint)
+			if err != nil {
+				return nil, err
+			}
+			return client.UpdateComponent(comp.ID, map[string]interface{}{"name": in.Name})
+		}),
+		destructive(mcpTool("component_delete", "Delete a component by fingerprint.", func(in mcpFingerprintInput) (interface{}, error) {
+			comp, err := findComponent(in.Fingerprint)
+			if err != nil {
+				return nil, err
+			}
+			if err := client.DeleteComponent(comp.ID); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"deleted": true, "id": comp.ID, "fingerprint": comp.Fingerprint, "name": comp.Name}, nil
+		})),
+
+		readOnly(mcpTool("users_list", "List users, optionally filtered by status.", func(in mcpUsersInput) (interface{}, error) {
+			params := in.params()
+			if in.Status != "" {
+				params["status"] = strings.ToUpper(in.Status)
+			}
+			return client.ListUsers(params)
+		})),
+		readOnly(mcpTool("user_show", "Show a user by ID or email, with their licenses.", func(in mcpUserInput) (interface{}, error) {
+			var user *api.User
+			var err error
+			if strings.Contains(in.User, "@") {
+				user, err = client.FindUserByEmail(in.User)
+			} else {
+				user, err = client.GetUser(in.User)
+			}
+			if err != nil {
+				return nil, err
+			}
+			licenses, err := client.GetUserLicenses(user.ID)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"user": user, "licenses": licenses}, nil
+		})),
+	}
+}
+
+func init() {
+	mcpCmd.AddCommand(mcpServeCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/mcp"
+)
+
+// keygenStub answers GET /licenses/lic-1 and records every request.
+func keygenStub(t *testing.T) (*httptest.Server, *[]string) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/accounts/acct/licenses/lic-1":
+			w.Write([]byte(`{"data":{"id":"lic-1","type":"licenses","attributes":{"key":"KEY-1","name":"Front Desk","status":"ACTIVE"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"title":"Not found","detail":"no such resource"}]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// serveMCP sends NDJSON requests to a server of the CLI's tools and returns
+// the responses by ID, with the requests that reached Keygen.
+func serveMCP(t *testing.T, allowDestructive bool, requests ...string) (map[string]map[string]interface{}, []string) {
+	srv, reached := keygenStub(t)
+	client := api.NewClient(srv.URL, "acct", "token")
+	cfg := &config.Config{AccountID: "acct", BaseURL: srv.URL, Token: "token"}
+	server := mcp.NewServer("keygen", "test", mcpTools(client, cfg), allowDestructive)
+
+	var out strings.Builder
+	if err := server.Serve(strings.NewReader(strings.Join(requests, "\n")+"\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[string]map[string]interface{}{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("response %q: %v", scanner.Text(), err)
+		}
+		responses[string(mustJSON(t, resp["id"]))] = resp
+	}
+	return responses, *reached
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func toolNames(t *testing.T, resp map[string]interface{}) map[string]bool {
+	names := map[string]bool{}
+	result, _ := resp["result"].(map[string]interface{})
+	tools, _ := result["tools"].([]interface{})
+	for _, tool := range tools {
+		names[tool.(map[string]interface{})["name"].(string)] = true
+	}
+	if len(names) == 0 {
+		t.Fatalf("tools/list returned no tools: %v", resp)
+	}
+	return names
+}
+
+// toolResult returns a tools/call result's text and isError.
+func toolResult(t *testing.T, resp map[string]interface{}) (string, bool) {
+	result, ok := resp["result"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no result in %v", resp)
+	}
+	content := result["content"].([]interface{})
+	return content[0].(map[string]interface{})["text"].(string), result["isError"].(bool)
+}
+
+func TestMCPServe(t *testing.T) {
+	responses, _ := serveMCP(t, false,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"license_show","arguments":{"id":"lic-1"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"license_show","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"license_show","arguments":{"id":"missing"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"nope"}`,
+	)
+	if len(responses) != 7 {
+		t.Fatalf("got %d responses, want 7 (notifications get none): %v", len(responses), responses)
+	}
+
+	initResult := responses["1"]["result"].(map[string]interface{})
+	if initResult["protocolVersion"] != "2025-03-26" {
+		t.Errorf("initialize protocolVersion = %v, want the client's", initResult["protocolVersion"])
+	}
+	if info := initResult["serverInfo"].(map[string]interface{}); info["name"] != "keygen" {
+		t.Errorf("serverInfo = %v", info)
+	}
+
+	names := toolNames(t, responses["2"])
+	for _, name := range []string{"license_show", "status", "component_check"} {
+		if !names[name] {
+			t.Errorf("tools/list is missing %s", name)
+		}
+	}
+
+	text, isError := toolResult(t, responses["3"])
+	if isError || !strings.Contains(text, `"key": "KEY-1"`) {
+		t.Errorf("license_show = %q (isError %v), want the stub's license", text, isError)
+	}
+	if text, isError := toolResult(t, responses["4"]); !isError || !strings.Contains(text, `missing required argument "id"`) {
+		t.Errorf("license_show without id = %q (isError %v)", text, isError)
+	}
+	if _, isError := toolResult(t, responses["5"]); !isError {
+		t.Error("license_show of a missing license is not an error")
+	}
+
+	for id, code := range map[string]float64{"6": -32602, "7": -32601} {
+		rerr, _ := responses[id]["error"].(map[string]interface{})
+		if rerr == nil || rerr["code"] != code {
+			t.Errorf("response %s error = %v, want code %v", id, responses[id]["error"], code)
+		}
+	}
+}
+
+func TestMCPDestructiveTools(t *testing.T) {
+	destructiveTools := []string{"license_suspend", "machine_deactivate", "machines_prune", "component_delete"}
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"license_suspend","arguments":{"id":"lic-1"}}}`
+	list := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	t.Run("hidden and refused by default", func(t *testing.T) {
+		responses, reached := serveMCP(t, false, list, call)
+		names := toolNames(t, responses["1"])
+		for _, name := range destructiveTools {
+			if names[name] {
+				t.Errorf("tools/list offers %s", name)
+			}
+		}
+		text, isError := toolResult(t, responses["2"])
+		if !isError || !strings.Contains(text, "destructive") {
+			t.Errorf("license_suspend = %q (isError %v), want it refused", text, isError)
+		}
+		if len(reached) != 0 {
+			t.Errorf("refused call reached Keygen: %v", reached)
+		}
+	})
+
+	t.Run("offered with allow_destructive", func(t *testing.T) {
+		responses, reached := serveMCP(t, true, list, call)
+		names := toolNames(t, responses["1"])
+		for _, name := range destructiveTools {
+			if !names[name] {
+				t.Errorf("tools/list is missing %s", name)
+			}
+		}
+		// The stub has no suspend action, so the call reaches it and fails there
+		text, isError := toolResult(t, responses["2"])
+		if !isError || strings.Contains(text, "destructive") {
+			t.Errorf("license_suspend = %q (isError %v), want the stub's error", text, isError)
+		}
+		if len(reached) != 1 || reached[0] != "POST /v1/accounts/acct/licenses/lic-1/actions/suspend" {
+			t.Errorf("requests = %v, want the suspend action", reached)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy an account's resources to another profile's account",
+	Long: `Copy products, policies, users, licenses, machines and components from
+the account of --from to the account of --to, e.g. from a self-hosted
+Keygen CE instance to Keygen EE. Both are profiles in profiles.json.
+
+Resources are copied in dependency order with their attributes and
+metadata; licenses keep their keys and relationships are rewritten to the
+target's IDs. Resources that already exist in the target (same license key,
+user email, product name, ...) are adopted rather than duplicated; see
+--on-conflict.
+
+Every source-to-target ID pair is written to the --map file as soon as it
+is known. If a migration stops part way, run the same command again to
+resume: mapped resources are not copied twice.
+
+After copying, the target is re-listed to verify that every source resource
+has a migrated counterpart. If counts don't match, the command exits with
+status 1; rerun it to retry the failed resources.
+
+Examples:
+  keygen migrate --from legacy --to cloud --dry-run
+  keygen migrate --from legacy --to cloud --format table
+  keygen migrate --from legacy --to cloud --map legacy-to-cloud.json --on-conflict update`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		if from == "" || to == "" {
+			output.Error("--from and --to are required")
+			return
+		}
+		if from == to {
+			output.Error("--from and --to must be different profiles")
+			return
+		}
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		if onConflict != "skip" && onConflict != "update" && onConflict != "fail" {
+			output.Error("--on-conflict must be skip, update or fail")
+			return
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		fromCfg, fromClient, err := profileClient(from)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		toCfg, toClient, err := profileClient(to)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		mapPath, _ := cmd.Flags().GetString("map")
+		if mapPath == "" {
+			mapPath = "keygen-migrate-" + from + "-" + to + ".json"
+		}
+		mapping, err := snapshot.LoadMapping(mapPath)
+		if err != nil {
+			output.Error("loading ID mapping: " + err.Error())
+			return
+		}
+		if mapping.Len() > 0 && (mapping.FromAccount != fromCfg.AccountID || mapping.ToAccount != toCfg.AccountID) {
+			output.Error(fmt.Sprintf("%s maps account %s to %s, not %s to %s; use another --map", mapPath, mapping.FromAccount, mapping.ToAccount, fromCfg.AccountID, toCfg.AccountID))
+			return
+		}
+		mapping.From, mapping.To = from, to
+		mapping.FromAccount, mapping.ToAccount = fromCfg.AccountID, toCfg.AccountID
+		resumed := mapping.Len()
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Reading %s (%s)\n", from, fromCfg.AccountID)
+		}
+		snap, err := exportSnapshot(fromClient, fromCfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		opts := restoreOptions{
+			OnConflict: onConflict,
+			DryRun:     dryRun,
+			IDs:        mapping.IDs,
+			Save:       func() error { return mapping.Save(mapPath) },
+		}
+		if onConflict == "fail" && !dryRun {
+			check := opts
+			check.DryRun = true
+			if actions, err := restoreSnapshot(toClient, snap, check); err != nil {
+				output.ErrorDetail(err.Error(), map[string]interface{}{"actions": actions})
+				return
+			}
+		}
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Copying to %s (%s)\n", to, toCfg.AccountID)
+		}
+		actions, err := restoreSnapshot(toClient, snap, opts)
+		if err != nil {
+			output.ErrorDetail(err.Error(), map[string]interface{}{"map": mapPath, "actions": actions})
+			os.Exit(1)
+		}
+		if !dryRun {
+			if err := mapping.Save(mapPath); err != nil {
+				output.Error("saving ID mapping: " + err.Error())
+				return
+			}
+		}
+
+		summary := map[string]map[string]int{}
+		for _, a := range actions {
+			if summary[a.Kind] == nil {
+				summary[a.Kind] = map[string]int{}
+			}
+			summary[a.Kind][a.Action]++
+		}
+		result := migrateOutput{
+			From:       profileAccount{Profile: from, AccountID: fromCfg.AccountID},
+			To:         profileAccount{Profile: to, AccountID: toCfg.AccountID},
+			Map:        mapPath,
+			Resumed:    resumed,
+			DryRun:     dryRun,
+			OnConflict: onConflict,
+			Summary:    summary,
+			Actions:    actions,
+		}
+
+		var verification []migrateCount
+		verified := true
+		if !dryRun {
+			verification, err = verifyMigration(toClient, snap, mapping)
+			if err != nil {
+				output.ErrorDetail("verifying migration: "+err.Error(), result)
+				os.Exit(1)
+			}
+			for _, v := range verification {
+				verified = verified && v.OK
+			}
+			result.Verification = verification
+			result.Verified = &verified
+		}
+
+		headers := []string{"KIND", "SOURCE", "CREATE", "EXISTING", "FAILED", "MIGRATED", "OK"}
+		var rows [][]string
+		for i, kind := range snapshot.Kinds {
+			s := summary[kind.Name]
+			row := []string{
+				kind.Name,
+				strconv.Itoa(len(snap.Resources[kind.Name])),
+				strconv.Itoa(s["create"]),
+				strconv.Itoa(s["skip"] + s["done"] + s["update"] + s["unchanged"]),
+				strconv.Itoa(s["failed"]),
+				"", "",
+			}
+			if verification != nil {
+				row[5] = strconv.Itoa(verification[i].Migrated)
+				row[6] = strconv.FormatBool(verification[i].OK)
+			}
+			rows = append(rows, row)
+		}
+
+		if !verified {
+			output.ErrorDetail("verification failed: not every resource was migrated; rerun to resume", result)
+			os.Exit(1)
+		}
+		output.SuccessTable(result, headers, rows)
+	},
+}
+
+// migrateCount compares a kind's source count with its migrated resources.
+type migrateCount struct {
+	Kind string `json:"kind"`
+	// Source is the number of resources in the source account.
+	Source int `json:"source"`
+	// Migrated counts source resources whose mapped target exists.
+	Migrated int `json:"migrated"`
+	// Target is the total in the target account, which may hold more.
+	Target int  `json:"target"`
+	OK     bool `json:"ok"`
+}
+
+// verifyMigration re-lists the target and checks that every source resource
+// maps to a resource that exists there.
+func verifyMigration(client *api.Client, snap *snapshot.Snapshot, mapping *snapshot.Mapping) ([]migrateCount, error) {
+	var counts []migrateCount
+	for _, kind := range snapshot.Kinds {
+		targets, err := listResources(client, kind)
+		if err != nil {
+			return nil, fmt.Errorf("listing target %s: %w", kind.Name, err)
+		}
+		present := map[string]bool{}
+		for _, t := range targets {
+			present[t.ID] = true
+		}
+		c := migrateCount{Kind: kind.Name, Source: len(snap.Resources[kind.Name]), Target: len(targets)}
+		for _, res := range snap.Resources[kind.Name] {
+			if present[mapping.IDs[kind.Name][res.ID]] {
+				c.Migrated++
+			}
+		}
+		c.OK = c.Migrated == c.Source
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+// profileClient loads a saved profile, without environment overrides, and
+// its API client. Cached reads are always revalidated, since migrate and
+// diff need the account's current state.
+func profileClient(name string) (*config.Config, *api.Client, error) {
+	cfg, err := config.GetProfile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.CacheTTL = "0"
+	cfg.NoCache = noCache
+	cfg.RefreshCache = refreshCache
+	client, err := auth.ResolveClient(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return cfg, client, nil
+}
+
+func init() {
+	migrateCmd.Flags().String("from", "", "Profile to copy from")
+	migrateCmd.Flags().String("to", "", "Profile to copy to")
+	migrateCmd.Flags().String("map", "", "ID mapping file, for resuming (default keygen-migrate-<from>-<to>.json)")
+	migrateCmd.Flags().Bool("dry-run", false, "Show the plan without writing anything")
+	migrateCmd.Flags().String("on-conflict", "skip", "What to do with resources that already exist: skip, update or fail")
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,530 @@
+package cmd
+
+import (
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/importer"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/productivityenthusiast/keygen-cli/internal/state"
+)
+
+// The types below are the data payloads of commands that don't print an API
+// type. Commands print them as they are, so the schemas 'keygen schema'
+// derives from them describe exactly what is printed.
+
+type messageOutput struct {
+	Message string `json:"message"`
+	Profile string `json:"profile"`
+}
+
+type planSummary struct {
+	Create    int `json:"create"`
+	Update    int `json:"update"`
+	Unchanged int `json:"unchanged"`
+}
+
+type planOutput struct {
+	Summary planSummary    `json:"summary"`
+	Changes []state.Change `json:"changes"`
+}
+
+type applyOutput struct {
+	planOutput
+	Applied []appliedChange `json:"applied"`
+}
+
+// applyPreview is the error detail of apply without --auto-approve.
+type applyPreview struct {
+	planOutput
+	Confirm string `json:"confirm"`
+}
+
+type diffSideOutput struct {
+	Source    string         `json:"source"`
+	AccountID string         `json:"account_id"`
+	Created   string         `json:"created"`
+	Resources map[string]int `json:"resources"`
+}
+
+type diffOutput struct {
+	A         diffSideOutput    `json:"a"`
+	B         diffSideOutput    `json:"b"`
+	Identical bool              `json:"identical"`
+	Summary   map[string]int    `json:"summary" desc:"Changes by op: added, removed, modified"`
+	Changes   []snapshot.Change `json:"changes"`
+}
+
+type profileAccount struct {
+	Profile   string `json:"profile"`
+	AccountID string `json:"account_id"`
+}
+
+type migrateOutput struct {
+	From         profileAccount            `json:"from"`
+	To           profileAccount            `json:"to"`
+	Map          string                    `json:"map" desc:"ID mapping file used to resume"`
+	Resumed      int                       `json:"resumed" desc:"IDs already mapped by an earlier run"`
+	DryRun       bool                      `json:"dry_run"`
+	OnConflict   string                    `json:"on_conflict"`
+	Summary      map[string]map[string]int `json:"summary" desc:"Actions by kind"`
+	Actions      []restoreAction           `json:"actions"`
+	Verification []migrateCount            `json:"verification,omitempty" desc:"Not set for dry runs"`
+	Verified     *bool                     `json:"verified,omitempty" desc:"Not set for dry runs"`
+}
+
+type importOutput struct {
+	File        string          `json:"file"`
+	Type        string          `json:"type"`
+	DryRun      bool            `json:"dry_run"`
+	Rows        int             `json:"rows"`
+	Summary     map[string]int  `json:"summary" desc:"Rows by status"`
+	Results     []*importer.Row `json:"results"`
+	ResultsFile string          `json:"results_file"`
+}
+
+type syncOutput struct {
+	File      string         `json:"file"`
+	AccountID string         `json:"account_id"`
+	Synced    string         `json:"synced"`
+	Duration  string         `json:"duration"`
+	Counts    map[string]int `json:"counts"`
+}
+
+type snapshotExportOutput struct {
+	Path      string         `json:"path"`
+	Version   int            `json:"version"`
+	AccountID string         `json:"account_id"`
+	Created   string         `json:"created"`
+	Resources map[string]int `json:"resources"`
+}
+
+type snapshotInfo struct {
+	AccountID string `json:"account_id"`
+	Created   string `json:"created"`
+	Version   int    `json:"version"`
+}
+
+type snapshotRestoreOutput struct {
+	Snapshot   snapshotInfo              `json:"snapshot"`
+	DryRun     bool                      `json:"dry_run"`
+	OnConflict string                    `json:"on_conflict"`
+	Summary    map[string]map[string]int `json:"summary" desc:"Actions by kind"`
+	Actions    []restoreAction           `json:"actions"`
+}
+
+type artifactUploadOutput struct {
+	Uploaded bool         `json:"uploaded"`
+	Attempts int          `json:"attempts,omitempty"`
+	Checksum string       `json:"checksum,omitempty"`
+	Skipped  string       `json:"skipped,omitempty" desc:"Why nothing was uploaded"`
+	Artifact api.Artifact `json:"artifact"`
+}
+
+type artifactDownloadOutput struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	Path     string `json:"path"`
+	Bytes    int64  `json:"bytes" desc:"Bytes written by this run"`
+	Resumed  bool   `json:"resumed"`
+	Verified bool   `json:"verified"`
+	Checksum string `json:"checksum"`
+}
+
+type cacheClearOutput struct {
+	Message string `json:"message"`
+	Profile string `json:"profile,omitempty" desc:"Not set with --all"`
+	Dir     string `json:"dir"`
+	Removed int    `json:"removed"`
+}
+
+type componentCheckOutput struct {
+	Found       bool   `json:"found"`
+	Fingerprint string `json:"fingerprint"`
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	MachineID   string `json:"machine_id,omitempty"`
+}
+
+type componentCheckLocalOutput struct {
+	Machine    *checkResult  `json:"machine"`
+	Components []checkResult `json:"components"`
+	Unreadable []string      `json:"unreadable,omitempty"`
+}
+
+type componentDeletePreview struct {
+	Action      string `json:"action"`
+	Fingerprint string `json:"fingerprint"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MachineID   string `json:"machine_id"`
+	Confirm     string `json:"confirm"`
+}
+
+type componentDeleteOutput struct {
+	Deleted     bool   `json:"deleted"`
+	Fingerprint string `json:"fingerprint"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+}
+
+type componentRenameOutput struct {
+	ID          string `json:"id"`
+	Fingerprint string `json:"fingerprint"`
+	OldName     string `json:"old_name"`
+	Name        string `json:"name"`
+	MachineID   string `json:"machine_id"`
+}
+
+type componentMovePreview struct {
+	Action      string `json:"action"`
+	Fingerprint string `json:"fingerprint"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FromMachine string `json:"from_machine"`
+	ToMachine   string `json:"to_machine"`
+	Confirm     string `json:"confirm"`
+}
+
+type componentMoveOutput struct {
+	Moved       bool   `json:"moved"`
+	Fingerprint string `json:"fingerprint"`
+	OldID       string `json:"old_id"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FromMachine string `json:"from_machine"`
+	ToMachine   string `json:"to_machine"`
+}
+
+type configShowOutput struct {
+	Profile     string `json:"profile"`
+	AccountID   string `json:"account_id"`
+	BaseURL     string `json:"base_url"`
+	Token       string `json:"token" desc:"Masked"`
+	Email       string `json:"email"`
+	HasPassword bool   `json:"has_password"`
+	TokenExpiry string `json:"token_expiry"`
+	PublicKey   string `json:"public_key"`
+}
+
+type fingerprintOutput struct {
+	Scheme     string             `json:"scheme"`
+	Salted     bool               `json:"salted"`
+	Machine    *localFingerprint  `json:"machine"`
+	Components []localFingerprint `json:"components"`
+	Unreadable []string           `json:"unreadable,omitempty"`
+}
+
+type licenseStatusOutput struct {
+	LicenseID     string `json:"license_id"`
+	Valid         bool   `json:"valid"`
+	Status        string `json:"status"`
+	Detail        string `json:"detail"`
+	Code          string `json:"code"`
+	Machines      int    `json:"machines"`
+	Components    int    `json:"components"`
+	DaysRemaining int    `json:"days_remaining"`
+	Key           string `json:"key"`
+	Name          string `json:"name"`
+	Expiry        string `json:"expiry"`
+	Uses          int    `json:"uses"`
+	MaxUses       int    `json:"max_uses"`
+	LastValidated string `json:"last_validated"`
+	UsageWarning  string `json:"usage_warning,omitempty"`
+}
+
+type licenseRenewOutput struct {
+	LicenseID string `json:"license_id"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	OldExpiry string `json:"old_expiry"`
+	NewExpiry string `json:"new_expiry"`
+	Status    string `json:"status"`
+}
+
+type licenseUpdateOutput struct {
+	LicenseID string                 `json:"license_id"`
+	Key       string                 `json:"key"`
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Metadata  map[string]interface{} `json:"metadata"`
+}
+
+// licenseChangeOutput is printed by change-policy, transfer and set-owner.
+type licenseChangeOutput struct {
+	LicenseID string             `json:"license_id"`
+	Key       string             `json:"key"`
+	DryRun    bool               `json:"dry_run"`
+	Before    licenseRelationIDs `json:"before"`
+	After     licenseRelationIDs `json:"after"`
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+type licenseUsageOutput struct {
+	LicenseID    string `json:"license_id"`
+	Key          string `json:"key"`
+	Action       string `json:"action"`
+	UsesBefore   int    `json:"uses_before"`
+	Uses         int    `json:"uses"`
+	MaxUses      int    `json:"max_uses"`
+	UsageWarning string `json:"usage_warning,omitempty"`
+}
+
+type loginTokenOutput struct {
+	Message   string `json:"message"`
+	Profile   string `json:"profile"`
+	AccountID string `json:"account_id"`
+	BaseURL   string `json:"base_url"`
+}
+
+type loginPasswordOutput struct {
+	Message   string `json:"message"`
+	Profile   string `json:"profile"`
+	TokenID   string `json:"token_id"`
+	Expiry    string `json:"expiry"`
+	AccountID string `json:"account_id"`
+}
+
+type whoamiOutput struct {
+	Profile    string `json:"profile"`
+	AccountID  string `json:"account_id"`
+	BaseURL    string `json:"base_url"`
+	AuthMethod string `json:"auth_method"`
+	TokenValid bool   `json:"token_valid"`
+}
+
+type machineActivateOutput struct {
+	LicenseID        string                 `json:"license_id"`
+	Activated        bool                   `json:"activated"`
+	AlreadyActivated bool                   `json:"already_activated"`
+	Machine          *api.Machine           `json:"machine"`
+	Validation       *api.LicenseValidation `json:"validation,omitempty"`
+	ValidationError  string                 `json:"validation_error,omitempty"`
+}
+
+type machinesStalePreview struct {
+	OlderThan string        `json:"older_than"`
+	Count     int           `json:"count"`
+	Machines  []interface{} `json:"machines" desc:"The stale machines; with --fields, only the id and the selected fields"`
+	Confirm   string        `json:"confirm"`
+}
+
+type processKillPreview struct {
+	Action    string `json:"action"`
+	ID        string `json:"id"`
+	PID       string `json:"pid"`
+	Status    string `json:"status"`
+	MachineID string `json:"machine_id"`
+	Confirm   string `json:"confirm"`
+}
+
+type processKillOutput struct {
+	Killed    bool   `json:"killed"`
+	ID        string `json:"id"`
+	PID       string `json:"pid"`
+	MachineID string `json:"machine_id"`
+}
+
+type profileListEmpty struct {
+	Profiles []string `json:"profiles"`
+	Message  string   `json:"message"`
+}
+
+type profileAddOutput struct {
+	Message   string `json:"message"`
+	Profile   string `json:"profile"`
+	AccountID string `json:"account_id"`
+	BaseURL   string `json:"base_url"`
+	Token     string `json:"token" desc:"Masked"`
+	Email     string `json:"email"`
+}
+
+type profileEditOutput struct {
+	Message   string `json:"message"`
+	Profile   string `json:"profile"`
+	AccountID string `json:"account_id"`
+	BaseURL   string `json:"base_url"`
+}
+
+type profileShowOutput struct {
+	Profile          string `json:"profile"`
+	Default          bool   `json:"default"`
+	AccountID        string `json:"account_id"`
+	BaseURL          string `json:"base_url"`
+	Token            string `json:"token" desc:"Masked"`
+	Email            string `json:"email"`
+	HasPassword      bool   `json:"has_password"`
+	TokenExpiry      string `json:"token_expiry"`
+	PublicKey        string `json:"public_key"`
+	AllowDestructive bool   `json:"allow_destructive"`
+	CacheTTL         string `json:"cache_ttl"`
+}
+
+type profileRenameOutput struct {
+	Message string `json:"message"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+type releaseDeletePreview struct {
+	Action  string `json:"action"`
+	ID      string `json:"id"`
+	Version string `json:"version"`
+	Channel string `json:"channel"`
+	Status  string `json:"status"`
+	Confirm string `json:"confirm"`
+}
+
+type releaseDeleteOutput struct {
+	Deleted bool   `json:"deleted"`
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+type reportOutput struct {
+	Path     string `json:"path"`
+	Template string `json:"template"`
+	Markdown bool   `json:"markdown"`
+	Bytes    int    `json:"bytes"`
+	Licenses int    `json:"licenses"`
+	Expiring int    `json:"expiring"`
+}
+
+type expiringGroup struct {
+	Group    string        `json:"group"`
+	Count    int           `json:"count"`
+	Licenses []interface{} `json:"licenses" desc:"The group's expiring licenses; with --fields, only the id and the selected fields"`
+}
+
+type reportsExpiringOutput struct {
+	Within  string           `json:"within"`
+	Cutoff  string           `json:"cutoff"`
+	GroupBy string           `json:"group_by"`
+	Total   int              `json:"total"`
+	Groups  []*expiringGroup `json:"groups"`
+	Notes   []string         `json:"notes,omitempty"`
+}
+
+type positionalArg struct {
+	Name     string   `json:"name"`
+	Enum     []string `json:"enum,omitempty"`
+	Variadic bool     `json:"variadic,omitempty"`
+}
+
+type commandArgs struct {
+	Positional []positionalArg `json:"positional"`
+	Min        *int            `json:"min,omitempty" desc:"Not set when the command doesn't check its arguments"`
+	Max        *int            `json:"max,omitempty" desc:"Not set when any number is accepted"`
+}
+
+type flagDescription struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+	Shorthand string `json:"shorthand,omitempty"`
+}
+
+type commandDescription struct {
+	Path     string                 `json:"path"`
+	Use      string                 `json:"use"`
+	Short    string                 `json:"short"`
+	Long     string                 `json:"long,omitempty"`
+	Args     commandArgs            `json:"args"`
+	Flags    []flagDescription      `json:"flags"`
+	Examples []string               `json:"examples,omitempty"`
+	Output   map[string]interface{} `json:"output" desc:"JSON Schema of the command's output"`
+}
+
+type schemaOutput struct {
+	Name        string               `json:"name"`
+	Version     string               `json:"version"`
+	Formats     []string             `json:"formats"`
+	GlobalFlags []flagDescription    `json:"global_flags"`
+	Commands    []commandDescription `json:"commands"`
+}
+
+type statusOutput struct {
+	UserID          string         `json:"user_id,omitempty" desc:"Set with --user"`
+	UserEmail       string         `json:"user_email,omitempty" desc:"Set with --user"`
+	AccountID       string         `json:"account_id,omitempty" desc:"Not set with --user"`
+	BaseURL         string         `json:"base_url,omitempty" desc:"Not set with --user"`
+	TotalUsers      *int           `json:"total_users,omitempty" desc:"Not set with --user"`
+	TotalProducts   *int           `json:"total_products,omitempty" desc:"Not set with --user"`
+	TotalLicenses   int            `json:"total_licenses"`
+	TotalMachines   int            `json:"total_machines"`
+	TotalComponents int            `json:"total_components"`
+	LicenseStatuses map[string]int `json:"license_statuses"`
+	UsageWarnings   []string       `json:"usage_warnings,omitempty"`
+	Licenses        []interface{}  `json:"licenses" desc:"Per-license details; with --fields, only the id and the selected fields"`
+}
+
+type userStatusOutput struct {
+	UserID          string `json:"user_id"`
+	Email           string `json:"email"`
+	TotalLicenses   int    `json:"total_licenses"`
+	Active          int    `json:"active"`
+	Expiring        int    `json:"expiring"`
+	Expired         int    `json:"expired"`
+	Suspended       int    `json:"suspended"`
+	TotalMachines   int    `json:"total_machines"`
+	TotalComponents int    `json:"total_components"`
+}
+
+type userUpdateOutput struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+	Updated   string `json:"updated"`
+}
+
+type endpointDeletePreview struct {
+	Action  string `json:"action"`
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	Confirm string `json:"confirm"`
+}
+
+type endpointDeleteOutput struct {
+	Deleted bool   `json:"deleted"`
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+}
+
+type webhookEventShowOutput struct {
+	ID               string      `json:"id"`
+	Event            string      `json:"event"`
+	Endpoint         string      `json:"endpoint"`
+	Status           string      `json:"status"`
+	LastResponseCode int         `json:"last_response_code"`
+	LastResponseBody string      `json:"last_response_body"`
+	Created          string      `json:"created"`
+	Updated          string      `json:"updated"`
+	Payload          interface{} `json:"payload" desc:"The event's payload document, or the raw string if it isn't JSON"`
+}
+
+type webhookRetryOutput struct {
+	RetriedEventID string            `json:"retried_event_id"`
+	Event          *api.WebhookEvent `json:"event"`
+}
+
+type webhookVerifyOutput struct {
+	Verified bool        `json:"verified"`
+	ID       string      `json:"id"`
+	Event    string      `json:"event"`
+	Payload  interface{} `json:"payload"`
+}
+
+type webhookReplayOutput struct {
+	Status int    `json:"status" desc:"The receiver's HTTP status"`
+	Body   string `json:"body"`
+}
+
+type webhookListenRecord struct {
+	Received string      `json:"received"`
+	ID       string      `json:"id"`
+	Event    string      `json:"event"`
+	Created  string      `json:"created"`
+	Payload  interface{} `json:"payload"`
+}
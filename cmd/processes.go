@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var processesCmd = &cobra.Command{
+	Use:   "processes",
+	Short: "Manage machine processes",
+}
+
+var processesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List processes",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		if v, _ := cmd.Flags().GetString("machine"); v != "" {
+			params["machine"] = v
+		}
+		if v, _ := cmd.Flags().GetString("license"); v != "" {
+			params["license"] = v
+		}
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		processes, err := client.ListProcesses(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Processes, processes)
+	},
+}
+
+var processesKillCmd = &cobra.Command{
+	Use:   "kill [process-id]",
+	Short: "Kill a process, freeing its slot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		process, err := client.GetProcess(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if !force {
+			output.Success(processKillPreview{
+				Action:    "kill",
+				ID:        process.ID,
+				PID:       process.PID,
+				Status:    process.Status,
+				MachineID: process.MachineID,
+				Confirm:   "use --force to confirm",
+			})
+			return
+		}
+
+		if err := client.KillProcess(process.ID); err != nil {
+			output.Error("kill failed: " + err.Error())
+			return
+		}
+
+		output.Success(processKillOutput{
+			Killed:    true,
+			ID:        process.ID,
+			PID:       process.PID,
+			MachineID: process.MachineID,
+		})
+	},
+}
+
+func init() {
+	processesListCmd.Flags().String("machine", "", "Filter by machine ID")
+	processesListCmd.Flags().String("license", "", "Filter by license ID")
+	processesListCmd.Flags().Int("limit", 10, "Results per page")
+	processesListCmd.Flags().Int("page", 1, "Page number")
+
+	processesKillCmd.Flags().Bool("force", false, "Skip confirmation")
+
+	processesCmd.AddCommand(processesListCmd)
+	processesCmd.AddCommand(processesKillCmd)
+	rootCmd.AddCommand(processesCmd)
+}
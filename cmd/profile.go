@@ -33,57 +33,37 @@ var profileListCmd = &cobra.Command{
 		names, defaultName := config.ListProfiles()
 
 		if len(names) == 0 {
-			output.Success(map[string]interface{}{
-				"profiles": []string{},
-				"message":  "No profiles configured. Run 'keygen profile add <name>' to create one.",
+			output.Success(profileListEmpty{
+				Profiles: []string{},
+				Message:  "No profiles configured. Run 'keygen profile add <name>' to create one.",
 			})
 			return
 		}
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"PROFILE", "DEFAULT", "ACCOUNT_ID", "BASE_URL"}
-			rows := make([][]string, len(names))
-			for i, name := range names {
-				isDefault := ""
-				if name == defaultName {
-					isDefault = "*"
-				}
-				p, _ := config.GetProfile(name)
-				acct := ""
-				base := ""
-				if p != nil {
-					acct = p.AccountID
-					base = p.BaseURL
-				}
-				rows[i] = []string{name, isDefault, acct, base}
+		headers := []string{"PROFILE", "DEFAULT", "ACCOUNT_ID", "BASE_URL"}
+		items := make([]profileInfo, len(names))
+		rows := make([][]string, len(names))
+		for i, name := range names {
+			isDefault := ""
+			if name == defaultName {
+				isDefault = "*"
 			}
-			output.FormatTable(f, headers, rows)
-		} else {
-			type profileInfo struct {
-				Name      string `json:"name"`
-				Default   bool   `json:"default"`
-				AccountID string `json:"account_id"`
-				BaseURL   string `json:"base_url"`
+			p, _ := config.GetProfile(name)
+			acct := ""
+			base := ""
+			if p != nil {
+				acct = p.AccountID
+				base = p.BaseURL
 			}
-			items := make([]profileInfo, len(names))
-			for i, name := range names {
-				p, _ := config.GetProfile(name)
-				acct := ""
-				base := ""
-				if p != nil {
-					acct = p.AccountID
-					base = p.BaseURL
-				}
-				items[i] = profileInfo{
-					Name:      name,
-					Default:   name == defaultName,
-					AccountID: acct,
-					BaseURL:   base,
-				}
+			items[i] = profileInfo{
+				Name:      name,
+				Default:   name == defaultName,
+				AccountID: acct,
+				BaseURL:   base,
 			}
-			output.SuccessList(items, len(items))
+			rows[i] = []string{name, isDefault, acct, base}
 		}
+		output.SuccessListTable(items, len(items), headers, rows)
 	},
 }
 
@@ -123,11 +103,22 @@ Examples:
 		if v, _ := cmd.Flags().GetString("password"); v != "" {
 			cfg.Password = v
 		}
+		if v, _ := cmd.Flags().GetString("public-key"); v != "" {
+			cfg.PublicKey = v
+		}
+		cfg.AllowDestructive, _ = cmd.Flags().GetBool("allow-destructive")
+		if v, _ := cmd.Flags().GetString("cache-ttl"); v != "" {
+			cfg.CacheTTL = v
+		}
 
 		if cfg.AccountID == "" || cfg.BaseURL == "" {
 			output.Error("--account-id and --base-url are required when adding a profile")
 			return
 		}
+		if _, err := cfg.CacheDuration(); err != nil {
+			output.Error(err.Error())
+			return
+		}
 
 		if err := config.SaveProfile(name, cfg); err != nil {
 			output.Error("failed to save profile: " + err.Error())
@@ -142,13 +133,13 @@ Examples:
 
 		maskedToken := maskToken(cfg.Token)
 
-		output.Success(map[string]interface{}{
-			"message":    fmt.Sprintf("Profile %q created", name),
-			"profile":    name,
-			"account_id": cfg.AccountID,
-			"base_url":   cfg.BaseURL,
-			"token":      maskedToken,
-			"email":      cfg.Email,
+		output.Success(profileAddOutput{
+			Message:   fmt.Sprintf("Profile %q created", name),
+			Profile:   name,
+			AccountID: cfg.AccountID,
+			BaseURL:   cfg.BaseURL,
+			Token:     maskedToken,
+			Email:     cfg.Email,
 		})
 	},
 }
@@ -199,9 +190,28 @@ Examples:
 			cfg.Password = v
 			changed = true
 		}
+		if cmd.Flags().Changed("public-key") {
+			v, _ := cmd.Flags().GetString("public-key")
+			cfg.PublicKey = v
+			changed = true
+		}
+		if cmd.Flags().Changed("allow-destructive") {
+			v, _ := cmd.Flags().GetBool("allow-destructive")
+			cfg.AllowDestructive = v
+			changed = true
+		}
+		if cmd.Flags().Changed("cache-ttl") {
+			v, _ := cmd.Flags().GetString("cache-ttl")
+			cfg.CacheTTL = v
+			if _, err := cfg.CacheDuration(); err != nil {
+				output.Error(err.Error())
+				return
+			}
+			changed = true
+		}
 
 		if !changed {
-			output.Error("no update flags provided. Use --account-id, --base-url, --token, --email, or --password")
+			output.Error("no update flags provided. Use --account-id, --base-url, --token, --email, --password, --public-key, --allow-destructive, or --cache-ttl")
 			return
 		}
 
@@ -210,11 +220,11 @@ Examples:
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message":    fmt.Sprintf("Profile %q updated", name),
-			"profile":    name,
-			"account_id": cfg.AccountID,
-			"base_url":   cfg.BaseURL,
+		output.Success(profileEditOutput{
+			Message:   fmt.Sprintf("Profile %q updated", name),
+			Profile:   name,
+			AccountID: cfg.AccountID,
+			BaseURL:   cfg.BaseURL,
 		})
 	},
 }
@@ -231,9 +241,9 @@ var profileDeleteCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message": fmt.Sprintf("Profile %q deleted", name),
-			"profile": name,
+		output.Success(messageOutput{
+			Message: fmt.Sprintf("Profile %q deleted", name),
+			Profile: name,
 		})
 	},
 }
@@ -253,15 +263,18 @@ var profileShowCmd = &cobra.Command{
 
 		_, defaultName := config.ListProfiles()
 
-		output.Success(map[string]interface{}{
-			"profile":      name,
-			"default":      name == defaultName,
-			"account_id":   cfg.AccountID,
-			"base_url":     cfg.BaseURL,
-			"token":        maskToken(cfg.Token),
-			"email":        cfg.Email,
-			"has_password":  cfg.Password != "",
-			"token_expiry": cfg.TokenExp,
+		output.Success(profileShowOutput{
+			Profile:          name,
+			Default:          name == defaultName,
+			AccountID:        cfg.AccountID,
+			BaseURL:          cfg.BaseURL,
+			Token:            maskToken(cfg.Token),
+			Email:            cfg.Email,
+			HasPassword:      cfg.Password != "",
+			TokenExpiry:      cfg.TokenExp,
+			PublicKey:        cfg.PublicKey,
+			AllowDestructive: cfg.AllowDestructive,
+			CacheTTL:         cfg.CacheTTL,
 		})
 	},
 }
@@ -283,9 +296,9 @@ Examples:
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message": fmt.Sprintf("Default profile set to %q", name),
-			"profile": name,
+		output.Success(messageOutput{
+			Message: fmt.Sprintf("Default profile set to %q", name),
+			Profile: name,
 		})
 	},
 }
@@ -303,14 +316,22 @@ var profileRenameCmd = &cobra.Command{
 			return
 		}
 
-		output.Success(map[string]interface{}{
-			"message":  fmt.Sprintf("Profile %q renamed to %q", oldName, newName),
-			"old_name": oldName,
-			"new_name": newName,
+		output.Success(profileRenameOutput{
+			Message: fmt.Sprintf("Profile %q renamed to %q", oldName, newName),
+			OldName: oldName,
+			NewName: newName,
 		})
 	},
 }
 
+// profileInfo is one profile of 'profile list'.
+type profileInfo struct {
+	Name      string `json:"name"`
+	Default   bool   `json:"default"`
+	AccountID string `json:"account_id"`
+	BaseURL   string `json:"base_url"`
+}
+
 func maskToken(t string) string {
 	if t == "" {
 		return ""
@@ -327,12 +348,18 @@ func init() {
 	profileAddCmd.Flags().String("token", "", "API token")
 	profileAddCmd.Flags().String("email", "", "Account email (for token refresh)")
 	profileAddCmd.Flags().String("password", "", "Account password (for token refresh)")
+	profileAddCmd.Flags().String("public-key", "", "Account Ed25519 public key (for webhook verification)")
+	profileAddCmd.Flags().Bool("allow-destructive", false, "Allow destructive MCP tools (delete, suspend, prune)")
+	profileAddCmd.Flags().String("cache-ttl", "", "How long cached API responses are used without revalidation, e.g. 30s (default 0)")
 
 	profileEditCmd.Flags().String("account-id", "", "Keygen account ID")
 	profileEditCmd.Flags().String("base-url", "", "Keygen API base URL")
 	profileEditCmd.Flags().String("token", "", "API token")
 	profileEditCmd.Flags().String("email", "", "Account email")
 	profileEditCmd.Flags().String("password", "", "Account password")
+	profileEditCmd.Flags().String("public-key", "", "Account Ed25519 public key")
+	profileEditCmd.Flags().Bool("allow-destructive", false, "Allow destructive MCP tools (delete, suspend, prune)")
+	profileEditCmd.Flags().String("cache-ttl", "", "How long cached API responses are used without revalidation, e.g. 30s (default 0)")
 
 	profileCmd.AddCommand(profileListCmd)
 	profileCmd.AddCommand(profileAddCmd)
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var releasesCmd = &cobra.Command{
+	Use:   "releases",
+	Short: "Manage product releases",
+}
+
+var releasesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List releases",
+	Long: `List releases with optional filters.
+
+--version accepts a constraint which is applied to the returned page,
+e.g. ">=1.2.0 <2.0.0", "^1.4" or "~1.4.2".
+
+Examples:
+  keygen releases list --product <id> --channel stable
+  keygen releases list --version "^2.0" --format table`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := releaseFilterParams(cmd)
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		releases, err := client.ListReleases(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		constraint, _ := cmd.Flags().GetString("version")
+		releases, err = filterReleasesByVersion(releases, constraint)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Releases, releases)
+	},
+}
+
+var releasesLatestCmd = &cobra.Command{
+	Use:   "latest",
+	Short: "Show the newest published release",
+	Long: `Show the newest published release by semantic version.
+
+Examples:
+  keygen releases latest --channel stable
+  keygen releases latest --product <id> --channel beta --version "<3.0.0"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := releaseFilterParams(cmd)
+		params["status"] = "PUBLISHED"
+		params["page[size]"] = "100"
+
+		var all []api.Release
+		for page := 1; ; page++ {
+			params["page[number]"] = fmt.Sprintf("%d", page)
+			releases, err := client.ListReleases(params)
+			if err != nil {
+				output.Error(err.Error())
+				return
+			}
+			all = append(all, releases...)
+			if len(releases) < 100 {
+				break
+			}
+		}
+
+		constraint, _ := cmd.Flags().GetString("version")
+		all, err = filterReleasesByVersion(all, constraint)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		var latest *api.Release
+		for i := range all {
+			if latest == nil || semver.CompareStrings(all[i].Version, latest.Version) > 0 {
+				latest = &all[i]
+			}
+		}
+
+		if latest == nil {
+			output.Error("no published release matches the given filters")
+			return
+		}
+
+		headers := []string{"ID", "VERSION", "CHANNEL", "STATUS", "NAME", "CREATED"}
+		rows := [][]string{{latest.ID, latest.Version, latest.Channel, strings.ToUpper(latest.Status), latest.Name, latest.Created}}
+		output.SuccessTable(latest, headers, rows)
+	},
+}
+
+var releasesShowCmd = &cobra.Command{
+	Use:   "show [release-id]",
+	Short: "Show release details",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		release, err := client.GetRelease(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(release)
+	},
+}
+
+var releasesCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a draft release",
+	Long: `Create a draft release for a product. Upload artifacts with
+'keygen artifacts upload' and then publish it with 'keygen releases publish'.
+
+Examples:
+  keygen releases create --product <id> --version 1.2.0 --channel stable
+  keygen releases create --product <id> --version 2.0.0-beta.1 --channel beta --name "2.0 Beta"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		productID, _ := cmd.Flags().GetString("product")
+		version, _ := cmd.Flags().GetString("version")
+		if productID == "" || version == "" {
+			output.Error("--product and --version are required")
+			return
+		}
+		if _, err := semver.Parse(version); err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		channel, _ := cmd.Flags().GetString("channel")
+		attrs := map[string]interface{}{
+			"version": version,
+			"channel": channel,
+		}
+		if v, _ := cmd.Flags().GetString("name"); v != "" {
+			attrs["name"] = v
+		}
+		if v, _ := cmd.Flags().GetString("tag"); v != "" {
+			attrs["tag"] = v
+		}
+		if v, _ := cmd.Flags().GetString("description"); v != "" {
+			attrs["description"] = v
+		}
+
+		release, err := client.CreateRelease(productID, attrs)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(release)
+	},
+}
+
+var releasesPublishCmd = &cobra.Command{
+	Use:   "publish [release-id]",
+	Short: "Publish a draft release",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		release, err := client.PublishRelease(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(release)
+	},
+}
+
+var releasesYankCmd = &cobra.Command{
+	Use:   "yank [release-id]",
+	Short: "Yank a published release",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		release, err := client.YankRelease(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(release)
+	},
+}
+
+var releasesDeleteCmd = &cobra.Command{
+	Use:   "delete [release-id]",
+	Short: "Delete a release and its artifacts",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		release, err := client.GetRelease(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if !force {
+			output.Success(releaseDeletePreview{
+				Action:  "delete",
+				ID:      release.ID,
+				Version: release.Version,
+				Channel: release.Channel,
+				Status:  release.Status,
+				Confirm: "use --force to confirm deletion",
+			})
+			return
+		}
+
+		if err := client.DeleteRelease(release.ID); err != nil {
+			output.Error("delete failed: " + err.Error())
+			return
+		}
+
+		output.Success(releaseDeleteOutput{
+			Deleted: true,
+			ID:      release.ID,
+			Version: release.Version,
+		})
+	},
+}
+
+// releaseFilterParams builds the server-side filters shared by list and latest.
+func releaseFilterParams(cmd *cobra.Command) map[string]string {
+	params := make(map[string]string)
+	if v, _ := cmd.Flags().GetString("product"); v != "" {
+		params["product"] = v
+	}
+	if v, _ := cmd.Flags().GetString("channel"); v != "" {
+		params["channel"] = v
+	}
+	if v, _ := cmd.Flags().GetString("platform"); v != "" {
+		params["platform"] = v
+	}
+	if cmd.Flags().Lookup("status") != nil {
+		if v, _ := cmd.Flags().GetString("status"); v != "" {
+			params["status"] = strings.ToUpper(v)
+		}
+	}
+	return params
+}
+
+func filterReleasesByVersion(releases []api.Release, constraint string) ([]api.Release, error) {
+	if constraint == "" {
+		return releases, nil
+	}
+
+	var matched []api.Release
+	for _, r := range releases {
+		ok, err := semver.Match(r.Version, constraint)
+		if err != nil {
+			// Skip releases with non-semver versions, but surface bad constraints
+			if _, perr := semver.Parse(r.Version); perr != nil {
+				continue
+			}
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+func init() {
+	releasesListCmd.Flags().String("product", "", "Filter by product ID")
+	releasesListCmd.Flags().String("channel", "", "Filter by channel (stable, rc, beta, alpha, dev)")
+	releasesListCmd.Flags().String("platform", "", "Filter by platform")
+	releasesListCmd.Flags().String("status", "", "Filter by status (draft, published, yanked)")
+	releasesListCmd.Flags().String("version", "", "Version constraint, e.g. \">=1.2.0 <2.0.0\"")
+	releasesListCmd.Flags().Int("limit", 10, "Results per page")
+	releasesListCmd.Flags().Int("page", 1, "Page number")
+
+	releasesLatestCmd.Flags().String("product", "", "Filter by product ID")
+	releasesLatestCmd.Flags().String("channel", "stable", "Release channel")
+	releasesLatestCmd.Flags().String("platform", "", "Filter by platform")
+	releasesLatestCmd.Flags().String("version", "", "Version constraint, e.g. \"<3.0.0\"")
+
+	releasesCreateCmd.Flags().String("product", "", "Product ID (required)")
+	releasesCreateCmd.Flags().String("version", "", "Semantic version (required)")
+	releasesCreateCmd.Flags().String("channel", "stable", "Release channel")
+	releasesCreateCmd.Flags().String("name", "", "Human-readable name")
+	releasesCreateCmd.Flags().String("tag", "", "Unique tag")
+	releasesCreateCmd.Flags().String("description", "", "Release notes")
+
+	releasesDeleteCmd.Flags().Bool("force", false, "Skip confirmation")
+
+	releasesCmd.AddCommand(releasesListCmd)
+	releasesCmd.AddCommand(releasesLatestCmd)
+	releasesCmd.AddCommand(releasesShowCmd)
+	releasesCmd.AddCommand(releasesCreateCmd)
+	releasesCmd.AddCommand(releasesPublishCmd)
+	releasesCmd.AddCommand(releasesYankCmd)
+	releasesCmd.AddCommand(releasesDeleteCmd)
+	rootCmd.AddCommand(releasesCmd)
+}
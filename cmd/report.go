@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render the account summary as an HTML or Markdown report",
+	Long: `Render the result of 'keygen status' through a report template: totals,
+status breakdown, top quota consumers, licenses expiring within
+--expiring-within and a quota bar per license.
+
+--template takes a built-in template name or the path of your own template
+file. The built-in "weekly" template comes in HTML and Markdown; the
+Markdown variant is used when --out ends in .md or with --markdown.
+Template files ending in .md or .markdown are rendered with text/template,
+all others with html/template.
+
+Templates receive the same data model as the built-ins, with fields
+addressed by their Go names:
+  .Title .Generated .AccountID .UserEmail .ExpiringDays .Warnings
+  .Totals       {Licenses Users Products Machines Components}
+  .Statuses     [{Status Count Percent}]
+  .Licenses     [{ID Key Name Status Owner ProductID PolicyID Expiry
+                  DaysRemaining Machines Utilization Quotas}]
+  .TopConsumers and .Expiring, lists of licenses
+  Quotas        [{Resource Used Limit Percent}]
+and the functions pct, width, bar, date, md, lower and upper.
+
+Without --out the report is written to stdout.
+
+Examples:
+  keygen report --template weekly --out report.html
+  keygen report --template weekly --out report.md
+  keygen report --template weekly --markdown --user admin@example.com
+  keygen report --template ./team.html.tmpl --out team.html --top 5`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		name, _ := cmd.Flags().GetString("template")
+		out, _ := cmd.Flags().GetString("out")
+		markdown, _ := cmd.Flags().GetBool("markdown")
+		tmpl, err := report.Load(name, markdown || report.IsMarkdown(out))
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		within, _ := cmd.Flags().GetString("expiring-within")
+		window, err := parseDays(within)
+		if err != nil {
+			output.Error("--expiring-within: " + err.Error())
+			return
+		}
+		top, _ := cmd.Flags().GetInt("top")
+		title, _ := cmd.Flags().GetString("title")
+
+		userFilter, _ := cmd.Flags().GetString("user")
+		result, details, warnings, err := accountStatus(client, cfg, userFilter)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		data := &report.Data{
+			Title:        title,
+			Generated:    time.Now(),
+			AccountID:    cfg.AccountID,
+			ExpiringDays: int(window.Hours() / 24),
+			Warnings:     warnings,
+			Statuses:     report.Statuses(result.LicenseStatuses),
+			UserEmail:    result.UserEmail,
+		}
+		data.Totals.Licenses = result.TotalLicenses
+		if result.TotalUsers != nil {
+			data.Totals.Users = *result.TotalUsers
+			data.Totals.Products = *result.TotalProducts
+		}
+		data.Totals.Machines = result.TotalMachines
+		data.Totals.Components = result.TotalComponents
+		for _, d := range details {
+			data.Licenses = append(data.Licenses, reportLicense(d))
+		}
+		data.TopConsumers = report.TopConsumers(data.Licenses, top)
+		data.Expiring = report.Expiring(data.Licenses, data.Generated, data.Generated.Add(window))
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			output.Error(err.Error())
+			return
+		}
+		if out == "" {
+			os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := os.WriteFile(out, buf.Bytes(), 0644); err != nil {
+			output.Error(fmt.Sprintf("writing report: %v", err))
+			return
+		}
+		output.Success(reportOutput{
+			Path:     out,
+			Template: tmpl.Name,
+			Markdown: tmpl.Markdown,
+			Bytes:    buf.Len(),
+			Licenses: len(data.Licenses),
+			Expiring: len(data.Expiring),
+		})
+	},
+}
+
+// reportLicense converts a status row to the report model.
+func reportLicense(d licenseDetail) report.License {
+	l := report.License{
+		ID:            d.ID,
+		Key:           d.Key,
+		Name:          d.Name,
+		Status:        d.Status,
+		Owner:         d.OwnerEmail,
+		ProductID:     d.ProductID,
+		PolicyID:      d.PolicyID,
+		Expiry:        d.Expiry,
+		DaysRemaining: d.DaysRemaining,
+		Machines:      d.Machines,
+	}
+	for _, q := range d.quotas() {
+		rq := report.NewQuota(q.Resource, q.Used, q.Limit)
+		l.Quotas = append(l.Quotas, rq)
+		if rq.Percent > l.Utilization {
+			l.Utilization = rq.Percent
+		}
+	}
+	return l
+}
+
+func init() {
+	reportCmd.Flags().String("template", "weekly", "Built-in template ("+strings.Join(report.Builtins(), ", ")+") or template file")
+	reportCmd.Flags().String("out", "", "Write the report to this file instead of stdout")
+	reportCmd.Flags().Bool("markdown", false, "Use the Markdown variant of a built-in template")
+	reportCmd.Flags().String("user", "", "Scope the report to a user (ID or email)")
+	reportCmd.Flags().String("expiring-within", "30d", "Window for the expiring licenses section, e.g. 30d")
+	reportCmd.Flags().Int("top", 10, "Number of top consumers to list")
+	reportCmd.Flags().String("title", "Keygen license report", "Report title")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var reportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Account reports",
+}
+
+var reportsExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "Licenses expiring within a time window, grouped for renewal follow-up",
+	Long: `List the licenses that expire within --within, grouped by owner, product
+or policy, with their machine and component counts.
+
+Licenses that were renewed before are flagged from the license.renewed
+event logs. Event logs need a Keygen plan that includes them; without
+them the renewed column is left empty and a note is added.
+
+Use --format markdown or html for a report that can be pasted into an
+email, or table/csv for spreadsheets.
+
+Available fields:
+  group, key, name, status, owner, product, policy, expiry, days,
+  machines, components, renewed, last_renewed
+
+Examples:
+  keygen reports expiring --within 30d
+  keygen reports expiring --within 90d --group-by product --format table
+  keygen reports expiring --within 60d --format html > expiring.html
+  keygen reports expiring --within 30d --where 'renewed=0' --format markdown`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		within, _ := cmd.Flags().GetString("within")
+		window, err := parseDays(within)
+		if err != nil {
+			output.Error("--within: " + err.Error())
+			return
+		}
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		if groupBy != "owner" && groupBy != "product" && groupBy != "policy" {
+			output.Error("--group-by must be owner, product or policy")
+			return
+		}
+
+		params := map[string]string{}
+		if v, _ := cmd.Flags().GetString("product"); v != "" {
+			params["product"] = v
+		}
+		if v, _ := cmd.Flags().GetString("policy"); v != "" {
+			params["policy"] = v
+		}
+		licenses, err := listAll(client.ListLicenses, params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		now := time.Now()
+		cutoff := now.Add(window)
+		skipRenewals, _ := cmd.Flags().GetBool("no-renewals")
+		items, notes := expiringLicenses(client, licenses, now, cutoff, !skipRenewals)
+		for i := range items {
+			items[i].Group = items[i].groupName(groupBy)
+		}
+
+		items, err = expiringColumns.Filter(items, listOpts)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		if len(listOpts.Sort) == 0 {
+			sort.SliceStable(items, func(i, j int) bool {
+				if items[i].Group != items[j].Group {
+					return strings.ToLower(items[i].Group) < strings.ToLower(items[j].Group)
+				}
+				return items[i].Expiry < items[j].Expiry
+			})
+		}
+
+		res, err := expiringColumns.Apply(items, columns.Options{Fields: listOpts.Fields})
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		groups := []*expiringGroup{}
+		index := map[string]*expiringGroup{}
+		for i, item := range items {
+			g, ok := index[item.Group]
+			if !ok {
+				g = &expiringGroup{Group: item.Group, Licenses: []interface{}{}}
+				index[item.Group] = g
+				groups = append(groups, g)
+			}
+			g.Count++
+			g.Licenses = append(g.Licenses, res.Items[i])
+		}
+
+		output.SuccessTable(reportsExpiringOutput{
+			Within:  within,
+			Cutoff:  cutoff.UTC().Format(time.RFC3339),
+			GroupBy: groupBy,
+			Total:   len(items),
+			Groups:  groups,
+			Notes:   notes,
+		}, res.Headers, res.Rows)
+	},
+}
+
+// expiringLicense is one row of 'reports expiring'.
+type expiringLicense struct {
+	ID            string `json:"id"`
+	Key           string `json:"key"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Expiry        string `json:"expiry"`
+	DaysRemaining int    `json:"days_remaining"`
+	OwnerID       string `json:"owner_id,omitempty"`
+	OwnerEmail    string `json:"owner_email,omitempty"`
+	ProductID     string `json:"product_id,omitempty"`
+	Product       string `json:"product,omitempty"`
+	PolicyID      string `json:"policy_id,omitempty"`
+	Policy        string `json:"policy,omitempty"`
+	Machines      int    `json:"machines"`
+	Components    int    `json:"components"`
+	// Renewals is nil when event logs aren't available.
+	Renewals    *int   `json:"renewals"`
+	LastRenewed string `json:"last_renewed,omitempty"`
+	Group       string `json:"group"`
+}
+
+func (l expiringLicense) groupName(by string) string {
+	var name string
+	switch by {
+	case "owner":
+		name = firstNonEmpty(l.OwnerEmail, l.OwnerID)
+	case "product":
+		name = firstNonEmpty(l.Product, l.ProductID)
+	case "policy":
+		name = firstNonEmpty(l.Policy, l.PolicyID)
+	}
+	if name == "" {
+		return "(no " + by + ")"
+	}
+	return name
+}
+
+var expiringColumns = columns.NewSet([]string{"group", "key", "name", "owner", "product", "policy", "expiry", "days", "machines", "components", "renewed"},
+	columns.Column[expiringLicense]{Name: "days", Header: "DAYS", Value: func(l expiringLicense) interface{} { return l.DaysRemaining }},
+	columns.Column[expiringLicense]{Name: "owner", Header: "OWNER", Value: func(l expiringLicense) interface{} { return firstNonEmpty(l.OwnerEmail, l.OwnerID) }},
+	columns.Column[expiringLicense]{Name: "product", Header: "PRODUCT", Value: func(l expiringLicense) interface{} { return firstNonEmpty(l.Product, l.ProductID) }},
+	columns.Column[expiringLicense]{Name: "policy", Header: "POLICY", Value: func(l expiringLicense) interface{} { return firstNonEmpty(l.Policy, l.PolicyID) }},
+	columns.Column[expiringLicense]{
+		Name:   "renewed",
+		Header: "RENEWED",
+		Value: func(l expiringLicense) interface{} {
+			if l.Renewals == nil {
+				return nil
+			}
+			return *l.Renewals
+		},
+		Cell: func(l expiringLicense) string {
+			switch {
+			case l.Renewals == nil:
+				return ""
+			case *l.Renewals == 0:
+				return "no"
+			}
+			return fmt.Sprintf("yes (%d)", *l.Renewals)
+		},
+	},
+)
+
+// expiringLicenses returns the licenses expiring between now and cutoff with
+// their owner, product, policy, machine and renewal details. Lookups that
+// fail only leave those details empty; the returned notes explain why.
+func expiringLicenses(client *api.Client, licenses []api.License, now, cutoff time.Time, renewals bool) ([]expiringLicense, []string) {
+	var notes []string
+
+	products := map[string]string{}
+	if list, err := client.ListProducts(); err == nil {
+		for _, p := range list {
+			products[p.ID] = p.Name
+		}
+	}
+	policies := map[string]string{}
+	if list, err := listAll(client.ListPolicies, map[string]string{}); err == nil {
+		for _, p := range list {
+			policies[p.ID] = p.Name
+		}
+	}
+	owners := map[string]string{}
+
+	items := []expiringLicense{}
+	for _, lic := range licenses {
+		if lic.Expiry == "" {
+			continue
+		}
+		expiry, err := time.Parse(time.RFC3339, lic.Expiry)
+		if err != nil || expiry.Before(now) || expiry.After(cutoff) {
+			continue
+		}
+
+		item := expiringLicense{
+			ID:            lic.ID,
+			Key:           lic.Key,
+			Name:          lic.Name,
+			Status:        strings.ToUpper(lic.Status),
+			Expiry:        lic.Expiry,
+			DaysRemaining: int(math.Max(0, expiry.Sub(now).Hours()/24)),
+			OwnerID:       lic.OwnerID,
+			ProductID:     lic.ProductID,
+			Product:       products[lic.ProductID],
+			PolicyID:      lic.PolicyID,
+			Policy:        policies[lic.PolicyID],
+		}
+
+		if lic.OwnerID != "" {
+			email, ok := owners[lic.OwnerID]
+			if !ok {
+				if u, err := client.GetUser(lic.OwnerID); err == nil {
+					email = u.Email
+				}
+				owners[lic.OwnerID] = email
+			}
+			item.OwnerEmail = email
+		}
+
+		if machines, err := client.GetLicenseMachines(lic.ID); err == nil {
+			item.Machines = len(machines)
+			for _, m := range machines {
+				item.Components += len(m.Components)
+			}
+		}
+
+		if renewals {
+			events, err := listAll(client.ListEventLogs, map[string]string{
+				"event":          "license.renewed",
+				"resource[type]": "licenses",
+				"resource[id]":   lic.ID,
+			})
+			if err != nil {
+				// Event logs are plan-dependent; stop asking after the first failure.
+				notes = append(notes, "renewal history unavailable: "+err.Error())
+				renewals = false
+			} else {
+				n := len(events)
+				item.Renewals = &n
+				for _, e := range events {
+					if e.Created > item.LastRenewed {
+						item.LastRenewed = e.Created
+					}
+				}
+			}
+		}
+
+		items = append(items, item)
+	}
+	return items, notes
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func init() {
+	reportsExpiringCmd.Flags().String("within", "30d", "Time window, e.g. 30d, 60d, 90d")
+	reportsExpiringCmd.Flags().String("group-by", "owner", "Group by owner, product or policy")
+	reportsExpiringCmd.Flags().String("product", "", "Only licenses for this product ID")
+	reportsExpiringCmd.Flags().String("policy", "", "Only licenses for this policy ID")
+	reportsExpiringCmd.Flags().Bool("no-renewals", false, "Skip the renewal history lookup")
+
+	reportsCmd.AddCommand(reportsExpiringCmd)
+	rootCmd.AddCommand(reportsCmd)
+}
@@ -3,21 +3,36 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
 	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile     string
-	format      string
-	quiet       bool
-	verbose     bool
-	envFile     string
-	accountID   string
-	baseURL     string
-	token       string
-	profileName string
+	cfgFile      string
+	format       string
+	tmplText     string
+	tmplFile     string
+	listFields   string
+	listSort     string
+	listWhere    string
+	queryExpr    string
+	rawOutput    bool
+	listOpts     columns.Options
+	quiet        bool
+	verbose      bool
+	envFile      string
+	accountID    string
+	baseURL      string
+	token        string
+	profileName  string
+	noCache      bool
+	refreshCache bool
+	cacheTTL     string
+	offlineMode  bool
 
 	Version = "dev"
 )
@@ -37,8 +52,46 @@ Examples:
   keygen licenses list --profile prod
   keygen status --profile prod --format table
 
-All output is JSON by default. Use --format table or --format csv for alternatives.`,
+All output is JSON by default. Other formats:
+  --format table|csv     Human-readable table or CSV
+  --format markdown|html Table as Markdown or HTML, e.g. for email
+  --format ndjson        One resource per line
+  --format yaml          The JSON output as YAML
+  --format template      Go template per resource, e.g.
+                         --template '{{.Key}} {{.Expiry}}' or --template-file
+  --format openmetrics   Metrics text for 'keygen status'
+
+Templates see each resource's Go fields ({{.Key}}, {{.Expiry}}) and have
+the functions json, upper, lower and join.
+
+List commands accept --fields, --sort and --where in every format:
+  --fields id,key,status,metadata.maxDevices
+  --sort days:desc,name
+  --where 'status=ACTIVE && days<30'
+
+--query evaluates a JMESPath expression over the JSON envelope, so jq isn't
+needed; --raw prints strings unquoted, one list element per line:
+  keygen licenses list --query "data[?status=='ACTIVE'].key" --raw`,
 	Version: Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := output.Configure(format, tmplText, tmplFile); err != nil {
+			exitError(err.Error())
+		}
+		if err := output.SetQuery(queryExpr, rawOutput); err != nil {
+			exitError(err.Error())
+		}
+		opts, err := columns.ParseOptions(listFields, listSort, listWhere)
+		if err != nil {
+			exitError(err.Error())
+		}
+		listOpts = opts
+		if offlineMode && cmd.Annotations[offlineAnnotation] == "" {
+			exitError(cmd.CommandPath() + " can't run --offline; see 'keygen sync --help' for the commands that can")
+		}
+		if getFormat() == "openmetrics" && cmd.Annotations[openMetricsAnnotation] == "" {
+			exitError(cmd.CommandPath() + " doesn't support --format openmetrics; only 'keygen status' does")
+		}
+	},
 }
 
 func Execute() {
@@ -54,13 +107,24 @@ func SetVersion(v string) {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Named profile to use (required for all authenticated commands)")
-	rootCmd.PersistentFlags().StringVar(&format, "format", "json", "Output format: json, table, csv")
+	rootCmd.PersistentFlags().StringVar(&format, "format", "json", "Output format: "+strings.Join(output.Formats, ", "))
+	rootCmd.PersistentFlags().StringVar(&tmplText, "template", "", "Go template for --format template")
+	rootCmd.PersistentFlags().StringVar(&tmplFile, "template-file", "", "File containing a Go template for --format template")
+	rootCmd.PersistentFlags().StringVar(&listFields, "fields", "", "Comma-separated fields to show in list output")
+	rootCmd.PersistentFlags().StringVar(&listSort, "sort", "", "Sort list output by field[:desc], comma-separated")
+	rootCmd.PersistentFlags().StringVar(&listWhere, "where", "", "Filter list output, e.g. 'status=ACTIVE && days<30'")
+	rootCmd.PersistentFlags().StringVar(&queryExpr, "query", "", "JMESPath expression applied to the JSON output")
+	rootCmd.PersistentFlags().BoolVar(&rawOutput, "raw", false, "Print --query string results unquoted")
 	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Suppress non-essential output")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Show verbose output")
 	rootCmd.PersistentFlags().StringVar(&envFile, "env-file", "", "Path to .env file")
 	rootCmd.PersistentFlags().StringVar(&accountID, "account-id", "", "Keygen account ID")
 	rootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "", "Keygen API base URL")
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Keygen API token")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Don't read or store cached API responses")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Refetch cached API responses")
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Answer from the profile's last 'keygen sync' instead of the server")
+	rootCmd.PersistentFlags().StringVar(&cacheTTL, "cache-ttl", "", "How long cached responses are used without revalidation, e.g. 30s (default 0: always revalidate, or the profile's cache_ttl)")
 }
 
 // loadConfig loads the active profile. Requires --profile to be set.
@@ -97,12 +161,65 @@ func loadConfig() *config.Config {
 	if token != "" {
 		cfg.Token = token
 	}
+	if cacheTTL != "" {
+		cfg.CacheTTL = cacheTTL
+	}
+	cfg.NoCache = noCache
+	cfg.RefreshCache = refreshCache
+
+	return cfg
+}
+
+// loadConfigForWrite loads the active profile for commands that change a
+// resource based on what they read first, like the expiry a renewal starts
+// from or the component a move deletes. Those reads skip the cache, since a
+// change made from a stale copy can't be undone by refetching later.
+func loadConfigForWrite() *config.Config {
+	cfg := loadConfig()
+	cfg.RefreshCache = true
+	return cfg
+}
 
+// loadConfigRevalidated loads the active profile for commands that read the
+// whole account, like snapshot, apply, import and sync. Every cached read is
+// revalidated, so they see the current state while unchanged collections
+// still cost only a 304.
+func loadConfigRevalidated() *config.Config {
+	cfg := loadConfig()
+	cfg.CacheTTL = "0"
 	return cfg
 }
 
+// printList renders a list through its column registry, applying --fields,
+// --sort and --where.
+func printList[T any](set *columns.Set[T], items []T) {
+	res, err := set.Apply(items, listOpts)
+	if err != nil {
+		output.Error(err.Error())
+		return
+	}
+	output.SuccessListTable(res.Items, len(res.Items), res.Headers, res.Rows)
+}
+
+// listAll fetches every page of a list endpoint, 100 records at a time.
+func listAll[T any](fetch func(map[string]string) ([]T, error), params map[string]string) ([]T, error) {
+	var all []T
+	params["page[size]"] = "100"
+	for page := 1; ; page++ {
+		params["page[number]"] = fmt.Sprintf("%d", page)
+		batch, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < 100 {
+			return all, nil
+		}
+	}
+}
+
 func getFormat() string {
-	return format
+	return output.Format()
 }
 
 func exitError(msg string) {
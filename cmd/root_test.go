@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestOpenMetricsCommands(t *testing.T) {
+	var supported []string
+	walkCommands(rootCmd, func(c *cobra.Command) {
+		if c.Annotations[openMetricsAnnotation] != "" {
+			supported = append(supported, c.CommandPath())
+		}
+	})
+	if len(supported) != 1 || supported[0] != "keygen status" {
+		t.Errorf("commands allowing --format openmetrics: %v, want only keygen status", supported)
+	}
+}
+
+func TestReportTemplateShadowsRootTemplate(t *testing.T) {
+	c, _, err := rootCmd.Find([]string{"report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFlags([]string{"--template", "./team.html.tmpl"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Flags().Set("template", "weekly") })
+
+	if got, _ := c.Flags().GetString("template"); got != "./team.html.tmpl" {
+		t.Errorf("report --template = %q", got)
+	}
+	if tmplText != "" {
+		t.Errorf("report --template also set the root --template to %q", tmplText)
+	}
+}
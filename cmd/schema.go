@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/cache"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/schema"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// commandOutput describes the data payload a command prints.
+type commandOutput struct {
+	data interface{}
+	list bool
+	// stream marks commands that print data as NDJSON, one record per line,
+	// without the envelope.
+	stream bool
+	// note describes the output of commands that print no data, like
+	// servers, which only print errors.
+	note string
+	// oneOf lists the shapes of commands that print more than one, like a
+	// preview without --force and the result with it.
+	oneOf []commandOutput
+}
+
+// either is the output of a command that prints one of several shapes.
+func either(outs ...commandOutput) commandOutput {
+	return commandOutput{oneOf: outs}
+}
+
+// commandOutputs maps command paths to the type of their data payload.
+// Every runnable command must be listed.
+var commandOutputs = map[string]commandOutput{
+	"apply":                     {data: applyOutput{}},
+	"plan":                      {data: planOutput{}},
+	"diff":                      {data: diffOutput{}},
+	"migrate":                   {data: migrateOutput{}},
+	"import":                    {data: importOutput{}},
+	"sync":                      {data: syncOutput{}},
+	"snapshot export":           {data: snapshotExportOutput{}},
+	"snapshot restore":          {data: snapshotRestoreOutput{}},
+	"status":                    {data: statusOutput{}},
+	"report":                    {data: reportOutput{}},
+	"reports expiring":          {data: reportsExpiringOutput{}},
+	"fingerprint":               {data: fingerprintOutput{}},
+	"schema":                    either(commandOutput{data: schemaOutput{}}, commandOutput{data: commandDescription{}}),
+	"login token":               {data: loginTokenOutput{}},
+	"login password":            {data: loginPasswordOutput{}},
+	"logout":                    {data: messageOutput{}},
+	"whoami":                    {data: whoamiOutput{}},
+	"config show":               {data: configShowOutput{}},
+	"config clear":              {data: messageOutput{}},
+	"profile list":              either(commandOutput{data: profileInfo{}, list: true}, commandOutput{data: profileListEmpty{}}),
+	"profile add":               {data: profileAddOutput{}},
+	"profile edit":              {data: profileEditOutput{}},
+	"profile delete":            {data: messageOutput{}},
+	"profile show":              {data: profileShowOutput{}},
+	"profile use":               {data: messageOutput{}},
+	"profile rename":            {data: profileRenameOutput{}},
+	"cache stats":               {data: cache.Stats{}},
+	"cache clear":               {data: cacheClearOutput{}},
+	"licenses list":             {data: api.License{}, list: true},
+	"licenses show":             {data: api.License{}},
+	"licenses status":           {data: licenseStatusOutput{}},
+	"licenses renew":            {data: licenseRenewOutput{}},
+	"licenses components":       {data: licenseComponent{}, list: true},
+	"licenses update":           {data: licenseUpdateOutput{}},
+	"licenses change-policy":    {data: licenseChangeOutput{}},
+	"licenses transfer":         {data: licenseChangeOutput{}},
+	"licenses set-owner":        {data: licenseChangeOutput{}},
+	"licenses usage":            {data: licenseUsageOutput{}},
+	"machines list":             {data: api.Machine{}, list: true},
+	"machines activate":         {data: machineActivateOutput{}},
+	"machines stale":            either(commandOutput{data: machinesStalePreview{}}, commandOutput{data: staleMachine{}, list: true}),
+	"components list":           {data: api.Component{}, list: true},
+	"components add":            {data: api.Component{}},
+	"components check":          either(commandOutput{data: componentCheckOutput{}}, commandOutput{data: componentCheckLocalOutput{}}),
+	"components delete":         either(commandOutput{data: componentDeletePreview{}}, commandOutput{data: componentDeleteOutput{}}),
+	"components rename":         {data: componentRenameOutput{}},
+	"components move":           either(commandOutput{data: componentMovePreview{}}, commandOutput{data: componentMoveOutput{}}),
+	"users list":                {data: api.User{}, list: true},
+	"users show":                {data: api.User{}},
+	"users status":              {data: userStatusOutput{}},
+	"users update":              {data: userUpdateOutput{}},
+	"tokens list":               {data: api.Token{}, list: true},
+	"releases list":             {data: api.Release{}, list: true},
+	"releases latest":           {data: api.Release{}},
+	"releases show":             {data: api.Release{}},
+	"releases create":           {data: api.Release{}},
+	"releases publish":          {data: api.Release{}},
+	"releases yank":             {data: api.Release{}},
+	"releases delete":           either(commandOutput{data: releaseDeletePreview{}}, commandOutput{data: releaseDeleteOutput{}}),
+	"artifacts list":            {data: api.Artifact{}, list: true},
+	"artifacts upload":          {data: artifactUploadOutput{}},
+	"artifacts download":        {data: artifactDownloadOutput{}},
+	"processes list":            {data: api.Process{}, list: true},
+	"processes kill":            either(commandOutput{data: processKillPreview{}}, commandOutput{data: processKillOutput{}}),
+	"webhooks endpoints list":   {data: api.WebhookEndpoint{}, list: true},
+	"webhooks endpoints create": {data: api.WebhookEndpoint{}},
+	"webhooks endpoints update": {data: api.WebhookEndpoint{}},
+	"webhooks endpoints delete": either(commandOutput{data: endpointDeletePreview{}}, commandOutput{data: endpointDeleteOutput{}}),
+	"webhooks events list":      {data: api.WebhookEvent{}, list: true},
+	"webhooks events show":      {data: webhookEventShowOutput{}},
+	"webhooks events retry":     {data: webhookRetryOutput{}},
+	"webhooks verify":           {data: webhookVerifyOutput{}},
+	"webhooks replay":           {data: webhookReplayOutput{}},
+	"webhooks listen":           {data: webhookListenRecord{}, stream: true},
+	"logs requests":             {data: api.RequestLog{}, list: true},
+	"logs events":               {data: api.EventLog{}, list: true},
+	"exporter":                  {note: "Serves OpenMetrics on /metrics until interrupted; prints nothing to stdout but errors"},
+	"serve":                     {note: "Serves the HTTP gateway until interrupted; prints nothing to stdout but errors"},
+	"mcp serve":                 {note: "Speaks MCP (JSON-RPC 2.0) on stdin and stdout, one message per line"},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [command...]",
+	Short: "Describe commands, flags and output as JSON",
+	Long: `Describe every command as JSON for agents and scripts: arguments,
+flags with types and defaults, examples, and a JSON Schema for the output
+envelope and its data payload.
+
+Pass a command path to describe just that command. List output selected
+with --fields is an array of objects holding only those fields.
+
+Examples:
+  keygen schema
+  keygen schema licenses list
+  keygen schema --query "data.commands[].path" --raw`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			target, rest, err := rootCmd.Find(args)
+			if err != nil || len(rest) > 0 || target == rootCmd {
+				output.Error(fmt.Sprintf("unknown command %q", strings.Join(args, " ")))
+				return
+			}
+			output.Success(describeCommand(target))
+			return
+		}
+
+		var commands []commandDescription
+		walkCommands(rootCmd, func(c *cobra.Command) {
+			commands = append(commands, describeCommand(c))
+		})
+
+		output.Success(schemaOutput{
+			Name:        rootCmd.Name(),
+			Version:     Version,
+			Formats:     output.Formats,
+			GlobalFlags: describeFlags(rootCmd.PersistentFlags()),
+			Commands:    commands,
+		})
+	},
+}
+
+// walkCommands calls fn for every visible runnable command below c.
+func walkCommands(c *cobra.Command, fn func(*cobra.Command)) {
+	for _, sub := range c.Commands() {
+		if sub.Hidden || sub.Name() == "help" || sub.Name() == "completion" {
+			continue
+		}
+		if sub.Runnable() {
+			fn(sub)
+		}
+		walkCommands(sub, fn)
+	}
+}
+
+func describeCommand(c *cobra.Command) commandDescription {
+	path := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+	return commandDescription{
+		Path:     path,
+		Use:      c.UseLine(),
+		Short:    c.Short,
+		Long:     c.Long,
+		Args:     describeArgs(c),
+		Flags:    describeFlags(c.LocalNonPersistentFlags()),
+		Examples: commandExamples(c),
+		Output:   outputSchema(commandOutputs[path]),
+	}
+}
+
+// outputSchema returns the JSON Schema of what a command prints on stdout.
+func outputSchema(out commandOutput) map[string]interface{} {
+	switch {
+	case len(out.oneOf) > 0:
+		alternatives := make([]interface{}, len(out.oneOf))
+		for i, o := range out.oneOf {
+			alt := outputSchema(o)
+			delete(alt, "$schema")
+			alternatives[i] = alt
+		}
+		return map[string]interface{}{"$schema": schema.Draft, "oneOf": alternatives}
+	case out.stream:
+		s := schema.For(out.data)
+		s["$schema"] = schema.Draft
+		s["description"] = "One JSON record per line"
+		return s
+	case out.note != "":
+		return map[string]interface{}{"$schema": schema.Draft, "description": out.note}
+	}
+	return schema.Envelope(schema.For(out.data), out.list)
+}
+
+// describeArgs lists the positional arguments named in the Use line and
+// probes the Args validator for how many are required.
+func describeArgs(c *cobra.Command) commandArgs {
+	fields := strings.Fields(c.Use)
+	names := []positionalArg{}
+	for _, f := range fields[1:] {
+		name := strings.Trim(f, "[]<>.")
+		arg := positionalArg{Name: name}
+		if strings.Contains(name, "|") {
+			arg.Enum = strings.Split(name, "|")
+		}
+		arg.Variadic = strings.HasSuffix(f, "...]") || strings.HasSuffix(f, "...")
+		names = append(names, arg)
+	}
+
+	d := commandArgs{Positional: names}
+	if c.Args == nil {
+		return d
+	}
+
+	probe := make([]string, len(names)+2)
+	min, max := -1, -1
+	for n := 0; n <= len(probe); n++ {
+		if c.Args(c, probe[:n]) == nil {
+			if min < 0 {
+				min = n
+			}
+			max = n
+		}
+	}
+	if min >= 0 {
+		d.Min = &min
+		if max < len(probe) {
+			d.Max = &max
+		}
+	}
+	return d
+}
+
+func describeFlags(fs *pflag.FlagSet) []flagDescription {
+	flags := []flagDescription{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		flags = append(flags, flagDescription{
+			Name:      f.Name,
+			Type:      f.Value.Type(),
+			Default:   f.DefValue,
+			Usage:     f.Usage,
+			Shorthand: f.Shorthand,
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// commandExamples returns the example invocations from a command's Example
+// field or the "Examples:" section of its long help.
+func commandExamples(c *cobra.Command) []string {
+	text := c.Example
+	if i := strings.Index(c.Long, "Examples:"); text == "" && i >= 0 {
+		text = c.Long[i+len("Examples:"):]
+	}
+
+	var examples []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, rootCmd.Name()+" ") {
+			examples = append(examples, line)
+		}
+	}
+	return examples
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestEveryCommandHasAnOutputSchema(t *testing.T) {
+	seen := map[string]bool{}
+	walkCommands(rootCmd, func(c *cobra.Command) {
+		path := strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" ")
+		seen[path] = true
+		out, ok := commandOutputs[path]
+		if !ok {
+			t.Errorf("%q has no entry in commandOutputs", path)
+			return
+		}
+		if out.data == nil && out.note == "" && len(out.oneOf) == 0 {
+			t.Errorf("%q has an empty output description", path)
+		}
+	})
+	for path := range commandOutputs {
+		if !seen[path] {
+			t.Errorf("commandOutputs lists %q, which is not a command", path)
+		}
+	}
+}
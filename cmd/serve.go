@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/gateway"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve license status over a local HTTP/JSON API",
+	Long: `Run a read-mostly HTTP/JSON API so dashboards and internal tools can
+query license state without holding the Keygen admin token.
+
+Routes (names are used by --allow):
+  license-status   GET /v1/licenses/{id}/status
+  user-status      GET /v1/users/{id-or-email}/status
+  component-check  GET /v1/components/{fingerprint}
+  quota            GET /v1/quota[?user=<id-or-email>]
+
+GET /healthz and GET /metrics (Prometheus) need no API key.
+
+Clients authenticate with "Authorization: Bearer <key>" or "X-API-Key: <key>".
+Keys come from --api-key or KEYGEN_SERVE_API_KEYS (comma-separated); use
+--no-auth to run without keys on a trusted host.
+
+Responses use the CLI's {"ok": ..., "data": ...} envelope and are cached
+for --cache-ttl.
+
+Examples:
+  keygen serve --listen :8080 --api-key s3cret --profile prod
+  keygen serve --listen 127.0.0.1:8080 --no-auth --allow license-status,quota
+  curl -H "X-API-Key: s3cret" localhost:8080/v1/licenses/<id>/status`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		// A long-running server must not answer from a stale cache
+		cfg.NoCache = true
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		keys, _ := cmd.Flags().GetStringSlice("api-key")
+		if v := os.Getenv("KEYGEN_SERVE_API_KEYS"); v != "" {
+			keys = append(keys, splitList(v)...)
+		}
+		noAuth, _ := cmd.Flags().GetBool("no-auth")
+		if len(keys) == 0 && !noAuth {
+			output.Error("an API key is required: use --api-key, KEYGEN_SERVE_API_KEYS, or --no-auth")
+			return
+		}
+		if len(keys) > 0 && noAuth {
+			output.Error("--no-auth can't be combined with API keys")
+			return
+		}
+
+		allow, _ := cmd.Flags().GetStringSlice("allow")
+		ttl, _ := cmd.Flags().GetDuration("cache-ttl")
+		gw, err := gateway.New(gatewayRoutes(client, cfg), gateway.Options{APIKeys: keys, Allow: allow, CacheTTL: ttl})
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		listen, _ := cmd.Flags().GetString("listen")
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           gw,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Serving %s on %s\n", strings.Join(gw.Routes(), ", "), listen)
+		}
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// gatewayRoutes are the routes of 'keygen serve'. Each returns the same data
+// as the matching CLI command.
+func gatewayRoutes(client *api.Client, cfg *config.Config) []gateway.Route {
+	return []gateway.Route{
+		{
+			Name:    "license-status",
+			Pattern: "GET /v1/licenses/{id}/status",
+			Handler: func(r *http.Request) (interface{}, error) {
+				result, _, err := licenseStatus(client, r.PathValue("id"))
+				return result, gatewayError(err)
+			},
+		},
+		{
+			Name:    "user-status",
+			Pattern: "GET /v1/users/{id}/status",
+			Handler: func(r *http.Request) (interface{}, error) {
+				result, err := userStatus(client, r.PathValue("id"))
+				return result, gatewayError(err)
+			},
+		},
+		{
+			Name:    "component-check",
+			Pattern: "GET /v1/components/{fingerprint}",
+			Handler: func(r *http.Request) (interface{}, error) {
+				fingerprint := r.PathValue("fingerprint")
+				comp, err := client.FindComponentByFingerprint(fingerprint)
+				if err != nil {
+					return nil, gatewayError(err)
+				}
+				result := map[string]interface{}{"fingerprint": fingerprint, "registered": comp != nil}
+				if comp != nil {
+					result["component"] = comp
+				}
+				return result, nil
+			},
+		},
+		{
+			Name:    "quota",
+			Pattern: "GET /v1/quota",
+			Handler: func(r *http.Request) (interface{}, error) {
+				_, details, warnings, err := accountStatus(client, cfg, r.URL.Query().Get("user"))
+				if err != nil {
+					return nil, gatewayError(err)
+				}
+				if details == nil {
+					details = []licenseDetail{}
+				}
+				if warnings == nil {
+					warnings = []string{}
+				}
+				return map[string]interface{}{"licenses": details, "usage_warnings": warnings}, nil
+			},
+		},
+	}
+}
+
+// gatewayError reports Keygen "not found" responses as 404s; other errors
+// stay 502s.
+func gatewayError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *api.APIError
+	if errors.Is(err, api.ErrUserNotFound) || (errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+		return &gateway.StatusError{Status: http.StatusNotFound, Err: err}
+	}
+	return err
+}
+
+func init() {
+	serveCmd.Flags().String("listen", ":8080", "Address to listen on")
+	serveCmd.Flags().StringSlice("api-key", nil, "API key clients must present (repeatable)")
+	serveCmd.Flags().Bool("no-auth", false, "Serve without API keys")
+	serveCmd.Flags().StringSlice("allow", nil, "Routes to serve (default all): license-status, user-status, component-check, quota")
+	serveCmd.Flags().Duration("cache-ttl", 30*time.Second, "How long to cache responses (0 disables)")
+	rootCmd.AddCommand(serveCmd)
+}
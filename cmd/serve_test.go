@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/gateway"
+)
+
+func TestGatewayError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int // 0 for no status, i.e. a 502
+	}{
+		{"keygen 404", &api.APIError{StatusCode: http.StatusNotFound, Title: "Not found"}, http.StatusNotFound},
+		{"wrapped keygen 404", fmt.Errorf("validating: %w", &api.APIError{StatusCode: http.StatusNotFound}), http.StatusNotFound},
+		{"unknown user", api.ErrUserNotFound, http.StatusNotFound},
+		{"keygen 500", &api.APIError{StatusCode: http.StatusInternalServerError}, 0},
+		{"network error", errors.New("connection refused"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := gatewayError(tt.err)
+			var se *gateway.StatusError
+			got := 0
+			if errors.As(err, &se) {
+				got = se.Status
+			}
+			if got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%v doesn't wrap %v", err, tt.err)
+			}
+		})
+	}
+	if gatewayError(nil) != nil {
+		t.Error("gatewayError(nil) is not nil")
+	}
+}
+
+func TestServeRoutes(t *testing.T) {
+	srv, reached := keygenStub(t)
+	client := api.NewClient(srv.URL, "acct", "token")
+	cfg := &config.Config{AccountID: "acct", BaseURL: srv.URL, Token: "token"}
+	gw, err := gateway.New(gatewayRoutes(client, cfg), gateway.Options{APIKeys: []string{"s3cret"}, Allow: []string{"license-status"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		key    string
+		want   int
+		keygen bool // whether the request reaches Keygen
+	}{
+		{"no key", "/v1/licenses/lic-1/status", "", http.StatusUnauthorized, false},
+		{"wrong key", "/v1/licenses/lic-1/status", "nope", http.StatusUnauthorized, false},
+		{"license unknown to keygen", "/v1/licenses/missing/status", "s3cret", http.StatusNotFound, true},
+		{"route outside the allow-list", "/v1/quota", "s3cret", http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*reached = nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.key)
+			}
+			rec := httptest.NewRecorder()
+			gw.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if got := len(*reached) > 0; got != tt.keygen {
+				t.Errorf("reached Keygen = %v, want %v: %v", got, tt.keygen, *reached)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export and restore point-in-time account backups",
+}
+
+var snapshotExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Dump products, policies, users, licenses, machines and components",
+	Long: `Write every product, policy, user, license, machine and component of the
+account to a versioned snapshot file, paging through each collection.
+Resources keep all of their attributes, including metadata, and the IDs of
+their related resources.
+
+The file is gzip-compressed when --out ends in .gz. Snapshots contain
+license keys, so the file is created readable by its owner only.
+
+Examples:
+  keygen snapshot export --out snap.json.gz --profile prod
+  keygen snapshot export --out before-migration.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigRevalidated()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		out, _ := cmd.Flags().GetString("out")
+		if out == "" {
+			out = "keygen-snapshot-" + time.Now().UTC().Format("20060102-150405") + ".json.gz"
+		}
+
+		snap, err := exportSnapshot(client, cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if err := snapshot.Write(out, snap); err != nil {
+			output.Error("writing snapshot: " + err.Error())
+			return
+		}
+		output.Success(snapshotExportOutput{
+			Path:      out,
+			Version:   snap.Version,
+			AccountID: snap.AccountID,
+			Created:   snap.Created,
+			Resources: snap.Counts(),
+		})
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Recreate or reconcile a snapshot's resources in the target profile",
+	Long: `Restore a snapshot into the account of the selected profile, which may be
+the account it came from or another one.
+
+Resources are restored in dependency order (products, policies, users,
+licenses, machines, components). Each is matched to an existing resource by
+ID, then by its natural key: product name, policy name within its product,
+user email, license key, or fingerprint within the license or machine.
+Relationships are rewritten to the matched or newly created IDs.
+
+--on-conflict decides what happens to resources that already exist:
+  skip    leave them as they are (default)
+  update  patch attributes that differ from the snapshot
+  fail    abort before making any change
+
+Relationships of existing resources are not changed. Use --dry-run to see
+the plan without writing anything.
+
+Examples:
+  keygen snapshot restore snap.json.gz --profile staging --dry-run
+  keygen snapshot restore snap.json.gz --profile staging --format table
+  keygen snapshot restore snap.json.gz --profile prod --on-conflict update`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigRevalidated()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		if onConflict != "skip" && onConflict != "update" && onConflict != "fail" {
+			output.Error("--on-conflict must be skip, update or fail")
+			return
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		snap, err := snapshot.Read(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		// With --on-conflict fail, find conflicts before anything is written
+		if onConflict == "fail" && !dryRun {
+			if actions, err := restoreSnapshot(client, snap, restoreOptions{OnConflict: onConflict, DryRun: true}); err != nil {
+				output.ErrorDetail(err.Error(), map[string]interface{}{"actions": actions})
+				return
+			}
+		}
+
+		actions, err := restoreSnapshot(client, snap, restoreOptions{OnConflict: onConflict, DryRun: dryRun})
+		if err != nil {
+			output.ErrorDetail(err.Error(), map[string]interface{}{"actions": actions})
+			return
+		}
+
+		summary := map[string]map[string]int{}
+		rows := make([][]string, len(actions))
+		for i, a := range actions {
+			if summary[a.Kind] == nil {
+				summary[a.Kind] = map[string]int{}
+			}
+			summary[a.Kind][a.Action]++
+			rows[i] = []string{a.Kind, a.Action, a.SourceID, a.TargetID, a.Key, a.Error}
+		}
+		output.SuccessTable(snapshotRestoreOutput{
+			Snapshot: snapshotInfo{
+				AccountID: snap.AccountID,
+				Created:   snap.Created,
+				Version:   snap.Version,
+			},
+			DryRun:     dryRun,
+			OnConflict: onConflict,
+			Summary:    summary,
+			Actions:    actions,
+		}, []string{"KIND", "ACTION", "SOURCE", "TARGET", "KEY", "ERROR"}, rows)
+	},
+}
+
+// exportSnapshot fetches every resource of the snapshot kinds.
+func exportSnapshot(client *api.Client, cfg *config.Config) (*snapshot.Snapshot, error) {
+	snap := &snapshot.Snapshot{
+		Format:    snapshot.Format,
+		Version:   snapshot.Version,
+		Created:   time.Now().UTC().Format(time.RFC3339),
+		AccountID: cfg.AccountID,
+		BaseURL:   cfg.BaseURL,
+		Resources: map[string][]snapshot.Resource{},
+	}
+	for _, kind := range snapshot.Kinds {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Exporting %s\n", kind.Name)
+		}
+		resources, err := listResources(client, kind)
+		if err != nil {
+			return nil, fmt.Errorf("exporting %s: %w", kind.Name, err)
+		}
+		items := make([]snapshot.Resource, len(resources))
+		for i, res := range resources {
+			items[i] = snapshotResource(res)
+		}
+		snap.Resources[kind.Name] = items
+	}
+	return snap, nil
+}
+
+// restoreAction is what restore did, or would do, with one resource.
+type restoreAction struct {
+	Kind     string `json:"kind"`
+	Action   string `json:"action"` // create, update, unchanged, skip, done, conflict, failed
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id,omitempty"`
+	Key      string `json:"key"`
+	// Changes lists the attributes an update sets.
+	Changes []string `json:"changes,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// restoreOptions control restoreSnapshot.
+type restoreOptions struct {
+	OnConflict string
+	DryRun     bool
+	// IDs maps source IDs to target IDs per kind. It may hold the mapping of
+	// an earlier, interrupted run and is updated as resources are matched
+	// or created, except on dry runs.
+	IDs map[string]map[string]string
+	// Save is called whenever IDs changes, e.g. to persist the mapping.
+	Save func() error
+}
+
+// restoreSnapshot creates or reconciles the snapshot's resources in the
+// client's account. Resources that fail are recorded and skipped, along
+// with those that depend on them; an error is only returned when restore
+// can't continue, e.g. on a conflict under the fail policy.
+func restoreSnapshot(client *api.Client, snap *snapshot.Snapshot, opts restoreOptions) ([]restoreAction, error) {
+	actions := []restoreAction{}
+	ids := opts.IDs
+	if ids == nil || opts.DryRun {
+		// Dry runs map to placeholders, so they work on a copy
+		ids = map[string]map[string]string{}
+		for kind, m := range opts.IDs {
+			ids[kind] = map[string]string{}
+			for src, dst := range m {
+				ids[kind][src] = dst
+			}
+		}
+	}
+	save := func() error {
+		if opts.Save == nil || opts.DryRun {
+			return nil
+		}
+		return opts.Save()
+	}
+	// existing holds the IDs present in the target per kind
+	existing := map[string]map[string]bool{}
+
+	for _, kind := range snapshot.Kinds {
+		if ids[kind.Name] == nil {
+			ids[kind.Name] = map[string]string{}
+		}
+		existing[kind.Name] = map[string]bool{}
+
+		targets, err := listResources(client, kind)
+		if err != nil {
+			return actions, fmt.Errorf("listing target %s: %w", kind.Name, err)
+		}
+		byID := map[string]api.JSONAPIResource{}
+		byKey := map[string]api.JSONAPIResource{}
+		for _, t := range targets {
+			byID[t.ID] = t
+			existing[kind.Name][t.ID] = true
+			if key := kind.Key(t.Attributes, snapshotResource(t).Relationships); key != "" {
+				byKey[key] = t
+			}
+		}
+
+		for _, res := range snap.Resources[kind.Name] {
+			action := restoreAction{Kind: kind.Name, SourceID: res.ID}
+
+			// Point relationships at the target's resources
+			rels := map[string]string{}
+			var missing string
+			for _, rel := range kind.Relations {
+				src := res.Relationships[rel.Name]
+				if src == "" {
+					continue
+				}
+				if id, ok := ids[rel.Kind][src]; ok {
+					rels[rel.Name] = id
+				} else if existing[rel.Kind][src] {
+					rels[rel.Name] = src
+				} else {
+					missing = fmt.Sprintf("%s %s was not restored", rel.Kind, src)
+					break
+				}
+			}
+			action.Key = kind.Key(res.Attributes, rels)
+			if missing != "" {
+				action.Action = "failed"
+				action.Error = missing
+				actions = append(actions, action)
+				continue
+			}
+
+			// A resource mapped by an earlier run is not a conflict
+			target, mapped := byID[ids[kind.Name][res.ID]]
+			ok := mapped
+			if !ok {
+				target, ok = byID[res.ID]
+			}
+			if !ok && action.Key != "" {
+				target, ok = byKey[action.Key]
+			}
+			if ok {
+				if ids[kind.Name][res.ID] != target.ID {
+					ids[kind.Name][res.ID] = target.ID
+					if err := save(); err != nil {
+						return actions, fmt.Errorf("saving ID mapping: %w", err)
+					}
+				}
+				action.TargetID = target.ID
+				switch {
+				case mapped && opts.OnConflict != "update":
+					action.Action = "done"
+				case opts.OnConflict == "skip":
+					action.Action = "skip"
+				case opts.OnConflict == "fail":
+					action.Action = "conflict"
+					action.Error = "already exists"
+					actions = append(actions, action)
+					return actions, fmt.Errorf("%s %s already exists in the target as %s", kind.Name, action.Key, target.ID)
+				case opts.OnConflict == "update":
+					changes := kind.Changes(res.Attributes, target.Attributes)
+					for name := range changes {
+						action.Changes = append(action.Changes, name)
+					}
+					sort.Strings(action.Changes)
+					switch {
+					case len(changes) == 0:
+						action.Action = "unchanged"
+					case opts.DryRun:
+						action.Action = "update"
+					default:
+						if _, err := client.UpdateResource(kind.Path()+"/"+target.ID, kind.Name, target.ID, changes); err != nil {
+							action.Action = "failed"
+							action.Error = err.Error()
+						} else {
+							action.Action = "update"
+						}
+					}
+				}
+				actions = append(actions, action)
+				continue
+			}
+
+			action.Action = "create"
+			if opts.DryRun {
+				ids[kind.Name][res.ID] = "new:" + res.ID
+				actions = append(actions, action)
+				continue
+			}
+			relData := map[string]api.RelationshipData{}
+			for _, rel := range kind.Relations {
+				if id := rels[rel.Name]; id != "" {
+					relData[rel.Name] = api.RelationshipData{Type: rel.Kind, ID: id}
+				}
+			}
+			created, err := client.CreateResource(kind.Path(), kind.Name, kind.CreateAttributes(res), relData)
+			if err != nil {
+				action.Action = "failed"
+				action.Error = err.Error()
+			} else {
+				ids[kind.Name][res.ID] = created.ID
+				existing[kind.Name][created.ID] = true
+				action.TargetID = created.ID
+				if err := save(); err != nil {
+					actions = append(actions, action)
+					return actions, fmt.Errorf("saving ID mapping: %w", err)
+				}
+			}
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// listResources fetches every resource of a kind.
+func listResources(client *api.Client, kind snapshot.Kind) ([]api.JSONAPIResource, error) {
+	return listAll(func(params map[string]string) ([]api.JSONAPIResource, error) {
+		return client.ListResources(kind.Path(), params)
+	}, map[string]string{})
+}
+
+// snapshotResource keeps a resource's attributes and to-one relationships.
+func snapshotResource(res api.JSONAPIResource) snapshot.Resource {
+	r := snapshot.Resource{ID: res.ID, Attributes: res.Attributes}
+	if r.Attributes == nil {
+		r.Attributes = map[string]interface{}{}
+	}
+	for name := range res.Relationships {
+		if id := res.RelationshipID(name); id != "" {
+			if r.Relationships == nil {
+				r.Relationships = map[string]string{}
+			}
+			r.Relationships[name] = id
+		}
+	}
+	return r
+}
+
+func init() {
+	snapshotExportCmd.Flags().String("out", "", "Snapshot file; .gz compresses (default keygen-snapshot-<time>.json.gz)")
+	snapshotRestoreCmd.Flags().Bool("dry-run", false, "Show the plan without writing anything")
+	snapshotRestoreCmd.Flags().String("on-conflict", "skip", "What to do with resources that already exist: skip, update or fail")
+
+	snapshotCmd.AddCommand(snapshotExportCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
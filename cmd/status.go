@@ -3,14 +3,23 @@ package cmd
 import (
 	"fmt"
 	"math"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
 	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/metrics"
 	"github.com/productivityenthusiast/keygen-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
+// openMetricsAnnotation marks the commands that can print --format openmetrics.
+const openMetricsAnnotation = "openmetrics"
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show account summary (licenses, users, products, components)",
@@ -19,16 +28,26 @@ total licenses, users, products, and per-license component breakdowns
 (devices, printers, servers based on license metadata).
 
 Use --user to scope to a single user's licenses.
-Use --fields to choose which columns to display (comma-separated).
+Use --fields to choose which license columns to display (comma-separated),
+and --sort and --where to order and filter them.
 
 Available fields:
-  key, name, status, days, owner, machines, devices, printers, servers
+  key, name, status, days, owner, machines, devices, printers, servers, usage
+  and any license JSON key, e.g. max_devices
+
+Licenses at or near their maxUses limit are listed under usage_warnings.
+
+--format openmetrics prints the same aggregates as gauges, e.g. for the
+node_exporter textfile collector; 'keygen exporter' serves them continuously.
 
 Examples:
   keygen status
   keygen status --user admin@example.com
   keygen status --fields status,devices,printers,servers --format table
-  keygen status --user admin@example.com --fields key,status,days`,
+  keygen status --user admin@example.com --fields key,status,days
+  keygen status --where 'days<30' --sort days --format table
+  keygen status --format openmetrics > /var/lib/node_exporter/keygen.prom`,
+	Annotations: map[string]string{openMetricsAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := loadConfig()
 		client, err := auth.ResolveClient(cfg)
@@ -37,277 +56,321 @@ Examples:
 			return
 		}
 
-		// Resolve --user flag
 		userFilter, _ := cmd.Flags().GetString("user")
-		var filterUserID, filterUserEmail string
-
-		if userFilter != "" {
-			if strings.Contains(userFilter, "@") {
-				u, err := client.FindUserByEmail(userFilter)
-				if err != nil {
-					output.Error("user not found: " + err.Error())
-					return
-				}
-				filterUserID = u.ID
-				filterUserEmail = u.Email
-			} else {
-				u, err := client.GetUser(userFilter)
-				if err != nil {
-					output.Error("user not found: " + err.Error())
-					return
-				}
-				filterUserID = u.ID
-				filterUserEmail = u.Email
-			}
-		}
-
-		// Fetch licenses — scoped to user if filter provided
-		var licenseParams = map[string]string{"page[size]": "100", "page[number]": "1"}
-		if filterUserID != "" {
-			licenseParams["user"] = filterUserID
-		}
-		licenses, errL := client.ListLicenses(licenseParams)
-		if errL != nil {
-			output.Error("failed to fetch licenses: " + errL.Error())
+		result, details, usageWarnings, err := accountStatus(client, cfg, userFilter)
+		if err != nil {
+			output.Error(err.Error())
 			return
 		}
 
-		// Only fetch account-wide counts when not filtering by user
-		userCount := 0
-		productCount := 0
-		if filterUserID == "" {
-			users, errU := client.ListUsers(map[string]string{"page[size]": "100", "page[number]": "1"})
-			if errU == nil {
-				userCount = len(users)
+		if getFormat() == "openmetrics" {
+			details, err = statusColumns.Filter(details, listOpts)
+			if err != nil {
+				output.Error(err.Error())
+				return
 			}
-			products, errP := client.ListProducts()
-			if errP == nil {
-				productCount = len(products)
+			if err := metrics.Write(os.Stdout, statusMetrics(result, details), true); err != nil {
+				exitError(err.Error())
 			}
+			return
 		}
 
-		// Aggregate license statuses
-		statusCounts := map[string]int{}
-		for _, lic := range licenses {
-			s := strings.ToUpper(lic.Status)
-			statusCounts[s]++
+		res, err := statusColumns.Apply(details, listOpts)
+		if err != nil {
+			output.Error(err.Error())
+			return
 		}
+		result.Licenses = res.Items
 
-		// Per-license details with component breakdown
-		type licenseDetail struct {
-			ID            string `json:"id"`
-			Key           string `json:"key"`
-			Name          string `json:"name"`
-			Status        string `json:"status"`
-			Expiry        string `json:"expiry"`
-			DaysRemaining int    `json:"days_remaining"`
-			OwnerEmail    string `json:"owner_email,omitempty"`
-			Machines      int    `json:"machines"`
-			MaxDevices    string `json:"max_devices,omitempty"`
-			MaxPrinters   string `json:"max_printers,omitempty"`
-			MaxServers    string `json:"max_servers,omitempty"`
-			Devices       int    `json:"devices"`
-			Printers      int    `json:"printers"`
-			Servers       int    `json:"servers"`
+		// Print summary header
+		f := getFormat()
+		if f == "table" || f == "csv" {
+			if result.UserID != "" {
+				fmt.Printf("User: %s | Licenses: %d | Machines: %d | Components: %d\n\n",
+					result.UserEmail, result.TotalLicenses, result.TotalMachines, result.TotalComponents)
+			} else {
+				fmt.Printf("Account: %s | Licenses: %d | Users: %d | Products: %d | Machines: %d | Components: %d\n\n",
+					cfg.AccountID, result.TotalLicenses, *result.TotalUsers, *result.TotalProducts, result.TotalMachines, result.TotalComponents)
+			}
 		}
-
-		var details []licenseDetail
-		totalMachines := 0
-		totalComponents := 0
-
-		for _, lic := range licenses {
-			d := licenseDetail{
-				ID:     lic.ID,
-				Key:    lic.Key,
-				Name:   lic.Name,
-				Status: strings.ToUpper(lic.Status),
-				Expiry: lic.Expiry,
+		output.SuccessTable(result, res.Headers, res.Rows)
+		if f == "table" {
+			for _, w := range usageWarnings {
+				fmt.Printf("\nWarning: %s", w)
 			}
-
-			// Days remaining
-			if lic.Expiry != "" {
-				if t, err := time.Parse(time.RFC3339, lic.Expiry); err == nil {
-					d.DaysRemaining = int(math.Max(0, time.Until(t).Hours()/24))
-				}
+			if len(usageWarnings) > 0 {
+				fmt.Println()
 			}
+		}
+	},
+}
 
-			// Metadata limits
-			if lic.Metadata != nil {
-				if v, ok := lic.Metadata["maxDevices"]; ok {
-					d.MaxDevices = fmt.Sprintf("%v", v)
-				}
-				if v, ok := lic.Metadata["maxPrinters"]; ok {
-					d.MaxPrinters = fmt.Sprintf("%v", v)
-				}
-				if v, ok := lic.Metadata["maxServers"]; ok {
-					d.MaxServers = fmt.Sprintf("%v", v)
-				}
-			}
+// accountStatus gathers the account summary shown by 'keygen status',
+// scoped to one user's licenses when userFilter is set.
+func accountStatus(client *api.Client, cfg *config.Config, userFilter string) (*statusOutput, []licenseDetail, []string, error) {
+	// Resolve the user filter
+	var filterUserID, filterUserEmail string
 
-			// Fetch machines + components for this license
-			machines, err := client.GetLicenseMachines(lic.ID)
-			if err == nil && machines != nil {
-				d.Machines = len(machines)
-				totalMachines += len(machines)
-				for _, m := range machines {
-					totalComponents += len(m.Components)
-					for _, comp := range m.Components {
-						name := strings.ToLower(comp.Name)
-						switch {
-						case strings.Contains(name, "printer") || strings.Contains(name, "print"):
-							d.Printers++
-						case strings.Contains(name, "server") || strings.Contains(name, "srv"):
-							d.Servers++
-						default:
-							d.Devices++
-						}
-					}
-				}
+	if userFilter != "" {
+		if strings.Contains(userFilter, "@") {
+			u, err := client.FindUserByEmail(userFilter)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("user not found: %w", err)
 			}
-
-			// Resolve owner email (skip if we already know from --user)
-			if lic.OwnerID != "" {
-				if filterUserEmail != "" && lic.OwnerID == filterUserID {
-					d.OwnerEmail = filterUserEmail
-				} else if u, err := client.GetUser(lic.OwnerID); err == nil {
-					d.OwnerEmail = u.Email
-				}
+			filterUserID = u.ID
+			filterUserEmail = u.Email
+		} else {
+			u, err := client.GetUser(userFilter)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("user not found: %w", err)
 			}
-
-			details = append(details, d)
+			filterUserID = u.ID
+			filterUserEmail = u.Email
 		}
+	}
 
-		// Parse --fields flag
-		fieldsFlag, _ := cmd.Flags().GetString("fields")
-		allFields := []string{"key", "name", "status", "days", "owner", "machines", "devices", "printers", "servers"}
-		selectedFields := allFields // default: all
-
-		if fieldsFlag != "" {
-			selectedFields = nil
-			for _, f := range strings.Split(fieldsFlag, ",") {
-				f = strings.TrimSpace(strings.ToLower(f))
-				if f != "" {
-					selectedFields = append(selectedFields, f)
-				}
-			}
-		}
+	// Fetch licenses — scoped to user if filter provided
+	licenseParams := map[string]string{}
+	if filterUserID != "" {
+		licenseParams["user"] = filterUserID
+	}
+	licenses, errL := listAll(client.ListLicenses, licenseParams)
+	if errL != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch licenses: %w", errL)
+	}
 
-		// Build a set for quick lookup
-		fieldSet := map[string]bool{}
-		for _, f := range selectedFields {
-			fieldSet[f] = true
+	// Only fetch account-wide counts when not filtering by user
+	userCount := 0
+	productCount := 0
+	if filterUserID == "" {
+		users, errU := listAll(client.ListUsers, map[string]string{})
+		if errU == nil {
+			userCount = len(users)
 		}
+		products, errP := client.ListProducts()
+		if errP == nil {
+			productCount = len(products)
+		}
+	}
 
-		// Build JSON result — filter fields for JSON output too
-		result := map[string]interface{}{}
+	// Aggregate license statuses
+	statusCounts := map[string]int{}
+	for _, lic := range licenses {
+		s := strings.ToUpper(lic.Status)
+		statusCounts[s]++
+	}
 
-		if filterUserID != "" {
-			result["user_id"] = filterUserID
-			result["user_email"] = filterUserEmail
-		} else {
-			result["account_id"] = cfg.AccountID
-			result["base_url"] = cfg.BaseURL
-			result["total_users"] = userCount
-			result["total_products"] = productCount
+	// Per-license details with component breakdown
+	var details []licenseDetail
+	var usageWarnings []string
+	totalMachines := 0
+	totalComponents := 0
+
+	for _, lic := range licenses {
+		d := licenseDetail{
+			ID:        lic.ID,
+			Key:       lic.Key,
+			Name:      lic.Name,
+			Status:    strings.ToUpper(lic.Status),
+			Expiry:    lic.Expiry,
+			ProductID: lic.ProductID,
+			PolicyID:  lic.PolicyID,
+			Uses:      lic.Uses,
+			MaxUses:   lic.MaxUses,
+			Usage:     formatUsage(&lic),
 		}
-		result["total_licenses"] = len(licenses)
-		result["total_machines"] = totalMachines
-		result["total_components"] = totalComponents
-		result["license_statuses"] = statusCounts
-
-		// Filter license detail fields for JSON
-		filteredDetails := make([]map[string]interface{}, len(details))
-		for i, d := range details {
-			m := map[string]interface{}{"id": d.ID}
-			if fieldSet["key"] {
-				m["key"] = d.Key
-			}
-			if fieldSet["name"] {
-				m["name"] = d.Name
-			}
-			if fieldSet["status"] {
-				m["status"] = d.Status
-			}
-			if fieldSet["days"] {
-				m["days_remaining"] = d.DaysRemaining
-				m["expiry"] = d.Expiry
-			}
-			if fieldSet["owner"] {
-				m["owner_email"] = d.OwnerEmail
-			}
-			if fieldSet["machines"] {
-				m["machines"] = d.Machines
-			}
-			if fieldSet["devices"] {
-				m["devices"] = d.Devices
-				m["max_devices"] = d.MaxDevices
-			}
-			if fieldSet["printers"] {
-				m["printers"] = d.Printers
-				m["max_printers"] = d.MaxPrinters
-			}
-			if fieldSet["servers"] {
-				m["servers"] = d.Servers
-				m["max_servers"] = d.MaxServers
+		if w := usageWarning(&lic); w != "" {
+			usageWarnings = append(usageWarnings, w)
+		}
+
+		// Days remaining
+		if lic.Expiry != "" {
+			if t, err := time.Parse(time.RFC3339, lic.Expiry); err == nil {
+				d.DaysRemaining = int(math.Max(0, time.Until(t).Hours()/24))
 			}
-			filteredDetails[i] = m
 		}
-		result["licenses"] = filteredDetails
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			// Map field names to headers and row builders
-			type colDef struct {
-				header string
-				value  func(d licenseDetail) string
+		// Metadata limits
+		if lic.Metadata != nil {
+			if v, ok := lic.Metadata["maxDevices"]; ok {
+				d.MaxDevices = fmt.Sprintf("%v", v)
 			}
-			colMap := map[string]colDef{
-				"key":      {"KEY", func(d licenseDetail) string { return d.Key }},
-				"name":     {"NAME", func(d licenseDetail) string { return d.Name }},
-				"status":   {"STATUS", func(d licenseDetail) string { return d.Status }},
-				"days":     {"DAYS", func(d licenseDetail) string { return fmt.Sprintf("%d", d.DaysRemaining) }},
-				"owner":    {"OWNER", func(d licenseDetail) string { return d.OwnerEmail }},
-				"machines": {"MACHINES", func(d licenseDetail) string { return fmt.Sprintf("%d", d.Machines) }},
-				"devices":  {"DEVICES", func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Devices, d.MaxDevices) }},
-				"printers": {"PRINTERS", func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Printers, d.MaxPrinters) }},
-				"servers":  {"SERVERS", func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Servers, d.MaxServers) }},
+			if v, ok := lic.Metadata["maxPrinters"]; ok {
+				d.MaxPrinters = fmt.Sprintf("%v", v)
 			}
-
-			var headers []string
-			var builders []func(d licenseDetail) string
-			for _, field := range selectedFields {
-				if c, ok := colMap[field]; ok {
-					headers = append(headers, c.header)
-					builders = append(builders, c.value)
-				}
+			if v, ok := lic.Metadata["maxServers"]; ok {
+				d.MaxServers = fmt.Sprintf("%v", v)
 			}
+		}
 
-			rows := make([][]string, len(details))
-			for i, d := range details {
-				row := make([]string, len(builders))
-				for j, fn := range builders {
-					row[j] = fn(d)
+		// Fetch machines + components for this license
+		machines, err := client.GetLicenseMachines(lic.ID)
+		if err == nil && machines != nil {
+			d.Machines = len(machines)
+			totalMachines += len(machines)
+			for _, m := range machines {
+				totalComponents += len(m.Components)
+				for _, comp := range m.Components {
+					name := strings.ToLower(comp.Name)
+					switch {
+					case strings.Contains(name, "printer") || strings.Contains(name, "print"):
+						d.Printers++
+					case strings.Contains(name, "server") || strings.Contains(name, "srv"):
+						d.Servers++
+					default:
+						d.Devices++
+					}
 				}
-				rows[i] = row
 			}
+		}
 
-			// Print summary header
-			if filterUserID != "" {
-				fmt.Printf("User: %s | Licenses: %d | Machines: %d | Components: %d\n\n",
-					filterUserEmail, len(licenses), totalMachines, totalComponents)
-			} else {
-				fmt.Printf("Account: %s | Licenses: %d | Users: %d | Products: %d | Machines: %d | Components: %d\n\n",
-					cfg.AccountID, len(licenses), userCount, productCount, totalMachines, totalComponents)
+		// Resolve owner email (skip if we already know from --user)
+		if lic.OwnerID != "" {
+			if filterUserEmail != "" && lic.OwnerID == filterUserID {
+				d.OwnerEmail = filterUserEmail
+			} else if u, err := client.GetUser(lic.OwnerID); err == nil {
+				d.OwnerEmail = u.Email
 			}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.Success(result)
 		}
-	},
+
+		details = append(details, d)
+	}
+
+	result := &statusOutput{
+		TotalLicenses:   len(licenses),
+		TotalMachines:   totalMachines,
+		TotalComponents: totalComponents,
+		LicenseStatuses: statusCounts,
+		UsageWarnings:   usageWarnings,
+	}
+	if filterUserID != "" {
+		result.UserID = filterUserID
+		result.UserEmail = filterUserEmail
+	} else {
+		result.AccountID = cfg.AccountID
+		result.BaseURL = cfg.BaseURL
+		result.TotalUsers = &userCount
+		result.TotalProducts = &productCount
+	}
+
+	return result, details, usageWarnings, nil
+}
+
+// licenseDetail is one license row of 'keygen status'.
+type licenseDetail struct {
+	ID            string `json:"id"`
+	Key           string `json:"key"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Expiry        string `json:"expiry"`
+	DaysRemaining int    `json:"days_remaining"`
+	ProductID     string `json:"product_id,omitempty"`
+	PolicyID      string `json:"policy_id,omitempty"`
+	OwnerEmail    string `json:"owner_email,omitempty"`
+	Machines      int    `json:"machines"`
+	MaxDevices    string `json:"max_devices,omitempty"`
+	MaxPrinters   string `json:"max_printers,omitempty"`
+	MaxServers    string `json:"max_servers,omitempty"`
+	Devices       int    `json:"devices"`
+	Printers      int    `json:"printers"`
+	Servers       int    `json:"servers"`
+	Uses          int    `json:"uses"`
+	MaxUses       int    `json:"max_uses,omitempty"`
+	Usage         string `json:"-"`
 }
 
+// licenseQuota is one limited resource of a license and how much of it is used.
+type licenseQuota struct {
+	Resource string
+	Used     int
+	Limit    float64
+}
+
+// Utilization is the share of the quota in use, e.g. 0.75.
+func (q licenseQuota) Utilization() float64 {
+	return float64(q.Used) / q.Limit
+}
+
+// quotas lists the license's device, printer and server limits from its
+// metadata and its maxUses limit, skipping those that aren't set.
+func (d licenseDetail) quotas() []licenseQuota {
+	candidates := []struct {
+		resource string
+		used     int
+		max      string
+	}{
+		{"devices", d.Devices, d.MaxDevices},
+		{"printers", d.Printers, d.MaxPrinters},
+		{"servers", d.Servers, d.MaxServers},
+		{"uses", d.Uses, strconv.Itoa(d.MaxUses)},
+	}
+	var quotas []licenseQuota
+	for _, c := range candidates {
+		max, err := strconv.ParseFloat(c.max, 64)
+		if err != nil || max <= 0 {
+			continue
+		}
+		quotas = append(quotas, licenseQuota{Resource: c.resource, Used: c.used, Limit: max})
+	}
+	return quotas
+}
+
+var statusColumns = columns.NewSet([]string{"key", "name", "status", "days", "owner", "machines", "devices", "printers", "servers", "usage"},
+	columns.Column[licenseDetail]{
+		Name:   "days",
+		Header: "DAYS",
+		Value:  func(d licenseDetail) interface{} { return d.DaysRemaining },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"days_remaining": d.DaysRemaining, "expiry": d.Expiry}
+		},
+	},
+	columns.Column[licenseDetail]{
+		Name:   "owner",
+		Header: "OWNER",
+		Value:  func(d licenseDetail) interface{} { return d.OwnerEmail },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"owner_email": d.OwnerEmail}
+		},
+	},
+	columns.Column[licenseDetail]{
+		Name:   "devices",
+		Header: "DEVICES",
+		Value:  func(d licenseDetail) interface{} { return d.Devices },
+		Cell:   func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Devices, d.MaxDevices) },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"devices": d.Devices, "max_devices": d.MaxDevices}
+		},
+	},
+	columns.Column[licenseDetail]{
+		Name:   "printers",
+		Header: "PRINTERS",
+		Value:  func(d licenseDetail) interface{} { return d.Printers },
+		Cell:   func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Printers, d.MaxPrinters) },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"printers": d.Printers, "max_printers": d.MaxPrinters}
+		},
+	},
+	columns.Column[licenseDetail]{
+		Name:   "servers",
+		Header: "SERVERS",
+		Value:  func(d licenseDetail) interface{} { return d.Servers },
+		Cell:   func(d licenseDetail) string { return fmt.Sprintf("%d/%s", d.Servers, d.MaxServers) },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"servers": d.Servers, "max_servers": d.MaxServers}
+		},
+	},
+	columns.Column[licenseDetail]{
+		Name:   "usage",
+		Header: "USAGE",
+		Value:  func(d licenseDetail) interface{} { return d.Uses },
+		Cell:   func(d licenseDetail) string { return d.Usage },
+		JSON: func(d licenseDetail) map[string]interface{} {
+			return map[string]interface{}{"uses": d.Uses, "max_uses": d.MaxUses}
+		},
+	},
+)
+
 func init() {
 	statusCmd.Flags().String("user", "", "Filter by user ID or email")
-	statusCmd.Flags().String("fields", "", "Comma-separated fields to show: key,name,status,days,owner,machines,devices,printers,servers")
 	rootCmd.AddCommand(statusCmd)
 }
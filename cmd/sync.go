@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/config"
+	"github.com/productivityenthusiast/keygen-cli/internal/offline"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// offlineAnnotation marks the commands that can run with --offline.
+const offlineAnnotation = "offline"
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Store a local copy of the account for --offline",
+	Long: `Fetch every product, policy, user, license, machine and component of the
+account and store them under ~/.keygen-cli/sync/<profile>.json.gz, replacing
+the previous copy only once the new one is complete.
+
+With --offline, these commands answer from the copy instead of the server:
+
+  keygen components check <fingerprint>   (and --local)
+  keygen licenses show <license-id-or-key>
+  keygen licenses status <license-id-or-key>
+  keygen users status <user-id-or-email>
+
+Offline results carry the sync time: an "offline" field in the JSON output,
+or a line on stderr in the other formats. Offline validation checks the
+license's status, expiry and machine count as of the sync; rules that need
+the server, like heartbeats, are not checked.
+
+Examples:
+  keygen sync --profile factory
+  keygen components check <fingerprint> --profile factory --offline`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigRevalidated()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		start := time.Now()
+		snap, err := exportSnapshot(client, cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		path := config.SyncFile(cfg.ProfileName)
+		if err := writeSync(path, snap); err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		counts := snap.Counts()
+		rows := make([][]string, len(snapshot.Kinds))
+		for i, kind := range snapshot.Kinds {
+			rows[i] = []string{kind.Name, strconv.Itoa(counts[kind.Name])}
+		}
+		output.SuccessTable(syncOutput{
+			File:      path,
+			AccountID: snap.AccountID,
+			Synced:    snap.Created,
+			Duration:  time.Since(start).Round(time.Millisecond).String(),
+			Counts:    counts,
+		}, []string{"KIND", "COUNT"}, rows)
+	},
+}
+
+// writeSync writes the copy next to the previous one and then replaces it,
+// so an interrupted sync leaves the previous copy usable.
+func writeSync(path string, snap *snapshot.Snapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	partial := strings.TrimSuffix(path, ".json.gz") + ".partial.json.gz"
+	if err := snapshot.Write(partial, snap); err != nil {
+		os.Remove(partial)
+		return fmt.Errorf("writing %s: %w", partial, err)
+	}
+	return os.Rename(partial, path)
+}
+
+// accountReader is what the commands that can run --offline read, from the
+// API or from the last sync.
+type accountReader interface {
+	GetLicense(id string) (*api.License, error)
+	ValidateLicense(id string) (*api.LicenseValidation, *api.License, error)
+	GetLicenseMachines(licenseID string) ([]api.Machine, error)
+	ListMachines(params map[string]string) ([]api.Machine, error)
+	FindComponentByFingerprint(fingerprint string) (*api.Component, error)
+	FindComponentsByFingerprints(fingerprints []string) (map[string]*api.Component, error)
+	GetUser(id string) (*api.User, error)
+	FindUserByEmail(email string) (*api.User, error)
+	GetUserLicenses(userID string) ([]api.License, error)
+}
+
+// resolveReader returns the profile's API client, or with --offline its
+// last sync, whose time is then added to the output.
+func resolveReader(cfg *config.Config) (accountReader, error) {
+	if !offlineMode {
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+
+	store, err := offline.Load(config.SyncFile(cfg.ProfileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("profile %s has no sync; run 'keygen sync --profile %s' while online", cfg.ProfileName, cfg.ProfileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cfg.AccountID != "" && store.AccountID != "" && store.AccountID != cfg.AccountID {
+		return nil, fmt.Errorf("the sync of profile %s is of account %s, not %s; run 'keygen sync' again", cfg.ProfileName, store.AccountID, cfg.AccountID)
+	}
+
+	synced := store.Synced.UTC().Format(time.RFC3339)
+	age := time.Since(store.Synced).Truncate(time.Second)
+	output.Annotate("offline", map[string]interface{}{"synced": synced, "age": age.String()},
+		fmt.Sprintf("Offline: results are from the sync of %s (%s ago)", synced, age))
+	return store, nil
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
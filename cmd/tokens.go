@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage API tokens",
+}
+
+var tokensListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API tokens",
+	Long: `List API tokens. Token secrets are only shown when a token is created.
+
+Examples:
+  keygen tokens list --format table
+  keygen tokens list --where 'days<7' --sort expiry`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		tokens, err := client.ListTokens(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Tokens, tokens)
+	},
+}
+
+func init() {
+	tokensListCmd.Flags().Int("limit", 10, "Results per page")
+	tokensListCmd.Flags().Int("page", 1, "Page number")
+
+	tokensCmd.AddCommand(tokensListCmd)
+	rootCmd.AddCommand(tokensCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
 	"github.com/productivityenthusiast/keygen-cli/internal/output"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +15,43 @@ var usersCmd = &cobra.Command{
 	Short: "Manage users",
 }
 
+var usersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List users",
+	Long: `List users.
+
+Examples:
+  keygen users list --format table
+  keygen users list --where 'email~@example.com' --sort created:desc`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		if v, _ := cmd.Flags().GetString("status"); v != "" {
+			params["status"] = strings.ToUpper(v)
+		}
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		users, err := client.ListUsers(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.Users, users)
+	},
+}
+
 var usersShowCmd = &cobra.Command{
 	Use:   "show [user-id-or-email]",
 	Short: "Show user details",
@@ -88,98 +126,40 @@ var usersShowCmd = &cobra.Command{
 var usersStatusCmd = &cobra.Command{
 	Use:   "status [user-id-or-email]",
 	Short: "Show user status summary",
-	Args:  cobra.ExactArgs(1),
+	Long: `Summarize a user's licenses by status with their machine and component
+totals. With --offline, the summary comes from the last 'keygen sync'.
+
+Examples:
+  keygen users status user@example.com --format table
+  keygen users status user@example.com --offline`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{offlineAnnotation: "true"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := loadConfig()
-		client, err := auth.ResolveClient(cfg)
+		client, err := resolveReader(cfg)
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
 
-		identifier := args[0]
-		var userID, userEmail string
-
-		if strings.Contains(identifier, "@") {
-			u, err := client.FindUserByEmail(identifier)
-			if err != nil {
-				output.Error(err.Error())
-				return
-			}
-			userID = u.ID
-			userEmail = u.Email
-		} else {
-			u, err := client.GetUser(identifier)
-			if err != nil {
-				output.Error(err.Error())
-				return
-			}
-			userID = u.ID
-			userEmail = u.Email
-		}
-
-		licenses, err := client.GetUserLicenses(userID)
+		result, err := userStatus(client, args[0])
 		if err != nil {
 			output.Error(err.Error())
 			return
 		}
 
-		active := 0
-		expiring := 0
-		expired := 0
-		suspended := 0
-		totalMachines := 0
-		totalComponents := 0
-
-		for _, lic := range licenses {
-			switch strings.ToUpper(lic.Status) {
-			case "ACTIVE":
-				active++
-			case "EXPIRING":
-				expiring++
-			case "EXPIRED":
-				expired++
-			case "SUSPENDED":
-				suspended++
-			}
-
-			machines, _ := client.GetLicenseMachines(lic.ID)
-			if machines != nil {
-				totalMachines += len(machines)
-				for _, m := range machines {
-					totalComponents += len(m.Components)
-				}
-			}
-		}
-
-		result := map[string]interface{}{
-			"user_id":          userID,
-			"email":            userEmail,
-			"total_licenses":   len(licenses),
-			"active":           active,
-			"expiring":         expiring,
-			"expired":          expired,
-			"suspended":        suspended,
-			"total_machines":   totalMachines,
-			"total_components": totalComponents,
-		}
-
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"USER_ID", "EMAIL", "LICENSES", "ACTIVE", "EXPIRING", "EXPIRED", "MACHINES", "COMPONENTS"}
-			rows := [][]string{{
-				userID, userEmail,
-				fmt.Sprintf("%d", len(licenses)),
-				fmt.Sprintf("%d", active),
-				fmt.Sprintf("%d", expiring),
-				fmt.Sprintf("%d", expired),
-				fmt.Sprintf("%d", totalMachines),
-				fmt.Sprintf("%d", totalComponents),
-			}}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.Success(result)
-		}
+		headers := []string{"USER_ID", "EMAIL", "LICENSES", "ACTIVE", "EXPIRING", "EXPIRED", "MACHINES", "COMPONENTS"}
+		rows := [][]string{{
+			result.UserID,
+			result.Email,
+			fmt.Sprintf("%d", result.TotalLicenses),
+			fmt.Sprintf("%d", result.Active),
+			fmt.Sprintf("%d", result.Expiring),
+			fmt.Sprintf("%d", result.Expired),
+			fmt.Sprintf("%d", result.TotalMachines),
+			fmt.Sprintf("%d", result.TotalComponents),
+		}}
+		output.SuccessTable(result, headers, rows)
 	},
 }
 
@@ -188,7 +168,7 @@ var usersUpdateCmd = &cobra.Command{
 	Short: "Update user information (email, name, password)",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg := loadConfig()
+		cfg := loadConfigForWrite()
 		client, err := auth.ResolveClient(cfg)
 		if err != nil {
 			output.Error(err.Error())
@@ -246,33 +226,100 @@ var usersUpdateCmd = &cobra.Command{
 			return
 		}
 
-		result := map[string]interface{}{
-			"id":         updated.ID,
-			"email":      updated.Email,
-			"first_name": updated.FirstName,
-			"last_name":  updated.LastName,
-			"role":       updated.Role,
-			"status":     updated.Status,
-			"updated":    updated.Updated,
+		result := userUpdateOutput{
+			ID:        updated.ID,
+			Email:     updated.Email,
+			FirstName: updated.FirstName,
+			LastName:  updated.LastName,
+			Role:      updated.Role,
+			Status:    updated.Status,
+			Updated:   updated.Updated,
 		}
 
-		f := getFormat()
-		if f == "table" || f == "csv" {
-			headers := []string{"ID", "EMAIL", "FIRST_NAME", "LAST_NAME", "ROLE", "STATUS"}
-			rows := [][]string{{updated.ID, updated.Email, updated.FirstName, updated.LastName, updated.Role, updated.Status}}
-			output.FormatTable(f, headers, rows)
-		} else {
-			output.Success(result)
-		}
+		headers := []string{"ID", "EMAIL", "FIRST_NAME", "LAST_NAME", "ROLE", "STATUS"}
+		rows := [][]string{{updated.ID, updated.Email, updated.FirstName, updated.LastName, updated.Role, updated.Status}}
+		output.SuccessTable(result, headers, rows)
 	},
 }
 
+// userStatus summarizes a user's licenses by status with their machine and
+// component totals, as shown by 'users status'.
+func userStatus(client accountReader, identifier string) (*userStatusOutput, error) {
+	var userID, userEmail string
+
+	if strings.Contains(identifier, "@") {
+		u, err := client.FindUserByEmail(identifier)
+		if err != nil {
+			return nil, err
+		}
+		userID = u.ID
+		userEmail = u.Email
+	} else {
+		u, err := client.GetUser(identifier)
+		if err != nil {
+			return nil, err
+		}
+		userID = u.ID
+		userEmail = u.Email
+	}
+
+	licenses, err := client.GetUserLicenses(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := 0
+	expiring := 0
+	expired := 0
+	suspended := 0
+	totalMachines := 0
+	totalComponents := 0
+
+	for _, lic := range licenses {
+		switch strings.ToUpper(lic.Status) {
+		case "ACTIVE":
+			active++
+		case "EXPIRING":
+			expiring++
+		case "EXPIRED":
+			expired++
+		case "SUSPENDED":
+			suspended++
+		}
+
+		machines, _ := client.GetLicenseMachines(lic.ID)
+		if machines != nil {
+			totalMachines += len(machines)
+			for _, m := range machines {
+				totalComponents += len(m.Components)
+			}
+		}
+	}
+
+	return &userStatusOutput{
+		UserID:          userID,
+		Email:           userEmail,
+		TotalLicenses:   len(licenses),
+		Active:          active,
+		Expiring:        expiring,
+		Expired:         expired,
+		Suspended:       suspended,
+		TotalMachines:   totalMachines,
+		TotalComponents: totalComponents,
+	}, nil
+}
+
 func init() {
+	usersListCmd.Flags().String("status", "", "Filter by status (ACTIVE, INACTIVE, BANNED)")
+	usersListCmd.Flags().Int("limit", 10, "Results per page")
+	usersListCmd.Flags().Int("page", 1, "Page number")
+
 	usersUpdateCmd.Flags().String("email", "", "New email address")
 	usersUpdateCmd.Flags().String("first-name", "", "New first name")
 	usersUpdateCmd.Flags().String("last-name", "", "New last name")
 	usersUpdateCmd.Flags().String("password", "", "New password")
 
+	usersCmd.AddCommand(usersListCmd)
 	usersCmd.AddCommand(usersShowCmd)
 	usersCmd.AddCommand(usersStatusCmd)
 	usersCmd.AddCommand(usersUpdateCmd)
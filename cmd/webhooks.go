@@ -0,0 +1,614 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/auth"
+	"github.com/productivityenthusiast/keygen-cli/internal/columns"
+	"github.com/productivityenthusiast/keygen-cli/internal/output"
+	"github.com/productivityenthusiast/keygen-cli/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Manage webhook endpoints and events, or receive events locally",
+}
+
+var webhooksEndpointsCmd = &cobra.Command{
+	Use:   "endpoints",
+	Short: "Manage webhook endpoints",
+}
+
+var webhooksEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Browse and retry webhook events",
+}
+
+var webhooksEndpointsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook endpoints",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		endpoints, err := client.ListWebhookEndpoints(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.WebhookEndpoints, endpoints)
+	},
+}
+
+var webhooksEndpointsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a webhook endpoint",
+	Long: `Create a webhook endpoint.
+
+Examples:
+  keygen webhooks endpoints create --url https://hooks.example.com/keygen
+  keygen webhooks endpoints create --url https://hooks.example.com/keygen --subscriptions license.expiring-soon,machine.created`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		endpointURL, _ := cmd.Flags().GetString("url")
+		if endpointURL == "" {
+			output.Error("--url is required")
+			return
+		}
+
+		subs, _ := cmd.Flags().GetString("subscriptions")
+		algo, _ := cmd.Flags().GetString("signature-algorithm")
+		attrs := map[string]interface{}{
+			"url":                endpointURL,
+			"subscriptions":      splitList(subs),
+			"signatureAlgorithm": algo,
+		}
+
+		endpoint, err := client.CreateWebhookEndpoint(attrs)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(endpoint)
+	},
+}
+
+var webhooksEndpointsUpdateCmd = &cobra.Command{
+	Use:   "update [endpoint-id]",
+	Short: "Update a webhook endpoint",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		attrs := make(map[string]interface{})
+		if cmd.Flags().Changed("url") {
+			v, _ := cmd.Flags().GetString("url")
+			attrs["url"] = v
+		}
+		if cmd.Flags().Changed("subscriptions") {
+			v, _ := cmd.Flags().GetString("subscriptions")
+			attrs["subscriptions"] = splitList(v)
+		}
+		if cmd.Flags().Changed("signature-algorithm") {
+			v, _ := cmd.Flags().GetString("signature-algorithm")
+			attrs["signatureAlgorithm"] = v
+		}
+
+		if len(attrs) == 0 {
+			output.Error("no update flags provided. Use --url, --subscriptions, or --signature-algorithm")
+			return
+		}
+
+		endpoint, err := client.UpdateWebhookEndpoint(args[0], attrs)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(endpoint)
+	},
+}
+
+var webhooksEndpointsDeleteCmd = &cobra.Command{
+	Use:   "delete [endpoint-id]",
+	Short: "Delete a webhook endpoint",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfigForWrite()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		endpoint, err := client.GetWebhookEndpoint(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		if !force {
+			output.Success(endpointDeletePreview{
+				Action:  "delete",
+				ID:      endpoint.ID,
+				URL:     endpoint.URL,
+				Confirm: "use --force to confirm deletion",
+			})
+			return
+		}
+
+		if err := client.DeleteWebhookEndpoint(endpoint.ID); err != nil {
+			output.Error("delete failed: " + err.Error())
+			return
+		}
+
+		output.Success(endpointDeleteOutput{
+			Deleted: true,
+			ID:      endpoint.ID,
+			URL:     endpoint.URL,
+		})
+	},
+}
+
+var webhooksEventsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook events",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		params := make(map[string]string)
+		if v, _ := cmd.Flags().GetString("event"); v != "" {
+			params["events[]"] = v
+		}
+		if v, _ := cmd.Flags().GetInt("limit"); v > 0 {
+			params["page[size]"] = fmt.Sprintf("%d", v)
+		}
+		if v, _ := cmd.Flags().GetInt("page"); v > 0 {
+			params["page[number]"] = fmt.Sprintf("%d", v)
+		}
+
+		events, err := client.ListWebhookEvents(params)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		printList(columns.WebhookEvents, events)
+	},
+}
+
+var webhooksEventsShowCmd = &cobra.Command{
+	Use:   "show [event-id]",
+	Short: "Show a webhook event with its payload",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		event, err := client.GetWebhookEvent(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		result := webhookEventShowOutput{
+			ID:               event.ID,
+			Event:            event.Event,
+			Endpoint:         event.Endpoint,
+			Status:           event.Status,
+			LastResponseCode: event.LastResponseCode,
+			LastResponseBody: event.LastResponseBody,
+			Created:          event.Created,
+			Updated:          event.Updated,
+			Payload:          event.Payload,
+		}
+
+		// The payload is a JSON document encoded as a string; expand it
+		var payload interface{}
+		if err := json.Unmarshal([]byte(event.Payload), &payload); err == nil {
+			result.Payload = payload
+		}
+
+		output.Success(result)
+	},
+}
+
+var webhooksEventsRetryCmd = &cobra.Command{
+	Use:   "retry [event-id]",
+	Short: "Retry delivery of a webhook event",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		client, err := auth.ResolveClient(cfg)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		event, err := client.RetryWebhookEvent(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(webhookRetryOutput{
+			RetriedEventID: args[0],
+			Event:          event,
+		})
+	},
+}
+
+var webhooksListenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "Receive webhook events on a local HTTP server",
+	Long: `Start a local HTTP server that receives Keygen webhook deliveries.
+
+Every request's Keygen-Signature is verified against the profile's Ed25519
+public key (set with 'keygen profile edit <name> --public-key <hex>' or
+--public-key). Verified events are printed to stdout as NDJSON, one event
+per line. With --exec, the command is run once per event instead, with the
+event JSON on stdin and KEYGEN_EVENT / KEYGEN_EVENT_ID in its environment; a
+failing command answers 500 so Keygen retries the delivery.
+
+Examples:
+  keygen webhooks listen --port 8787 --profile prod
+  keygen webhooks listen --port 8787 --events license.expiring-soon,machine.created --profile prod
+  keygen webhooks listen --port 8787 --exec ./on-event.sh --profile prod
+
+To try it locally, replay the bundled signed fixture:
+  keygen webhooks listen --port 8787 --max-age 0 --public-key <fixture public_key>
+  keygen webhooks replay testdata/webhooks/license-expiring-soon.json --to http://localhost:8787`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keyFlag, _ := cmd.Flags().GetString("public-key")
+		if keyFlag == "" {
+			keyFlag = loadConfig().PublicKey
+		}
+		publicKey, err := webhook.ParsePublicKey(keyFlag)
+		if err != nil {
+			output.Error(err.Error() + " (use --public-key or 'keygen profile edit <name> --public-key')")
+			return
+		}
+
+		port, _ := cmd.Flags().GetInt("port")
+		maxAge, _ := cmd.Flags().GetDuration("max-age")
+		execCmd, _ := cmd.Flags().GetString("exec")
+		execTimeout, _ := cmd.Flags().GetDuration("exec-timeout")
+		eventsFlag, _ := cmd.Flags().GetString("events")
+		filter := splitList(eventsFlag)
+
+		var mu sync.Mutex
+		enc := json.NewEncoder(os.Stdout)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+			if err != nil {
+				http.Error(w, "reading body", http.StatusBadRequest)
+				return
+			}
+
+			if err := webhook.Verify(publicKey, r.Method, r.URL.RequestURI(), r.Host, r.Header, body, maxAge); err != nil {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "Rejected delivery from %s: %v\n", r.RemoteAddr, err)
+				}
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+
+			event, err := webhook.DecodeEvent(body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if !matchesEventFilter(event.Event, filter) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			record := webhookListenRecord{
+				Received: time.Now().UTC().Format(time.RFC3339),
+				ID:       event.ID,
+				Event:    event.Event,
+				Created:  event.Created,
+				Payload:  event.Payload,
+			}
+
+			// Serialize output and commands so events don't interleave
+			mu.Lock()
+			defer mu.Unlock()
+
+			if execCmd == "" {
+				_ = enc.Encode(record)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			line, _ := json.Marshal(record)
+			if err := runEventCommand(execCmd, execTimeout, event, line); err != nil {
+				if !quiet {
+					fmt.Fprintf(os.Stderr, "Command failed for %s %s: %v\n", event.Event, event.ID, err)
+				}
+				http.Error(w, "handler failed", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		server := &http.Server{
+			Addr:              fmt.Sprintf(":%d", port),
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Listening for Keygen webhooks on %s\n", server.Addr)
+		}
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			output.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+var webhooksVerifyCmd = &cobra.Command{
+	Use:   "verify [delivery.json]",
+	Short: "Verify the signature of a captured webhook delivery",
+	Long: `Verify the signature of a captured webhook delivery file.
+
+The file holds method, path, host, headers and body. The public key is
+taken from --public-key, the file's public_key field, or the profile.
+
+Examples:
+  keygen webhooks verify testdata/webhooks/license-expiring-soon.json
+  keygen webhooks verify captured.json --profile prod`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		delivery, err := webhook.LoadDelivery(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		keyFlag, _ := cmd.Flags().GetString("public-key")
+		if keyFlag == "" {
+			keyFlag = delivery.PublicKey
+		}
+		if keyFlag == "" {
+			keyFlag = loadConfig().PublicKey
+		}
+		publicKey, err := webhook.ParsePublicKey(keyFlag)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		maxAge, _ := cmd.Flags().GetDuration("max-age")
+		if err := delivery.Verify(publicKey, maxAge); err != nil {
+			output.ErrorDetail("verification failed", err.Error())
+			os.Exit(1)
+		}
+
+		event, err := webhook.DecodeEvent([]byte(delivery.Body))
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		output.Success(webhookVerifyOutput{
+			Verified: true,
+			ID:       event.ID,
+			Event:    event.Event,
+			Payload:  event.Payload,
+		})
+	},
+}
+
+var webhooksReplayCmd = &cobra.Command{
+	Use:   "replay [delivery.json]",
+	Short: "Send a captured webhook delivery to a URL",
+	Long: `Send a captured delivery, with its original headers and signature,
+to a receiver such as 'keygen webhooks listen'.
+
+Examples:
+  keygen webhooks replay testdata/webhooks/license-expiring-soon.json --to http://localhost:8787`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		delivery, err := webhook.LoadDelivery(args[0])
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+
+		to, _ := cmd.Flags().GetString("to")
+		if to == "" {
+			output.Error("--to is required")
+			return
+		}
+
+		req, err := http.NewRequest(delivery.Method, strings.TrimRight(to, "/")+delivery.Path, bytes.NewReader([]byte(delivery.Body)))
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		for k, v := range delivery.Headers {
+			req.Header.Set(k, v)
+		}
+		// The signature covers the original host
+		if delivery.Host != "" {
+			req.Host = delivery.Host
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			output.Error(err.Error())
+			return
+		}
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+
+		output.Success(webhookReplayOutput{
+			Status: resp.StatusCode,
+			Body:   strings.TrimSpace(string(respBody)),
+		})
+	},
+}
+
+// runEventCommand runs a user command for one event with the event JSON on stdin.
+func runEventCommand(command string, timeout time.Duration, event *webhook.Event, line []byte) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	c.Stdin = bytes.NewReader(append(line, '\n'))
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), "KEYGEN_EVENT="+event.Event, "KEYGEN_EVENT_ID="+event.ID)
+	return c.Run()
+}
+
+// matchesEventFilter reports whether an event name matches one of the
+// filters. Filters may end in ".*" to match a resource's events.
+func matchesEventFilter(name string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if f == "*" || f == name {
+			return true
+		}
+		if strings.HasSuffix(f, ".*") && strings.HasPrefix(name, strings.TrimSuffix(f, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func init() {
+	webhooksEndpointsListCmd.Flags().Int("limit", 10, "Results per page")
+	webhooksEndpointsListCmd.Flags().Int("page", 1, "Page number")
+
+	webhooksEndpointsCreateCmd.Flags().String("url", "", "Endpoint URL (required)")
+	webhooksEndpointsCreateCmd.Flags().String("subscriptions", "*", "Comma-separated events to subscribe to")
+	webhooksEndpointsCreateCmd.Flags().String("signature-algorithm", "ed25519", "Signature algorithm")
+
+	webhooksEndpointsUpdateCmd.Flags().String("url", "", "Endpoint URL")
+	webhooksEndpointsUpdateCmd.Flags().String("subscriptions", "", "Comma-separated events to subscribe to")
+	webhooksEndpointsUpdateCmd.Flags().String("signature-algorithm", "", "Signature algorithm")
+
+	webhooksEndpointsDeleteCmd.Flags().Bool("force", false, "Skip confirmation")
+
+	webhooksEventsListCmd.Flags().String("event", "", "Filter by event type, e.g. license.created")
+	webhooksEventsListCmd.Flags().Int("limit", 10, "Results per page")
+	webhooksEventsListCmd.Flags().Int("page", 1, "Page number")
+
+	webhooksListenCmd.Flags().Int("port", 8787, "Port to listen on")
+	webhooksListenCmd.Flags().String("public-key", "", "Ed25519 public key (overrides the profile)")
+	webhooksListenCmd.Flags().String("events", "", "Comma-separated events to accept, e.g. license.*,machine.created")
+	webhooksListenCmd.Flags().String("exec", "", "Command to run per event (event JSON on stdin)")
+	webhooksListenCmd.Flags().Duration("exec-timeout", 30*time.Second, "Timeout for --exec commands")
+	webhooksListenCmd.Flags().Duration("max-age", 5*time.Minute, "Reject deliveries older than this (0 disables)")
+
+	webhooksVerifyCmd.Flags().String("public-key", "", "Ed25519 public key")
+	webhooksVerifyCmd.Flags().Duration("max-age", 0, "Reject deliveries older than this (0 disables)")
+
+	webhooksReplayCmd.Flags().String("to", "", "Receiver base URL (required)")
+
+	webhooksEndpointsCmd.AddCommand(webhooksEndpointsListCmd)
+	webhooksEndpointsCmd.AddCommand(webhooksEndpointsCreateCmd)
+	webhooksEndpointsCmd.AddCommand(webhooksEndpointsUpdateCmd)
+	webhooksEndpointsCmd.AddCommand(webhooksEndpointsDeleteCmd)
+
+	webhooksEventsCmd.AddCommand(webhooksEventsListCmd)
+	webhooksEventsCmd.AddCommand(webhooksEventsShowCmd)
+	webhooksEventsCmd.AddCommand(webhooksEventsRetryCmd)
+
+	webhooksCmd.AddCommand(webhooksEndpointsCmd)
+	webhooksCmd.AddCommand(webhooksEventsCmd)
+	webhooksCmd.AddCommand(webhooksListenCmd)
+	webhooksCmd.AddCommand(webhooksVerifyCmd)
+	webhooksCmd.AddCommand(webhooksReplayCmd)
+	rootCmd.AddCommand(webhooksCmd)
+}
@@ -22,12 +22,12 @@ var whoamiCmd = &cobra.Command{
 		client := api.NewClient(cfg.BaseURL, cfg.AccountID, cfg.Token)
 		_, err := client.ValidateToken()
 
-		output.Success(map[string]interface{}{
-			"profile":     cfg.ProfileName,
-			"account_id":  cfg.AccountID,
-			"base_url":    cfg.BaseURL,
-			"auth_method": "token",
-			"token_valid": err == nil,
+		output.Success(whoamiOutput{
+			Profile:    cfg.ProfileName,
+			AccountID:  cfg.AccountID,
+			BaseURL:    cfg.BaseURL,
+			AuthMethod: "token",
+			TokenValid: err == nil,
 		})
 	},
 }
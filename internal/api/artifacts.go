@@ -0,0 +1,241 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+func (c *Client) ListArtifacts(params map[string]string) ([]Artifact, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/artifacts"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing artifacts: %w", err)
+	}
+
+	artifacts := make([]Artifact, len(resources))
+	for i, res := range resources {
+		artifacts[i] = parseArtifact(res)
+	}
+
+	return artifacts, nil
+}
+
+// CreateArtifact registers an artifact for a release. Keygen answers with a
+// redirect to the storage provider; the returned URL is where the file body
+// must be uploaded.
+func (c *Client) CreateArtifact(releaseID string, attrs map[string]interface{}) (*Artifact, string, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "artifacts",
+			"attributes": attrs,
+			"relationships": map[string]interface{}{
+				"release": map[string]interface{}{
+					"data": map[string]string{"type": "releases", "id": releaseID},
+				},
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	location, data, err := c.doRequestNoRedirect("POST", "/artifacts", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, "", err
+	}
+
+	artifact, err := parseArtifactDocument(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if location == "" {
+		return nil, "", fmt.Errorf("no upload URL returned for artifact %s", artifact.ID)
+	}
+
+	return artifact, location, nil
+}
+
+// GetArtifact fetches an artifact by ID or filename along with its download
+// URL. The URL is empty when the artifact has not finished uploading.
+func (c *Client) GetArtifact(id string) (*Artifact, string, error) {
+	location, data, err := c.doRequestNoRedirect("GET", "/artifacts/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	artifact, err := parseArtifactDocument(data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return artifact, location, nil
+}
+
+func (c *Client) DeleteArtifact(id string) error {
+	_, err := c.doRequest("DELETE", "/artifacts/"+id, nil)
+	return err
+}
+
+// UploadArtifact streams size bytes from r to a storage upload URL.
+func (c *Client) UploadArtifact(uploadURL string, r io.ReaderAt, size int64) error {
+	// A SectionReader is not an io.Closer, so the caller's file stays open
+	// for retries after the HTTP client is done with the body.
+	req, err := http.NewRequest("PUT", uploadURL, io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return fmt.Errorf("creating upload request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading artifact: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return nil
+}
+
+// DownloadArtifact streams a download URL to dest, which should be a
+// temporary file: if it already exists it is taken as a partial download
+// and resumed with a Range request. It returns the number of bytes written
+// and whether the transfer was resumed.
+func (c *Client) DownloadArtifact(downloadURL, dest string) (int64, bool, error) {
+	var offset int64
+	if fi, err := os.Stat(dest); err == nil {
+		offset = fi.Size()
+	}
+
+	req, err := http.NewRequest("GET", downloadURL, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("creating download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := c.transferClient().Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("downloading artifact: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	resumed := false
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		flags |= os.O_APPEND
+		resumed = true
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete
+		return 0, true, nil
+	case resp.StatusCode >= 300:
+		return 0, false, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	default:
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(dest, flags, 0644)
+	if err != nil {
+		return 0, false, fmt.Errorf("opening %s: %w", dest, err)
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		return n, resumed, fmt.Errorf("writing %s: %w", dest, err)
+	}
+
+	return n, resumed, nil
+}
+
+// FileChecksum returns the base64-encoded SHA-512 digest of a file, which is
+// the checksum format Keygen expects for artifacts.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyChecksum checks a file against an artifact checksum. SHA-512 and
+// SHA-256 digests are accepted in either base64 or hex encoding.
+func VerifyChecksum(path, expected string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	h512 := sha512.New()
+	h256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(h512, h256), f); err != nil {
+		return false, err
+	}
+
+	for _, sum := range [][]byte{h512.Sum(nil), h256.Sum(nil)} {
+		if expected == base64.StdEncoding.EncodeToString(sum) || strings.EqualFold(expected, hex.EncodeToString(sum)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func parseArtifactDocument(data []byte) (*Artifact, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing artifact: %w", err)
+	}
+
+	artifact := parseArtifact(res)
+	return &artifact, nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadArtifactResumes(t *testing.T) {
+	content := []byte(strings.Repeat("artifact-bytes ", 1000))
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "app.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "acct", "token")
+	part := filepath.Join(t.TempDir(), "app.tar.gz.part")
+
+	tests := []struct {
+		name        string
+		existing    []byte
+		wantRange   string
+		wantResumed bool
+		wantWritten int64
+	}{
+		{"fresh", nil, "", false, int64(len(content))},
+		{"partial", content[:100], "bytes=100-", true, int64(len(content) - 100)},
+		{"complete", content, "bytes=15000-", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(part)
+			if tt.existing != nil {
+				if err := os.WriteFile(part, tt.existing, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ranges = nil
+
+			n, resumed, err := c.DownloadArtifact(srv.URL, part)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tt.wantWritten || resumed != tt.wantResumed {
+				t.Errorf("got %d bytes, resumed %v; want %d, %v", n, resumed, tt.wantWritten, tt.wantResumed)
+			}
+			if len(ranges) != 1 || ranges[0] != tt.wantRange {
+				t.Errorf("Range headers %q, want %q", ranges, tt.wantRange)
+			}
+			got, _ := os.ReadFile(part)
+			if !bytes.Equal(got, content) {
+				t.Errorf("file has %d bytes, want the %d byte artifact", len(got), len(content))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -8,20 +9,35 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/cache"
 )
 
 type Client struct {
 	BaseURL   string
 	AccountID string
 	Token     string
-	HTTP      *http.Client
+	// AuthScheme is the Authorization scheme used with Token. It defaults to
+	// "Bearer"; "License" authenticates with a license key instead.
+	AuthScheme string
+	HTTP       *http.Client
+	// MaxRetries is how many times a rate-limited (429) request is retried
+	// after waiting for the limit to reset.
+	MaxRetries int
+	// Cache, when set, serves GET responses from disk and is invalidated
+	// by writes.
+	Cache *cache.Store
+
+	rate *rateState
 }
 
 func NewClient(baseURL, accountID, token string) *Client {
 	return &Client{
-		BaseURL:   strings.TrimRight(baseURL, "/"),
-		AccountID: accountID,
-		Token:     token,
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		AccountID:  accountID,
+		Token:      token,
+		MaxRetries: 3,
+		rate:       &rateState{},
 		HTTP: &http.Client{
 			Timeout: 30 * time.Second,
 			Transport: &http.Transport{
@@ -31,17 +47,103 @@ func NewClient(baseURL, accountID, token string) *Client {
 	}
 }
 
+// NewLicenseKeyClient returns a client that authenticates as a license using
+// its key, as client SDKs do.
+func NewLicenseKeyClient(baseURL, accountID, key string) *Client {
+	c := NewClient(baseURL, accountID, key)
+	c.AuthScheme = "License"
+	return c
+}
+
+func (c *Client) authorization() string {
+	scheme := c.AuthScheme
+	if scheme == "" {
+		scheme = "Bearer"
+	}
+	return scheme + " " + c.Token
+}
+
 func (c *Client) url(path string) string {
 	return fmt.Sprintf("%s/v1/accounts/%s%s", c.BaseURL, c.AccountID, path)
 }
 
 func (c *Client) doRequest(method, path string, body io.Reader) ([]byte, error) {
+	// Buffer the body so a rate-limited request can be sent again.
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+
+	var key string
+	var cached *cache.Entry
+	if c.Cache != nil && method == "GET" {
+		key = cache.Key(c.authorization(), c.url(path))
+		entry, fresh := c.Cache.Get(key)
+		if fresh {
+			return entry.Body, nil
+		}
+		cached = entry
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, c.url(path), bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		req.Header.Set("Authorization", c.authorization())
+		req.Header.Set("Accept", "application/vnd.api+json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/vnd.api+json")
+		}
+		if cached != nil && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("executing request: %w", err)
+		}
+		c.observeRateLimit(resp)
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.MaxRetries {
+			c.rate.throttle()
+			time.Sleep(retryDelay(resp.Header, attempt))
+			continue
+		}
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			c.Cache.Touch(key, cached)
+			return cached.Body, nil
+		}
+		if resp.StatusCode >= 400 {
+			return nil, c.parseAPIError(resp.StatusCode, data)
+		}
+
+		if key != "" {
+			c.Cache.Put(key, path, resp.Header.Get("ETag"), data)
+		} else if c.Cache != nil {
+			c.Cache.Invalidate(path, payload)
+		}
+		return data, nil
+	}
+}
+
+func (c *Client) doRequestBasicAuth(method, path string, email, password string, body io.Reader) ([]byte, error) {
 	req, err := http.NewRequest(method, c.url(path), body)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.SetBasicAuth(email, password)
 	req.Header.Set("Accept", "application/vnd.api+json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/vnd.api+json")
@@ -65,34 +167,67 @@ func (c *Client) doRequest(method, path string, body io.Reader) ([]byte, error)
 	return data, nil
 }
 
-func (c *Client) doRequestBasicAuth(method, path string, email, password string, body io.Reader) ([]byte, error) {
+// doRequestNoRedirect performs an authenticated request without following
+// redirects. For 3xx responses the Location header is returned alongside the
+// body, which Keygen uses to hand out storage URLs for artifacts.
+func (c *Client) doRequestNoRedirect(method, path string, body io.Reader) (string, []byte, error) {
 	req, err := http.NewRequest(method, c.url(path), body)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return "", nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	req.SetBasicAuth(email, password)
+	req.Header.Set("Authorization", c.authorization())
 	req.Header.Set("Accept", "application/vnd.api+json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/vnd.api+json")
 	}
 
-	resp, err := c.HTTP.Do(req)
+	httpClient := *c.HTTP
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		return "", nil, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		return "", nil, fmt.Errorf("reading response: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, c.parseAPIError(resp.StatusCode, data)
+		return "", nil, c.parseAPIError(resp.StatusCode, data)
+	}
+	if c.Cache != nil && method != "GET" {
+		c.Cache.Invalidate(path, nil)
 	}
 
-	return data, nil
+	return resp.Header.Get("Location"), data, nil
+}
+
+// transferClient returns an HTTP client for large uploads and downloads. It
+// shares the API transport but has no overall timeout.
+func (c *Client) transferClient() *http.Client {
+	return &http.Client{Transport: c.HTTP.Transport}
+}
+
+// APIError is an error response from the Keygen API.
+type APIError struct {
+	StatusCode int
+	Title      string
+	Detail     string
+	Code       string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	if e.Title == "" && e.Detail == "" {
+		return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("API error %d: %s - %s (code: %s)", e.StatusCode, e.Title, e.Detail, e.Code)
 }
 
 func (c *Client) parseAPIError(statusCode int, body []byte) error {
@@ -106,8 +241,8 @@ func (c *Client) parseAPIError(statusCode int, body []byte) error {
 
 	if err := json.Unmarshal(body, &errResp); err == nil && len(errResp.Errors) > 0 {
 		e := errResp.Errors[0]
-		return fmt.Errorf("API error %d: %s - %s (code: %s)", statusCode, e.Title, e.Detail, e.Code)
+		return &APIError{StatusCode: statusCode, Title: e.Title, Detail: e.Detail, Code: e.Code}
 	}
 
-	return fmt.Errorf("API error %d: %s", statusCode, string(body))
+	return &APIError{StatusCode: statusCode, Body: string(body)}
 }
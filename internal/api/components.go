@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 func (c *Client) ListComponents(machineID string, page, limit int) ([]Component, error) {
@@ -25,7 +26,7 @@ func (c *Client) ListComponents(machineID string, page, limit int) ([]Component,
 
 	components := make([]Component, len(resources))
 	for i, res := range resources {
-		components[i] = parseComponent(res)
+		components[i] = ParseComponent(res)
 		components[i].MachineID = machineID
 	}
 
@@ -48,7 +49,74 @@ func (c *Client) GetComponent(id string) (*Component, error) {
 		return nil, fmt.Errorf("parsing component: %w", err)
 	}
 
-	comp := parseComponent(res)
+	comp := ParseComponent(res)
+	return &comp, nil
+}
+
+// CreateComponent adds a component with the given fingerprint to a machine.
+func (c *Client) CreateComponent(machineID, fingerprint, name string) (*Component, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "components",
+			"attributes": map[string]interface{}{
+				"fingerprint": fingerprint,
+				"name":        name,
+			},
+			"relationships": map[string]interface{}{
+				"machine": map[string]interface{}{
+					"data": map[string]string{"type": "machines", "id": machineID},
+				},
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("POST", "/components", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseComponentDocument(data)
+}
+
+func (c *Client) UpdateComponent(id string, attrs map[string]interface{}) (*Component, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "components",
+			"id":         id,
+			"attributes": attrs,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("PATCH", "/components/"+id, strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseComponentDocument(data)
+}
+
+func parseComponentDocument(data []byte) (*Component, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing component: %w", err)
+	}
+
+	comp := ParseComponent(res)
 	return &comp, nil
 }
 
@@ -59,6 +127,26 @@ func (c *Client) DeleteComponent(id string) error {
 
 // FindComponentByFingerprint paginates through all machines and components to find a match
 func (c *Client) FindComponentByFingerprint(fingerprint string) (*Component, error) {
+	found, err := c.FindComponentsByFingerprints([]string{fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	return found[fingerprint], nil
+}
+
+// FindComponentsByFingerprints looks up several fingerprints in a single pass
+// over all machines. Fingerprints that aren't registered are absent from the
+// returned map.
+func (c *Client) FindComponentsByFingerprints(fingerprints []string) (map[string]*Component, error) {
+	wanted := make(map[string]bool, len(fingerprints))
+	for _, fp := range fingerprints {
+		wanted[fp] = true
+	}
+	found := make(map[string]*Component)
+	if len(wanted) == 0 {
+		return found, nil
+	}
+
 	page := 1
 	for {
 		path := fmt.Sprintf("/machines?page[size]=100&page[number]=%d", page)
@@ -82,20 +170,23 @@ func (c *Client) FindComponentByFingerprint(fingerprint string) (*Component, err
 		}
 
 		for _, machRes := range machines {
-			machine := parseMachine(machRes)
+			machine := ParseMachine(machRes)
 			comps, err := c.ListComponents(machine.ID, 1, 100)
 			if err != nil {
 				continue
 			}
-			for _, comp := range comps {
-				if comp.Fingerprint == fingerprint {
-					return &comp, nil
+			for i := range comps {
+				if wanted[comps[i].Fingerprint] && found[comps[i].Fingerprint] == nil {
+					found[comps[i].Fingerprint] = &comps[i]
 				}
 			}
+			if len(found) == len(wanted) {
+				return found, nil
+			}
 		}
 
 		page++
 	}
 
-	return nil, nil
+	return found, nil
 }
@@ -37,7 +37,7 @@ func (c *Client) ListLicenses(params map[string]string) ([]License, error) {
 
 	licenses := make([]License, len(resources))
 	for i, res := range resources {
-		licenses[i] = parseLicense(res)
+		licenses[i] = ParseLicense(res)
 	}
 
 	return licenses, nil
@@ -59,12 +59,32 @@ func (c *Client) GetLicense(id string) (*License, error) {
 		return nil, fmt.Errorf("parsing license: %w", err)
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
 	return &license, nil
 }
 
 func (c *Client) ValidateLicense(id string) (*LicenseValidation, *License, error) {
-	data, err := c.doRequest("POST", "/licenses/"+id+"/actions/validate", nil)
+	return c.validateLicense(id, nil)
+}
+
+// ValidateLicenseFingerprint validates a license scoped to a machine
+// fingerprint, as a client SDK would on startup.
+func (c *Client) ValidateLicenseFingerprint(id, fingerprint string) (*LicenseValidation, *License, error) {
+	return c.validateLicense(id, map[string]interface{}{"fingerprint": fingerprint})
+}
+
+func (c *Client) validateLicense(id string, scope map[string]interface{}) (*LicenseValidation, *License, error) {
+	var data []byte
+	var err error
+	if scope != nil {
+		bodyBytes, merr := json.Marshal(map[string]interface{}{"meta": map[string]interface{}{"scope": scope}})
+		if merr != nil {
+			return nil, nil, fmt.Errorf("marshaling request: %w", merr)
+		}
+		data, err = c.doRequest("POST", "/licenses/"+id+"/actions/validate", strings.NewReader(string(bodyBytes)))
+	} else {
+		data, err = c.doRequest("POST", "/licenses/"+id+"/actions/validate", nil)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -95,7 +115,7 @@ func (c *Client) ValidateLicense(id string) (*LicenseValidation, *License, error
 		return validation, nil, nil
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
 	return validation, &license, nil
 }
 
@@ -115,7 +135,7 @@ func (c *Client) RenewLicense(id string) (*License, error) {
 		return nil, fmt.Errorf("parsing license: %w", err)
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
 	return &license, nil
 }
 
@@ -139,14 +159,14 @@ func (c *Client) GetLicenseMachines(licenseID string) ([]Machine, error) {
 	componentMap := make(map[string][]Component)
 	for _, inc := range doc.Included {
 		if inc.Type == "components" {
-			comp := parseComponent(inc)
+			comp := ParseComponent(inc)
 			componentMap[comp.MachineID] = append(componentMap[comp.MachineID], comp)
 		}
 	}
 
 	machines := make([]Machine, len(resources))
 	for i, res := range resources {
-		machines[i] = parseMachine(res)
+		machines[i] = ParseMachine(res)
 		if comps, ok := componentMap[machines[i].ID]; ok {
 			machines[i].Components = comps
 		}
@@ -171,7 +191,7 @@ func (c *Client) SuspendLicense(id string) (*License, error) {
 		return nil, fmt.Errorf("parsing license: %w", err)
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
 	return &license, nil
 }
 
@@ -191,7 +211,7 @@ func (c *Client) ReinstateLicense(id string) (*License, error) {
 		return nil, fmt.Errorf("parsing license: %w", err)
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
 	return &license, nil
 }
 
@@ -226,6 +246,89 @@ func (c *Client) UpdateLicenseMetadata(id string, metadata map[string]interface{
 		return nil, fmt.Errorf("parsing license: %w", err)
 	}
 
-	license := parseLicense(res)
+	license := ParseLicense(res)
+	return &license, nil
+}
+
+// ChangeLicensePolicy moves a license to another policy via the policy
+// relationship endpoint.
+func (c *Client) ChangeLicensePolicy(id, policyID string) (*License, error) {
+	return c.changeLicenseRelationship(id, "policy", "policies", policyID)
+}
+
+// ChangeLicenseOwner assigns a license to another user via the owner
+// relationship endpoint.
+func (c *Client) ChangeLicenseOwner(id, userID string) (*License, error) {
+	return c.changeLicenseRelationship(id, "owner", "users", userID)
+}
+
+func (c *Client) changeLicenseRelationship(id, relationship, relType, relID string) (*License, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": relType,
+			"id":   relID,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	if _, err := c.doRequest("PUT", "/licenses/"+id+"/"+relationship, strings.NewReader(string(bodyBytes))); err != nil {
+		return nil, err
+	}
+
+	// The relationship endpoint returns the related resource, not the license
+	return c.GetLicense(id)
+}
+
+// IncrementLicenseUsage adds n to the license's uses counter.
+func (c *Client) IncrementLicenseUsage(id string, n int) (*License, error) {
+	return c.licenseUsageAction(id, "increment-usage", map[string]interface{}{"increment": n})
+}
+
+// DecrementLicenseUsage subtracts n from the license's uses counter.
+func (c *Client) DecrementLicenseUsage(id string, n int) (*License, error) {
+	return c.licenseUsageAction(id, "decrement-usage", map[string]interface{}{"decrement": n})
+}
+
+// ResetLicenseUsage sets the license's uses counter back to zero.
+func (c *Client) ResetLicenseUsage(id string) (*License, error) {
+	return c.licenseUsageAction(id, "reset-usage", nil)
+}
+
+func (c *Client) licenseUsageAction(id, action string, meta map[string]interface{}) (*License, error) {
+	var body *strings.Reader
+	if meta != nil {
+		bodyBytes, err := json.Marshal(map[string]interface{}{"meta": meta})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling request: %w", err)
+		}
+		body = strings.NewReader(string(bodyBytes))
+	}
+
+	var data []byte
+	var err error
+	if body != nil {
+		data, err = c.doRequest("POST", "/licenses/"+id+"/actions/"+action, body)
+	} else {
+		data, err = c.doRequest("POST", "/licenses/"+id+"/actions/"+action, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing license: %w", err)
+	}
+
+	license := ParseLicense(res)
 	return &license, nil
 }
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (c *Client) ListRequestLogs(params map[string]string) ([]RequestLog, error) {
+	data, err := c.doRequest("GET", logsPath("/request-logs", params), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing request logs: %w", err)
+	}
+
+	logs := make([]RequestLog, len(resources))
+	for i, res := range resources {
+		logs[i] = parseRequestLog(res)
+	}
+
+	return logs, nil
+}
+
+func (c *Client) ListEventLogs(params map[string]string) ([]EventLog, error) {
+	data, err := c.doRequest("GET", logsPath("/event-logs", params), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing event logs: %w", err)
+	}
+
+	logs := make([]EventLog, len(resources))
+	for i, res := range resources {
+		logs[i] = parseEventLog(res)
+	}
+
+	return logs, nil
+}
+
+func logsPath(base string, params map[string]string) string {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	if len(query) > 0 {
+		return base + "?" + query.Encode()
+	}
+	return base
+}
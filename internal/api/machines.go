@@ -3,8 +3,46 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 )
 
+func (c *Client) ListMachines(params map[string]string) ([]Machine, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/machines"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing machines: %w", err)
+	}
+
+	machines := make([]Machine, len(resources))
+	for i, res := range resources {
+		machines[i] = ParseMachine(res)
+	}
+
+	return machines, nil
+}
+
 func (c *Client) GetMachine(id string) (*Machine, error) {
 	data, err := c.doRequest("GET", "/machines/"+id+"?include=components", nil)
 	if err != nil {
@@ -21,15 +59,81 @@ func (c *Client) GetMachine(id string) (*Machine, error) {
 		return nil, fmt.Errorf("parsing machine: %w", err)
 	}
 
-	machine := parseMachine(res)
+	machine := ParseMachine(res)
 
 	// Parse included components
 	for _, inc := range doc.Included {
 		if inc.Type == "components" {
-			comp := parseComponent(inc)
+			comp := ParseComponent(inc)
 			machine.Components = append(machine.Components, comp)
 		}
 	}
 
 	return &machine, nil
 }
+
+// DeactivateMachine deletes a machine, freeing its slot on the license.
+func (c *Client) DeactivateMachine(id string) error {
+	_, err := c.doRequest("DELETE", "/machines/"+id, nil)
+	return err
+}
+
+// ActivateMachine creates a machine for a license. Components, if any, are
+// created together with the machine.
+func (c *Client) ActivateMachine(licenseID string, attrs map[string]interface{}, components []Component) (*Machine, error) {
+	relationships := map[string]interface{}{
+		"license": map[string]interface{}{
+			"data": map[string]string{"type": "licenses", "id": licenseID},
+		},
+	}
+	if len(components) > 0 {
+		compData := make([]map[string]interface{}, len(components))
+		for i, comp := range components {
+			compData[i] = map[string]interface{}{
+				"type": "components",
+				"attributes": map[string]interface{}{
+					"fingerprint": comp.Fingerprint,
+					"name":        comp.Name,
+				},
+			}
+		}
+		relationships["components"] = map[string]interface{}{"data": compData}
+	}
+
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":          "machines",
+			"attributes":    attrs,
+			"relationships": relationships,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("POST", "/machines?include=components", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing machine: %w", err)
+	}
+
+	machine := ParseMachine(res)
+	for _, inc := range doc.Included {
+		if inc.Type == "components" {
+			machine.Components = append(machine.Components, ParseComponent(inc))
+		}
+	}
+
+	return &machine, nil
+}
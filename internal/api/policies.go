@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (c *Client) ListPolicies(params map[string]string) ([]Policy, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/policies"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing policies: %w", err)
+	}
+
+	policies := make([]Policy, len(resources))
+	for i, res := range resources {
+		policies[i] = parsePolicy(res)
+	}
+
+	return policies, nil
+}
+
+func (c *Client) GetPolicy(id string) (*Policy, error) {
+	data, err := c.doRequest("GET", "/policies/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+
+	policy := parsePolicy(res)
+	return &policy, nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+func (c *Client) ListProcesses(params map[string]string) ([]Process, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/processes"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing processes: %w", err)
+	}
+
+	processes := make([]Process, len(resources))
+	for i, res := range resources {
+		processes[i] = parseProcess(res)
+	}
+
+	return processes, nil
+}
+
+func (c *Client) GetProcess(id string) (*Process, error) {
+	data, err := c.doRequest("GET", "/processes/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing process: %w", err)
+	}
+
+	process := parseProcess(res)
+	return &process, nil
+}
+
+// KillProcess deletes a process, freeing its slot on the machine.
+func (c *Client) KillProcess(id string) error {
+	_, err := c.doRequest("DELETE", "/processes/"+id, nil)
+	return err
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the account's API rate limit as last reported by Keygen.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	// Throttled counts requests that were rate limited and retried.
+	Throttled int `json:"throttled"`
+}
+
+type rateState struct {
+	mu    sync.Mutex
+	limit RateLimit
+}
+
+func (r *rateState) throttle() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.limit.Throttled++
+	r.mu.Unlock()
+}
+
+// RateLimit returns the rate limit headers of the most recent response.
+// Limit is 0 until Keygen has reported one.
+func (c *Client) RateLimit() RateLimit {
+	if c.rate == nil {
+		return RateLimit{}
+	}
+	c.rate.mu.Lock()
+	defer c.rate.mu.Unlock()
+	return c.rate.limit
+}
+
+func (c *Client) observeRateLimit(resp *http.Response) {
+	if c.rate == nil {
+		return
+	}
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	c.rate.mu.Lock()
+	defer c.rate.mu.Unlock()
+	c.rate.limit.Limit = limit
+	c.rate.limit.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		c.rate.limit.Reset = time.Unix(reset, 0)
+	}
+}
+
+// retryDelay is how long to wait before retrying a 429: Retry-After when
+// given, else until X-RateLimit-Reset, else a short backoff. It is capped so
+// a bad header can't stall the CLI.
+func retryDelay(h http.Header, attempt int) time.Duration {
+	const maxDelay = 60 * time.Second
+	d := time.Duration(1<<attempt) * time.Second
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs >= 0 {
+		d = time.Duration(secs) * time.Second
+	} else if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if until := time.Until(time.Unix(reset, 0)); until > 0 {
+			d = until
+		}
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
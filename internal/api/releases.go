@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+func (c *Client) ListReleases(params map[string]string) ([]Release, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/releases"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing releases: %w", err)
+	}
+
+	releases := make([]Release, len(resources))
+	for i, res := range resources {
+		releases[i] = parseRelease(res)
+	}
+
+	return releases, nil
+}
+
+func (c *Client) GetRelease(id string) (*Release, error) {
+	data, err := c.doRequest("GET", "/releases/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReleaseDocument(data)
+}
+
+// CreateRelease creates a draft release for a product.
+func (c *Client) CreateRelease(productID string, attrs map[string]interface{}) (*Release, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "releases",
+			"attributes": attrs,
+			"relationships": map[string]interface{}{
+				"product": map[string]interface{}{
+					"data": map[string]string{"type": "products", "id": productID},
+				},
+			},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("POST", "/releases", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReleaseDocument(data)
+}
+
+func (c *Client) PublishRelease(id string) (*Release, error) {
+	data, err := c.doRequest("POST", "/releases/"+id+"/actions/publish", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReleaseDocument(data)
+}
+
+func (c *Client) YankRelease(id string) (*Release, error) {
+	data, err := c.doRequest("POST", "/releases/"+id+"/actions/yank", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseReleaseDocument(data)
+}
+
+func (c *Client) DeleteRelease(id string) error {
+	_, err := c.doRequest("DELETE", "/releases/"+id, nil)
+	return err
+}
+
+func parseReleaseDocument(data []byte) (*Release, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing release: %w", err)
+	}
+
+	release := parseRelease(res)
+	return &release, nil
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ListResources fetches one page of a collection as raw JSON:API resources,
+// keeping attributes the typed structs don't model.
+func (c *Client) ListResources(path string, params map[string]string) ([]JSONAPIResource, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing resources: %w", err)
+	}
+	return resources, nil
+}
+
+// CreateResource creates a resource of the given type in the collection at
+// path. Relationships map a relationship name to the related resource.
+func (c *Client) CreateResource(path, resType string, attrs map[string]interface{}, relationships map[string]RelationshipData) (*JSONAPIResource, error) {
+	return c.writeResource("POST", path, resType, "", attrs, relationships)
+}
+
+// UpdateResource patches the attributes of the resource at path.
+func (c *Client) UpdateResource(path, resType, id string, attrs map[string]interface{}) (*JSONAPIResource, error) {
+	return c.writeResource("PATCH", path, resType, id, attrs, nil)
+}
+
+func (c *Client) writeResource(method, path, resType, id string, attrs map[string]interface{}, relationships map[string]RelationshipData) (*JSONAPIResource, error) {
+	resource := map[string]interface{}{
+		"type":       resType,
+		"attributes": attrs,
+	}
+	if id != "" {
+		resource["id"] = id
+	}
+	if len(relationships) > 0 {
+		rels := map[string]interface{}{}
+		for name, rd := range relationships {
+			rels[name] = map[string]interface{}{"data": rd}
+		}
+		resource["relationships"] = rels
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"data": resource})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest(method, path, strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", resType, err)
+	}
+	return &res, nil
+}
+
+// RelationshipID returns the ID of a to-one relationship, or "" when the
+// resource has none by that name.
+func (r JSONAPIResource) RelationshipID(name string) string {
+	rel, ok := r.Relationships[name]
+	if !ok {
+		return ""
+	}
+	return extractRelID(rel)
+}
+
+// AttachEntitlements attaches entitlements to the policy or license at
+// parent, e.g. "/policies/<id>".
+func (c *Client) AttachEntitlements(parent string, entitlementIDs []string) error {
+	return c.entitlementsAction("POST", parent, entitlementIDs)
+}
+
+// DetachEntitlements detaches entitlements from the policy or license at
+// parent.
+func (c *Client) DetachEntitlements(parent string, entitlementIDs []string) error {
+	return c.entitlementsAction("DELETE", parent, entitlementIDs)
+}
+
+func (c *Client) entitlementsAction(method, parent string, entitlementIDs []string) error {
+	data := make([]map[string]string, len(entitlementIDs))
+	for i, id := range entitlementIDs {
+		data[i] = map[string]string{"type": "entitlements", "id": id}
+	}
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	_, err = c.doRequest(method, parent+"/entitlements", strings.NewReader(string(bodyBytes)))
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 )
 
 func (c *Client) CreateToken(email, password string) (*Token, error) {
@@ -27,13 +28,49 @@ func (c *Client) CreateToken(email, password string) (*Token, error) {
 	return &token, nil
 }
 
+func (c *Client) ListTokens(params map[string]string) ([]Token, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/tokens"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing tokens: %w", err)
+	}
+
+	tokens := make([]Token, len(resources))
+	for i, res := range resources {
+		tokens[i] = parseToken(res)
+	}
+
+	return tokens, nil
+}
+
 func (c *Client) ValidateToken() (map[string]interface{}, error) {
 	req, err := http.NewRequest("GET", c.url("/me"), nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Authorization", c.authorization())
 	req.Header.Set("Accept", "application/vnd.api+json")
 
 	resp, err := c.HTTP.Do(req)
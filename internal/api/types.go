@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -35,31 +36,64 @@ type RelationshipData struct {
 // Domain types
 
 type License struct {
-	ID        string                 `json:"id"`
-	Key       string                 `json:"key"`
-	Name      string                 `json:"name"`
-	Status    string                 `json:"status"`
-	Expiry    string                 `json:"expiry"`
-	Created   string                 `json:"created"`
-	Updated   string                 `json:"updated"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
-	PolicyID  string                 `json:"policy_id,omitempty"`
-	ProductID string                 `json:"product_id,omitempty"`
-	OwnerID   string                 `json:"owner_id,omitempty"`
+	ID            string                 `json:"id"`
+	Key           string                 `json:"key"`
+	Name          string                 `json:"name"`
+	Status        string                 `json:"status"`
+	Expiry        string                 `json:"expiry"`
+	Created       string                 `json:"created"`
+	Updated       string                 `json:"updated"`
+	Uses          int                    `json:"uses"`
+	MaxUses       int                    `json:"max_uses,omitempty"`
+	MaxMachines   int                    `json:"max_machines,omitempty"`
+	MaxCores      int                    `json:"max_cores,omitempty"`
+	LastValidated string                 `json:"last_validated,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	PolicyID      string                 `json:"policy_id,omitempty"`
+	ProductID     string                 `json:"product_id,omitempty"`
+	OwnerID       string                 `json:"owner_id,omitempty"`
+}
+
+type Policy struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Duration    int                    `json:"duration,omitempty"`
+	MaxMachines int                    `json:"max_machines,omitempty"`
+	Floating    bool                   `json:"floating"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Created     string                 `json:"created"`
+	Updated     string                 `json:"updated"`
+	ProductID   string                 `json:"product_id,omitempty"`
 }
 
 type Machine struct {
-	ID          string      `json:"id"`
-	Fingerprint string      `json:"fingerprint"`
-	Name        string      `json:"name"`
-	Hostname    string      `json:"hostname"`
-	Platform    string      `json:"platform"`
-	IP          string      `json:"ip"`
-	Cores       int         `json:"cores"`
-	Created     string      `json:"created"`
-	Updated     string      `json:"updated"`
-	LicenseID   string      `json:"license_id,omitempty"`
-	Components  []Component `json:"components,omitempty"`
+	ID               string      `json:"id"`
+	Fingerprint      string      `json:"fingerprint"`
+	Name             string      `json:"name"`
+	Hostname         string      `json:"hostname"`
+	Platform         string      `json:"platform"`
+	IP               string      `json:"ip"`
+	Cores            int         `json:"cores"`
+	HeartbeatStatus  string      `json:"heartbeat_status,omitempty"`
+	LastHeartbeat    string      `json:"last_heartbeat,omitempty"`
+	RequireHeartbeat bool        `json:"require_heartbeat"`
+	MaxProcesses     int         `json:"max_processes,omitempty"`
+	Created          string      `json:"created"`
+	Updated          string      `json:"updated"`
+	LicenseID        string      `json:"license_id,omitempty"`
+	Components       []Component `json:"components,omitempty"`
+}
+
+type Process struct {
+	ID            string                 `json:"id"`
+	PID           string                 `json:"pid"`
+	Status        string                 `json:"status"`
+	LastHeartbeat string                 `json:"last_heartbeat,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Created       string                 `json:"created"`
+	Updated       string                 `json:"updated"`
+	MachineID     string                 `json:"machine_id,omitempty"`
+	LicenseID     string                 `json:"license_id,omitempty"`
 }
 
 type Component struct {
@@ -93,6 +127,84 @@ type Token struct {
 	BearerType string `json:"bearer_type,omitempty"`
 }
 
+type Release struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Channel     string                 `json:"channel"`
+	Status      string                 `json:"status"`
+	Version     string                 `json:"version"`
+	Tag         string                 `json:"tag,omitempty"`
+	Created     string                 `json:"created"`
+	Updated     string                 `json:"updated"`
+	Yanked      string                 `json:"yanked,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	ProductID   string                 `json:"product_id,omitempty"`
+}
+
+type Artifact struct {
+	ID        string `json:"id"`
+	Filename  string `json:"filename"`
+	Filetype  string `json:"filetype"`
+	Filesize  int64  `json:"filesize"`
+	Platform  string `json:"platform"`
+	Arch      string `json:"arch"`
+	Checksum  string `json:"checksum,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Status    string `json:"status"`
+	Created   string `json:"created"`
+	Updated   string `json:"updated"`
+	ReleaseID string `json:"release_id,omitempty"`
+}
+
+type WebhookEndpoint struct {
+	ID                 string   `json:"id"`
+	URL                string   `json:"url"`
+	Subscriptions      []string `json:"subscriptions"`
+	SignatureAlgorithm string   `json:"signature_algorithm,omitempty"`
+	APIVersion         string   `json:"api_version,omitempty"`
+	Created            string   `json:"created"`
+	Updated            string   `json:"updated"`
+}
+
+type WebhookEvent struct {
+	ID               string `json:"id"`
+	Event            string `json:"event"`
+	Endpoint         string `json:"endpoint"`
+	Status           string `json:"status"`
+	Payload          string `json:"payload,omitempty"`
+	LastResponseCode int    `json:"last_response_code,omitempty"`
+	LastResponseBody string `json:"last_response_body,omitempty"`
+	Created          string `json:"created"`
+	Updated          string `json:"updated"`
+}
+
+type RequestLog struct {
+	ID            string `json:"id"`
+	Method        string `json:"method"`
+	URL           string `json:"url"`
+	Status        string `json:"status"`
+	IP            string `json:"ip"`
+	UserAgent     string `json:"user_agent,omitempty"`
+	RequestorType string `json:"requestor_type,omitempty"`
+	RequestorID   string `json:"requestor_id,omitempty"`
+	ResourceType  string `json:"resource_type,omitempty"`
+	ResourceID    string `json:"resource_id,omitempty"`
+	Created       string `json:"created"`
+}
+
+type EventLog struct {
+	ID            string                 `json:"id"`
+	Event         string                 `json:"event"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	WhodunnitType string                 `json:"whodunnit_type,omitempty"`
+	WhodunnitID   string                 `json:"whodunnit_id,omitempty"`
+	ResourceType  string                 `json:"resource_type,omitempty"`
+	ResourceID    string                 `json:"resource_id,omitempty"`
+	RequestLogID  string                 `json:"request_log_id,omitempty"`
+	Created       string                 `json:"created"`
+}
+
 type LicenseValidation struct {
 	Valid    bool                   `json:"valid"`
 	Detail   string                 `json:"detail"`
@@ -100,18 +212,24 @@ type LicenseValidation struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// Parse functions
+// Parse functions. The exported ones also read the resources stored by
+// 'keygen sync'.
 
-func parseLicense(res JSONAPIResource) License {
+func ParseLicense(res JSONAPIResource) License {
 	attr := res.Attributes
 	l := License{
-		ID:      res.ID,
-		Key:     strVal(attr, "key"),
-		Name:    strVal(attr, "name"),
-		Status:  strVal(attr, "status"),
-		Expiry:  strVal(attr, "expiry"),
-		Created: strVal(attr, "created"),
-		Updated: strVal(attr, "updated"),
+		ID:            res.ID,
+		Key:           strVal(attr, "key"),
+		Name:          strVal(attr, "name"),
+		Status:        strVal(attr, "status"),
+		Expiry:        strVal(attr, "expiry"),
+		Created:       strVal(attr, "created"),
+		Updated:       strVal(attr, "updated"),
+		Uses:          intVal(attr, "uses"),
+		MaxUses:       intVal(attr, "maxUses"),
+		MaxMachines:   intVal(attr, "maxMachines"),
+		MaxCores:      intVal(attr, "maxCores"),
+		LastValidated: strVal(attr, "lastValidated"),
 	}
 
 	if md, ok := attr["metadata"].(map[string]interface{}); ok {
@@ -132,22 +250,53 @@ func parseLicense(res JSONAPIResource) License {
 	return l
 }
 
-func parseMachine(res JSONAPIResource) Machine {
+func parsePolicy(res JSONAPIResource) Policy {
 	attr := res.Attributes
-	m := Machine{
+	p := Policy{
 		ID:          res.ID,
-		Fingerprint: strVal(attr, "fingerprint"),
 		Name:        strVal(attr, "name"),
-		Hostname:    strVal(attr, "hostname"),
-		Platform:    strVal(attr, "platform"),
-		IP:          strVal(attr, "ip"),
+		Duration:    intVal(attr, "duration"),
+		MaxMachines: intVal(attr, "maxMachines"),
 		Created:     strVal(attr, "created"),
 		Updated:     strVal(attr, "updated"),
 	}
 
+	if v, ok := attr["floating"].(bool); ok {
+		p.Floating = v
+	}
+	if md, ok := attr["metadata"].(map[string]interface{}); ok {
+		p.Metadata = md
+	}
+
+	if rel, ok := res.Relationships["product"]; ok {
+		p.ProductID = extractRelID(rel)
+	}
+
+	return p
+}
+
+func ParseMachine(res JSONAPIResource) Machine {
+	attr := res.Attributes
+	m := Machine{
+		ID:              res.ID,
+		Fingerprint:     strVal(attr, "fingerprint"),
+		Name:            strVal(attr, "name"),
+		Hostname:        strVal(attr, "hostname"),
+		Platform:        strVal(attr, "platform"),
+		IP:              strVal(attr, "ip"),
+		HeartbeatStatus: strVal(attr, "heartbeatStatus"),
+		LastHeartbeat:   strVal(attr, "lastHeartbeat"),
+		MaxProcesses:    intVal(attr, "maxProcesses"),
+		Created:         strVal(attr, "created"),
+		Updated:         strVal(attr, "updated"),
+	}
+
 	if cores, ok := attr["cores"].(float64); ok {
 		m.Cores = int(cores)
 	}
+	if v, ok := attr["requireHeartbeat"].(bool); ok {
+		m.RequireHeartbeat = v
+	}
 
 	if rel, ok := res.Relationships["license"]; ok {
 		m.LicenseID = extractRelID(rel)
@@ -156,7 +305,32 @@ func parseMachine(res JSONAPIResource) Machine {
 	return m
 }
 
-func parseComponent(res JSONAPIResource) Component {
+func parseProcess(res JSONAPIResource) Process {
+	attr := res.Attributes
+	p := Process{
+		ID:            res.ID,
+		PID:           strVal(attr, "pid"),
+		Status:        strVal(attr, "status"),
+		LastHeartbeat: strVal(attr, "lastHeartbeat"),
+		Created:       strVal(attr, "created"),
+		Updated:       strVal(attr, "updated"),
+	}
+
+	if md, ok := attr["metadata"].(map[string]interface{}); ok {
+		p.Metadata = md
+	}
+
+	if rel, ok := res.Relationships["machine"]; ok {
+		p.MachineID = extractRelID(rel)
+	}
+	if rel, ok := res.Relationships["license"]; ok {
+		p.LicenseID = extractRelID(rel)
+	}
+
+	return p
+}
+
+func ParseComponent(res JSONAPIResource) Component {
 	attr := res.Attributes
 	c := Component{
 		ID:          res.ID,
@@ -173,7 +347,7 @@ func parseComponent(res JSONAPIResource) Component {
 	return c
 }
 
-func parseUser(res JSONAPIResource) User {
+func ParseUser(res JSONAPIResource) User {
 	attr := res.Attributes
 	u := User{
 		ID:        res.ID,
@@ -204,12 +378,164 @@ func parseToken(res JSONAPIResource) Token {
 	}
 
 	if rel, ok := res.Relationships["bearer"]; ok {
-		t.BearerID = extractRelID(rel)
+		bearer := extractRel(rel)
+		t.BearerID = bearer.ID
+		t.BearerType = bearer.Type
 	}
 
 	return t
 }
 
+func parseRelease(res JSONAPIResource) Release {
+	attr := res.Attributes
+	r := Release{
+		ID:          res.ID,
+		Name:        strVal(attr, "name"),
+		Description: strVal(attr, "description"),
+		Channel:     strVal(attr, "channel"),
+		Status:      strVal(attr, "status"),
+		Version:     strVal(attr, "version"),
+		Tag:         strVal(attr, "tag"),
+		Created:     strVal(attr, "created"),
+		Updated:     strVal(attr, "updated"),
+		Yanked:      strVal(attr, "yanked"),
+	}
+
+	if md, ok := attr["metadata"].(map[string]interface{}); ok {
+		r.Metadata = md
+	}
+
+	if rel, ok := res.Relationships["product"]; ok {
+		r.ProductID = extractRelID(rel)
+	}
+
+	return r
+}
+
+func parseArtifact(res JSONAPIResource) Artifact {
+	attr := res.Attributes
+	a := Artifact{
+		ID:        res.ID,
+		Filename:  strVal(attr, "filename"),
+		Filetype:  strVal(attr, "filetype"),
+		Platform:  strVal(attr, "platform"),
+		Arch:      strVal(attr, "arch"),
+		Checksum:  strVal(attr, "checksum"),
+		Signature: strVal(attr, "signature"),
+		Status:    strVal(attr, "status"),
+		Created:   strVal(attr, "created"),
+		Updated:   strVal(attr, "updated"),
+	}
+
+	if size, ok := attr["filesize"].(float64); ok {
+		a.Filesize = int64(size)
+	}
+
+	if rel, ok := res.Relationships["release"]; ok {
+		a.ReleaseID = extractRelID(rel)
+	}
+
+	return a
+}
+
+func parseWebhookEndpoint(res JSONAPIResource) WebhookEndpoint {
+	attr := res.Attributes
+	w := WebhookEndpoint{
+		ID:                 res.ID,
+		URL:                strVal(attr, "url"),
+		SignatureAlgorithm: strVal(attr, "signatureAlgorithm"),
+		APIVersion:         strVal(attr, "apiVersion"),
+		Created:            strVal(attr, "created"),
+		Updated:            strVal(attr, "updated"),
+	}
+
+	if subs, ok := attr["subscriptions"].([]interface{}); ok {
+		for _, s := range subs {
+			if str, ok := s.(string); ok {
+				w.Subscriptions = append(w.Subscriptions, str)
+			}
+		}
+	}
+
+	return w
+}
+
+func parseWebhookEvent(res JSONAPIResource) WebhookEvent {
+	attr := res.Attributes
+	e := WebhookEvent{
+		ID:               res.ID,
+		Event:            strVal(attr, "event"),
+		Endpoint:         strVal(attr, "endpoint"),
+		Status:           strVal(attr, "status"),
+		Payload:          strVal(attr, "payload"),
+		LastResponseBody: strVal(attr, "lastResponseBody"),
+		Created:          strVal(attr, "created"),
+		Updated:          strVal(attr, "updated"),
+	}
+
+	if code, ok := attr["lastResponseCode"].(float64); ok {
+		e.LastResponseCode = int(code)
+	}
+
+	return e
+}
+
+func parseRequestLog(res JSONAPIResource) RequestLog {
+	attr := res.Attributes
+	l := RequestLog{
+		ID:        res.ID,
+		Method:    strVal(attr, "method"),
+		URL:       strVal(attr, "url"),
+		Status:    strVal(attr, "status"),
+		IP:        strVal(attr, "ip"),
+		UserAgent: strVal(attr, "userAgent"),
+		Created:   strVal(attr, "created"),
+	}
+
+	// Older servers report the status code as a number
+	if code, ok := attr["status"].(float64); ok {
+		l.Status = fmt.Sprintf("%d", int(code))
+	}
+
+	if rel, ok := res.Relationships["requestor"]; ok {
+		rd := extractRel(rel)
+		l.RequestorType, l.RequestorID = rd.Type, rd.ID
+	}
+	if rel, ok := res.Relationships["resource"]; ok {
+		rd := extractRel(rel)
+		l.ResourceType, l.ResourceID = rd.Type, rd.ID
+	}
+
+	return l
+}
+
+func parseEventLog(res JSONAPIResource) EventLog {
+	attr := res.Attributes
+	l := EventLog{
+		ID:      res.ID,
+		Event:   strVal(attr, "event"),
+		Created: strVal(attr, "created"),
+	}
+
+	if md, ok := attr["metadata"].(map[string]interface{}); ok {
+		l.Metadata = md
+	}
+
+	if rel, ok := res.Relationships["whodunnit"]; ok {
+		rd := extractRel(rel)
+		l.WhodunnitType, l.WhodunnitID = rd.Type, rd.ID
+	}
+	if rel, ok := res.Relationships["resource"]; ok {
+		rd := extractRel(rel)
+		l.ResourceType, l.ResourceID = rd.Type, rd.ID
+	}
+	if rel, ok := res.Relationships["request"]; ok {
+		l.RequestLogID = extractRelID(rel)
+	}
+
+	return l
+}
+
 // Helper functions
 
 func strVal(m map[string]interface{}, key string) string {
@@ -221,12 +547,23 @@ func strVal(m map[string]interface{}, key string) string {
 	return ""
 }
 
+// intVal returns a numeric attribute as an int. Missing and null values
+// (e.g. an unlimited maxUses) are returned as zero.
+func intVal(m map[string]interface{}, key string) int {
+	if v, ok := m[key].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
 func extractRelID(rel Relationship) string {
+	return extractRel(rel).ID
+}
+
+func extractRel(rel Relationship) RelationshipData {
 	var rd RelationshipData
-	if err := json.Unmarshal(rel.Data, &rd); err == nil {
-		return rd.ID
-	}
-	return ""
+	_ = json.Unmarshal(rel.Data, &rd)
+	return rd
 }
 
 func ParseTime(s string) (time.Time, error) {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -37,7 +38,7 @@ func (c *Client) ListUsers(params map[string]string) ([]User, error) {
 
 	users := make([]User, len(resources))
 	for i, res := range resources {
-		users[i] = parseUser(res)
+		users[i] = ParseUser(res)
 	}
 
 	return users, nil
@@ -59,17 +60,20 @@ func (c *Client) GetUser(id string) (*User, error) {
 		return nil, fmt.Errorf("parsing user: %w", err)
 	}
 
-	user := parseUser(res)
+	user := ParseUser(res)
 	return &user, nil
 }
 
+// ErrUserNotFound is returned by FindUserByEmail when no user has the email.
+var ErrUserNotFound = errors.New("user not found")
+
 func (c *Client) FindUserByEmail(email string) (*User, error) {
 	users, err := c.ListUsers(map[string]string{"email": email})
 	if err != nil {
 		return nil, err
 	}
 	if len(users) == 0 {
-		return nil, fmt.Errorf("user not found: %s", email)
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, email)
 	}
 	return &users[0], nil
 }
@@ -92,7 +96,7 @@ func (c *Client) GetUserLicenses(userID string) ([]License, error) {
 
 	licenses := make([]License, len(resources))
 	for i, res := range resources {
-		licenses[i] = parseLicense(res)
+		licenses[i] = ParseLicense(res)
 	}
 
 	return licenses, nil
@@ -127,6 +131,6 @@ func (c *Client) UpdateUser(id string, attrs map[string]interface{}) (*User, err
 		return nil, fmt.Errorf("parsing user: %w", err)
 	}
 
-	user := parseUser(res)
+	user := ParseUser(res)
 	return &user, nil
 }
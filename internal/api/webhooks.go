@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+func (c *Client) ListWebhookEndpoints(params map[string]string) ([]WebhookEndpoint, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/webhook-endpoints"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing webhook endpoints: %w", err)
+	}
+
+	endpoints := make([]WebhookEndpoint, len(resources))
+	for i, res := range resources {
+		endpoints[i] = parseWebhookEndpoint(res)
+	}
+
+	return endpoints, nil
+}
+
+func (c *Client) GetWebhookEndpoint(id string) (*WebhookEndpoint, error) {
+	data, err := c.doRequest("GET", "/webhook-endpoints/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookEndpointDocument(data)
+}
+
+func (c *Client) CreateWebhookEndpoint(attrs map[string]interface{}) (*WebhookEndpoint, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "webhook-endpoints",
+			"attributes": attrs,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("POST", "/webhook-endpoints", strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookEndpointDocument(data)
+}
+
+func (c *Client) UpdateWebhookEndpoint(id string, attrs map[string]interface{}) (*WebhookEndpoint, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "webhook-endpoints",
+			"id":         id,
+			"attributes": attrs,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	data, err := c.doRequest("PATCH", "/webhook-endpoints/"+id, strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookEndpointDocument(data)
+}
+
+func (c *Client) DeleteWebhookEndpoint(id string) error {
+	_, err := c.doRequest("DELETE", "/webhook-endpoints/"+id, nil)
+	return err
+}
+
+func (c *Client) ListWebhookEvents(params map[string]string) ([]WebhookEvent, error) {
+	query := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	path := "/webhook-events"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	data, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var resources []JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &resources); err != nil {
+		return nil, fmt.Errorf("parsing webhook events: %w", err)
+	}
+
+	events := make([]WebhookEvent, len(resources))
+	for i, res := range resources {
+		events[i] = parseWebhookEvent(res)
+	}
+
+	return events, nil
+}
+
+func (c *Client) GetWebhookEvent(id string) (*WebhookEvent, error) {
+	data, err := c.doRequest("GET", "/webhook-events/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookEventDocument(data)
+}
+
+// RetryWebhookEvent re-delivers an event. Keygen creates a new event for the
+// retry, which is returned.
+func (c *Client) RetryWebhookEvent(id string) (*WebhookEvent, error) {
+	data, err := c.doRequest("POST", "/webhook-events/"+id+"/actions/retry", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWebhookEventDocument(data)
+}
+
+func parseWebhookEndpointDocument(data []byte) (*WebhookEndpoint, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing webhook endpoint: %w", err)
+	}
+
+	endpoint := parseWebhookEndpoint(res)
+	return &endpoint, nil
+}
+
+func parseWebhookEventDocument(data []byte) (*WebhookEvent, error) {
+	var doc JSONAPIDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	var res JSONAPIResource
+	if err := json.Unmarshal(doc.Data, &res); err != nil {
+		return nil, fmt.Errorf("parsing webhook event: %w", err)
+	}
+
+	event := parseWebhookEvent(res)
+	return &event, nil
+}
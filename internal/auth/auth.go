@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/cache"
 	"github.com/productivityenthusiast/keygen-cli/internal/config"
 )
 
@@ -26,5 +27,37 @@ func ResolveClient(cfg *config.Config) (*api.Client, error) {
 		client.Token = token.Token
 	}
 
+	if cfg.ProfileName != "" {
+		ttl, err := cfg.CacheDuration()
+		if err != nil {
+			return nil, err
+		}
+		client.Cache = cache.New(config.CacheDir(cfg.ProfileName), ttl)
+		client.Cache.Refresh = cfg.RefreshCache
+		client.Cache.Bypass = cfg.NoCache
+	}
+
 	return client, nil
 }
+
+// ResolveLicenseClient returns a client that acts as a license rather than an
+// admin, authenticating with either the license key or a license token. The
+// profile still supplies the account and base URL.
+func ResolveLicenseClient(cfg *config.Config, licenseKey, licenseToken string) (*api.Client, error) {
+	if cfg.AccountID == "" {
+		return nil, fmt.Errorf("account ID not configured (set KEYGEN_ACCOUNT_ID or --account-id)")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("base URL not configured (set KEYGEN_BASE_URL or --base-url)")
+	}
+
+	switch {
+	case licenseKey != "" && licenseToken != "":
+		return nil, fmt.Errorf("use only one of a license key and a license token")
+	case licenseKey != "":
+		return api.NewLicenseKeyClient(cfg.BaseURL, cfg.AccountID, licenseKey), nil
+	case licenseToken != "":
+		return api.NewClient(cfg.BaseURL, cfg.AccountID, licenseToken), nil
+	}
+	return nil, fmt.Errorf("no license key or license token provided")
+}
@@ -0,0 +1,278 @@
+// Package cache stores API GET responses on disk so repeated commands don't
+// refetch the same resources. Entries are served while fresh, revalidated
+// with If-None-Match once stale, and dropped when a write touches them.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store is the cache directory of one profile.
+type Store struct {
+	Dir string
+	// TTL is how long an entry is served without asking the server. With 0
+	// every read is revalidated.
+	TTL time.Duration
+	// Refresh skips cached entries for reads but stores the responses.
+	Refresh bool
+	// Bypass neither reads nor stores entries; writes still invalidate.
+	Bypass bool
+}
+
+// New returns the store in dir.
+func New(dir string, ttl time.Duration) *Store {
+	return &Store{Dir: dir, TTL: ttl}
+}
+
+// Entry is a cached response.
+type Entry struct {
+	// Path is the request path relative to the account, with its query.
+	Path   string    `json:"path"`
+	ETag   string    `json:"etag,omitempty"`
+	Stored time.Time `json:"stored"`
+	Body   []byte    `json:"body"`
+}
+
+// Key names the entry for a request. It covers the credentials so clients
+// authenticated differently never share entries.
+func Key(authorization, url string) string {
+	sum := sha256.Sum256([]byte(authorization + "\n" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the entry for key, if any, and whether it is fresh.
+func (s *Store) Get(key string) (*Entry, bool) {
+	if s.Bypass || s.Refresh {
+		return nil, false
+	}
+	e, err := s.read(s.file(key))
+	if err != nil {
+		return nil, false
+	}
+	return e, time.Since(e.Stored) < s.TTL
+}
+
+// Put stores a response. Errors are ignored: the cache is best effort.
+func (s *Store) Put(key, path, etag string, body []byte) {
+	if s.Bypass {
+		return
+	}
+	s.write(key, &Entry{Path: path, ETag: etag, Stored: time.Now(), Body: body})
+}
+
+// Touch marks a revalidated entry as fresh again.
+func (s *Store) Touch(key string, e *Entry) {
+	e.Stored = time.Now()
+	s.write(key, e)
+}
+
+// Invalidate removes the entries a write to path may have changed: those of
+// the same collection, and those whose path or response mentions the
+// written resource or any resource in the request body's relationships,
+// e.g. a user's license list after one of the licenses changed. It returns
+// how many were removed.
+func (s *Store) Invalidate(path string, body []byte) int {
+	segments := strings.Split(strings.Trim(strings.SplitN(path, "?", 2)[0], "/"), "/")
+	collection := "/" + segments[0]
+	ids := relatedIDs(body)
+	if len(segments) > 1 && segments[1] != "actions" {
+		ids = append(ids, segments[1])
+	}
+
+	removed := 0
+	s.each(func(file string, e *Entry) {
+		p := strings.SplitN(e.Path, "?", 2)[0]
+		affected := p == collection || strings.HasPrefix(p, collection+"/")
+		for _, id := range ids {
+			affected = affected || strings.Contains(p+"/", "/"+id+"/") || strings.Contains(e.Path, "="+id) ||
+				bytes.Contains(e.Body, []byte(`"`+id+`"`))
+		}
+		if affected && os.Remove(file) == nil {
+			removed++
+		}
+	})
+	return removed
+}
+
+// relatedIDs returns the IDs of the relationships in a JSON:API request
+// body, e.g. the license a new machine belongs to. To-many relationships
+// contribute every ID in their list.
+func relatedIDs(body []byte) []string {
+	var doc struct {
+		Data struct {
+			Relationships map[string]struct {
+				Data json.RawMessage `json:"data"`
+			} `json:"relationships"`
+		} `json:"data"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &doc) != nil {
+		return nil
+	}
+	var ids []string
+	type identifier struct {
+		ID string `json:"id"`
+	}
+	for _, rel := range doc.Data.Relationships {
+		var one identifier
+		if json.Unmarshal(rel.Data, &one) == nil && one.ID != "" {
+			ids = append(ids, one.ID)
+			continue
+		}
+		var many []identifier
+		if json.Unmarshal(rel.Data, &many) == nil {
+			for _, r := range many {
+				if r.ID != "" {
+					ids = append(ids, r.ID)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// Stats describes the entries in a store.
+type Stats struct {
+	Dir     string `json:"dir"`
+	TTL     string `json:"ttl"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+	Fresh   int    `json:"fresh"`
+	Stale   int    `json:"stale"`
+	// Revalidatable counts stale entries with an ETag, which cost a 304
+	// round trip rather than a full response.
+	Revalidatable int               `json:"revalidatable"`
+	Oldest        string            `json:"oldest,omitempty"`
+	Newest        string            `json:"newest,omitempty"`
+	Collections   []CollectionStats `json:"collections"`
+}
+
+// CollectionStats counts the entries of one collection, e.g. licenses.
+type CollectionStats struct {
+	Collection string `json:"collection"`
+	Entries    int    `json:"entries"`
+	Bytes      int64  `json:"bytes"`
+	Fresh      int    `json:"fresh"`
+}
+
+// Stats counts the store's entries.
+func (s *Store) Stats() Stats {
+	st := Stats{Dir: s.Dir, TTL: s.TTL.String(), Collections: []CollectionStats{}}
+	var oldest, newest time.Time
+	byCollection := map[string]*CollectionStats{}
+	s.each(func(file string, e *Entry) {
+		var size int64
+		if fi, err := os.Stat(file); err == nil {
+			size = fi.Size()
+		}
+		fresh := time.Since(e.Stored) < s.TTL
+
+		st.Entries++
+		st.Bytes += size
+		if fresh {
+			st.Fresh++
+		} else {
+			st.Stale++
+			if e.ETag != "" {
+				st.Revalidatable++
+			}
+		}
+		if oldest.IsZero() || e.Stored.Before(oldest) {
+			oldest = e.Stored
+		}
+		if e.Stored.After(newest) {
+			newest = e.Stored
+		}
+
+		name := strings.SplitN(strings.Trim(strings.SplitN(e.Path, "?", 2)[0], "/"), "/", 2)[0]
+		c, ok := byCollection[name]
+		if !ok {
+			c = &CollectionStats{Collection: name}
+			byCollection[name] = c
+		}
+		c.Entries++
+		c.Bytes += size
+		if fresh {
+			c.Fresh++
+		}
+	})
+	if st.Entries > 0 {
+		st.Oldest = oldest.UTC().Format(time.RFC3339)
+		st.Newest = newest.UTC().Format(time.RFC3339)
+	}
+	for _, c := range byCollection {
+		st.Collections = append(st.Collections, *c)
+	}
+	sort.Slice(st.Collections, func(i, j int) bool { return st.Collections[i].Collection < st.Collections[j].Collection })
+	return st
+}
+
+// Clear removes every entry and returns how many there were.
+func (s *Store) Clear() (int, error) {
+	removed := 0
+	var err error
+	s.each(func(file string, e *Entry) {
+		if rmErr := os.Remove(file); rmErr != nil {
+			err = rmErr
+			return
+		}
+		removed++
+	})
+	return removed, err
+}
+
+func (s *Store) file(key string) string {
+	return filepath.Join(s.Dir, key+".json")
+}
+
+func (s *Store) read(file string) (*Entry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// write replaces an entry atomically, so concurrent commands never read a
+// partial file.
+func (s *Store) write(key string, e *Entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), s.file(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// each calls fn for every readable entry.
+func (s *Store) each(fn func(file string, e *Entry)) {
+	files, _ := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	for _, file := range files {
+		if e, err := s.read(file); err == nil {
+			fn(file, e)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestKey(t *testing.T) {
+	base := Key("Bearer a", "https://api.keygen.sh/v1/accounts/x/licenses")
+	tests := []struct {
+		name          string
+		authorization string
+		url           string
+		same          bool
+	}{
+		{"same request", "Bearer a", "https://api.keygen.sh/v1/accounts/x/licenses", true},
+		{"other token", "Bearer b", "https://api.keygen.sh/v1/accounts/x/licenses", false},
+		{"license key auth", "License a", "https://api.keygen.sh/v1/accounts/x/licenses", false},
+		{"other query", "Bearer a", "https://api.keygen.sh/v1/accounts/x/licenses?page[number]=2", false},
+		{"other account", "Bearer a", "https://api.keygen.sh/v1/accounts/y/licenses", false},
+		// The separator keeps credentials and URL from running together
+		{"shifted boundary", "Bearer a\nhttps://api.keygen.sh/v1/accounts/x", "/licenses", false},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.authorization, tt.url) == base; got != tt.same {
+			t.Errorf("%s: same key = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+	if len(base) != 64 {
+		t.Errorf("Key is %q, want a hex SHA-256", base)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	entries := map[string]struct {
+		path string
+		body string
+	}{
+		"licenses":         {"/licenses?page[size]=100", `{"data":[{"id":"lic-1"}]}`},
+		"license":          {"/licenses/lic-1", `{"data":{"id":"lic-1"}}`},
+		"other license":    {"/licenses/lic-2", `{"data":{"id":"lic-2"}}`},
+		"license machines": {"/licenses/lic-1/machines", `{"data":[]}`},
+		"machines":         {"/machines", `{"data":[{"id":"m-1","relationships":{"license":{"data":{"id":"lic-1"}}}}]}`},
+		"machines of lic":  {"/machines?license=lic-1", `{"data":[]}`},
+		"user":             {"/users/u-1", `{"data":{"id":"u-1"}}`},
+		"user licenses":    {"/users/u-1/licenses", `{"data":[{"id":"lic-2"}]}`},
+		"products":         {"/products", `{"data":[{"id":"p-1"}]}`},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want []string // entries kept
+	}{
+		{
+			name: "update a license",
+			path: "/licenses/lic-1",
+			want: []string{"products", "user", "user licenses"},
+		},
+		{
+			name: "an action on a license",
+			path: "/licenses/lic-2/actions/renew",
+			want: []string{"machines", "machines of lic", "products", "user"},
+		},
+		{
+			name: "create a machine for a license",
+			path: "/machines",
+			body: `{"data":{"type":"machines","relationships":{"license":{"data":{"type":"licenses","id":"lic-1"}}}}}`,
+			want: []string{"other license", "products", "user", "user licenses"},
+		},
+		{
+			name: "create a product",
+			path: "/products",
+			body: `{"data":{"type":"products","attributes":{"name":"App"}}}`,
+			want: []string{"license", "license machines", "licenses", "machines", "machines of lic", "other license", "user", "user licenses"},
+		},
+		{
+			name: "update a user with a relationship list",
+			path: "/users/u-1",
+			body: `{"data":{"relationships":{"licenses":{"data":[{"id":"lic-2"}]}}}}`,
+			want: []string{"license", "license machines", "licenses", "machines", "machines of lic", "products"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(t.TempDir(), time.Hour)
+			for name, e := range entries {
+				s.Put(Key("Bearer a", name), e.path, "", []byte(e.body))
+			}
+
+			removed := s.Invalidate(tt.path, []byte(tt.body))
+
+			var kept []string
+			for name := range entries {
+				if _, ok := s.Get(Key("Bearer a", name)); ok {
+					kept = append(kept, name)
+				}
+			}
+			sort.Strings(kept)
+			if !reflect.DeepEqual(kept, tt.want) || removed != len(entries)-len(tt.want) {
+				t.Errorf("kept %v (removed %d), want %v", kept, removed, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetModes(t *testing.T) {
+	s := New(t.TempDir(), time.Hour)
+	key := Key("Bearer a", "/licenses")
+	s.Put(key, "/licenses", `"v1"`, []byte(`{}`))
+
+	if e, fresh := s.Get(key); e == nil || !fresh || e.ETag != `"v1"` {
+		t.Errorf("Get = %v, %v; want a fresh entry", e, fresh)
+	}
+
+	s.TTL = 0
+	if e, fresh := s.Get(key); e == nil || fresh {
+		t.Errorf("with TTL 0: Get = %v, %v; want an entry to revalidate", e, fresh)
+	}
+
+	s.TTL, s.Refresh = time.Hour, true
+	if e, _ := s.Get(key); e != nil {
+		t.Error("Refresh served a cached entry")
+	}
+
+	s.Refresh, s.Bypass = false, true
+	s.Put(Key("Bearer a", "/users"), "/users", "", []byte(`{}`))
+	s.Bypass = false
+	if e, _ := s.Get(Key("Bearer a", "/users")); e != nil {
+		t.Error("Bypass stored an entry")
+	}
+}
@@ -0,0 +1,365 @@
+// Package columns is the registry of selectable fields for list output. It
+// backs the global --fields, --sort and --where flags so they behave the same
+// for every list command and every output format.
+package columns
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Column is a named field of a resource. Columns cover computed values
+// (e.g. days until expiry) and control table headers; any other JSON key of
+// the resource, including nested ones like metadata.maxDevices, can be used
+// by name without being registered.
+type Column[T any] struct {
+	Name   string
+	Header string
+	Value  func(T) interface{}
+	// Cell formats the value for table and CSV output; defaults to Value.
+	Cell func(T) string
+	// JSON gives the keys the column adds to JSON output when --fields
+	// selects it, e.g. both days_remaining and expiry for "days"; defaults
+	// to the column's name and Value.
+	JSON func(T) map[string]interface{}
+}
+
+// Set is the column registry for one resource type.
+type Set[T any] struct {
+	columns  []Column[T]
+	defaults []string
+	keys     map[string]bool
+}
+
+// NewSet creates a registry whose table output shows the defaults fields.
+func NewSet[T any](defaults []string, cols ...Column[T]) *Set[T] {
+	var zero T
+	return &Set[T]{columns: cols, defaults: defaults, keys: jsonKeys(reflect.TypeOf(zero))}
+}
+
+// Options are the list flags shared by every list command.
+type Options struct {
+	Fields []string
+	Sort   []SortKey
+	Where  Expr
+}
+
+// SortKey is one field of a --sort list.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseOptions parses the --fields, --sort and --where flag values.
+func ParseOptions(fields, sortBy, where string) (Options, error) {
+	var opts Options
+	opts.Fields = splitFields(fields)
+	for _, s := range splitFields(sortBy) {
+		key := SortKey{Field: s}
+		if i := strings.LastIndex(s, ":"); i >= 0 {
+			key.Field = s[:i]
+			switch strings.ToLower(s[i+1:]) {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return opts, fmt.Errorf("invalid sort direction in %q (want asc or desc)", s)
+			}
+		}
+		opts.Sort = append(opts.Sort, key)
+	}
+	if strings.TrimSpace(where) != "" {
+		expr, err := ParseExpr(where)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --where: %w", err)
+		}
+		opts.Where = expr
+	}
+	return opts, nil
+}
+
+// Result is a list after filtering, sorting and field selection. Items are
+// the original resources unless fields were selected, in which case each is
+// a map of just those fields, plus the resource's id.
+type Result struct {
+	Items   []interface{}
+	Headers []string
+	Rows    [][]string
+}
+
+// Filter returns the items matching opts.Where, sorted by opts.Sort.
+func (s *Set[T]) Filter(items []T, opts Options) ([]T, error) {
+	records, err := s.filter(items, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, len(records))
+	for i, r := range records {
+		out[i] = r.item
+	}
+	return out, nil
+}
+
+// Apply filters, sorts and projects items according to opts.
+func (s *Set[T]) Apply(items []T, opts Options) (*Result, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = s.defaults
+	}
+	for _, f := range fields {
+		if err := s.check(f); err != nil {
+			return nil, err
+		}
+	}
+
+	records, err := s.filter(items, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Items: make([]interface{}, len(records)), Rows: make([][]string, len(records))}
+	for _, f := range fields {
+		res.Headers = append(res.Headers, s.header(f))
+	}
+	for i, r := range records {
+		row := make([]string, len(fields))
+		for j, f := range fields {
+			row[j] = r.cell(f)
+		}
+		res.Rows[i] = row
+
+		if len(opts.Fields) == 0 {
+			res.Items[i] = r.item
+			continue
+		}
+		m := make(map[string]interface{}, len(fields)+1)
+		if s.keys["id"] {
+			m["id"] = r.value("id")
+		}
+		for _, f := range fields {
+			if c := s.column(f); c != nil && c.JSON != nil {
+				for k, v := range c.JSON(r.item) {
+					m[k] = v
+				}
+				continue
+			}
+			m[f] = r.value(f)
+		}
+		res.Items[i] = m
+	}
+	return res, nil
+}
+
+func (s *Set[T]) filter(items []T, opts Options) ([]*record[T], error) {
+	for _, k := range opts.Sort {
+		if err := s.check(k.Field); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Where != nil {
+		for _, f := range opts.Where.Fields() {
+			if err := s.check(f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	records := make([]*record[T], 0, len(items))
+	for _, item := range items {
+		r := &record[T]{set: s, item: item}
+		if opts.Where != nil && !opts.Where.Eval(r.value) {
+			continue
+		}
+		records = append(records, r)
+	}
+
+	if len(opts.Sort) > 0 {
+		sort.SliceStable(records, func(i, j int) bool {
+			for _, k := range opts.Sort {
+				c := compare(records[i].value(k.Field), records[j].value(k.Field))
+				if c == 0 {
+					continue
+				}
+				if k.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+	return records, nil
+}
+
+// Names lists the registered columns followed by the resource's JSON keys.
+func (s *Set[T]) Names() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, c := range s.columns {
+		seen[c.Name] = true
+		names = append(names, c.Name)
+	}
+	var keys []string
+	for k := range s.keys {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return append(names, keys...)
+}
+
+func (s *Set[T]) column(name string) *Column[T] {
+	for i := range s.columns {
+		if s.columns[i].Name == name {
+			return &s.columns[i]
+		}
+	}
+	return nil
+}
+
+func (s *Set[T]) check(field string) error {
+	if s.column(field) != nil {
+		return nil
+	}
+	root := strings.SplitN(field, ".", 2)[0]
+	if s.keys[root] {
+		return nil
+	}
+	return fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(s.Names(), ", "))
+}
+
+func (s *Set[T]) header(field string) string {
+	if c := s.column(field); c != nil && c.Header != "" {
+		return c.Header
+	}
+	return strings.ToUpper(field)
+}
+
+// record is one item being listed, with its JSON form decoded on demand for
+// fields that aren't registered columns.
+type record[T any] struct {
+	set  *Set[T]
+	item T
+	doc  map[string]interface{}
+}
+
+func (r *record[T]) value(field string) interface{} {
+	if c := r.set.column(field); c != nil {
+		return c.Value(r.item)
+	}
+	if r.doc == nil {
+		r.doc = map[string]interface{}{}
+		if data, err := json.Marshal(r.item); err == nil {
+			_ = json.Unmarshal(data, &r.doc)
+		}
+	}
+	var v interface{} = r.doc
+	for _, part := range strings.Split(field, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[part]
+	}
+	return v
+}
+
+func (r *record[T]) cell(field string) string {
+	if c := r.set.column(field); c != nil && c.Cell != nil {
+		return c.Cell(r.item)
+	}
+	return format(r.value(field))
+}
+
+// format renders a value for a table cell.
+func format(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(x)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
+
+// compare orders two values numerically when both are numbers and as
+// case-insensitive strings otherwise. Missing values sort first.
+func compare(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+	as, bs := format(a), format(b)
+	af, aErr := strconv.ParseFloat(as, 64)
+	bf, bErr := strconv.ParseFloat(bs, 64)
+	if aErr == nil && bErr == nil {
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(as), strings.ToLower(bs))
+}
+
+// jsonKeys collects the JSON names of a struct's fields, including those of
+// embedded structs.
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	if t == nil {
+		return keys
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			for k := range jsonKeys(f.Type) {
+				keys[k] = true
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		keys[name] = true
+	}
+	return keys
+}
+
+func splitFields(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
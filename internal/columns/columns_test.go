@@ -0,0 +1,103 @@
+package columns
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Days   int    `json:"days_remaining"`
+	Expiry string `json:"expiry"`
+}
+
+var items = NewSet([]string{"key", "days"},
+	Column[item]{
+		Name:   "days",
+		Header: "DAYS",
+		Value:  func(i item) interface{} { return i.Days },
+		JSON: func(i item) map[string]interface{} {
+			return map[string]interface{}{"days_remaining": i.Days, "expiry": i.Expiry}
+		},
+	},
+)
+
+var sample = []item{
+	{"1", "KEY-B", 30, "2026-02-01"},
+	{"2", "KEY-A", 5, "2026-01-05"},
+	{"3", "KEY-C", 90, "2026-04-01"},
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, fields, sort, where string
+		wantRows                  [][]string
+		wantItems                 []interface{}
+	}{
+		{
+			name:      "defaults keep the resources",
+			wantRows:  [][]string{{"KEY-B", "30"}, {"KEY-A", "5"}, {"KEY-C", "90"}},
+			wantItems: []interface{}{sample[0], sample[1], sample[2]},
+		},
+		{
+			name:      "sort and filter",
+			sort:      "days:desc",
+			where:     "days<60",
+			wantRows:  [][]string{{"KEY-B", "30"}, {"KEY-A", "5"}},
+			wantItems: []interface{}{sample[0], sample[1]},
+		},
+		{
+			name:     "selected fields keep the id and the column's JSON keys",
+			fields:   "key,days",
+			sort:     "key",
+			wantRows: [][]string{{"KEY-A", "5"}, {"KEY-B", "30"}, {"KEY-C", "90"}},
+			wantItems: []interface{}{
+				map[string]interface{}{"id": "2", "key": "KEY-A", "days_remaining": 5, "expiry": "2026-01-05"},
+				map[string]interface{}{"id": "1", "key": "KEY-B", "days_remaining": 30, "expiry": "2026-02-01"},
+				map[string]interface{}{"id": "3", "key": "KEY-C", "days_remaining": 90, "expiry": "2026-04-01"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ParseOptions(tt.fields, tt.sort, tt.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := items.Apply(sample, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res.Rows, tt.wantRows) {
+				t.Errorf("rows = %v, want %v", res.Rows, tt.wantRows)
+			}
+			if !reflect.DeepEqual(res.Items, tt.wantItems) {
+				t.Errorf("items = %#v, want %#v", res.Items, tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestApplyUnknownField(t *testing.T) {
+	for _, opts := range []struct{ fields, sort, where string }{
+		{"nope", "", ""},
+		{"", "nope", ""},
+		{"", "", "nope=1"},
+	} {
+		o, err := ParseOptions(opts.fields, opts.sort, opts.where)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := items.Apply(sample, o); err == nil || !strings.Contains(err.Error(), `unknown field "nope"`) {
+			t.Errorf("%+v: error = %v, want unknown field", opts, err)
+		}
+	}
+}
+
+func TestParseOptionsSortDirection(t *testing.T) {
+	if _, err := ParseOptions("", "days:sideways", ""); err == nil {
+		t.Error("accepted an invalid sort direction")
+	}
+}
@@ -0,0 +1,225 @@
+package columns
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Expr is a parsed --where expression, e.g. `status=ACTIVE && days<30`.
+//
+// Comparisons are field OP value with OP one of = == != < <= > >= and ~
+// (contains). Values compare as numbers when both sides are numeric and as
+// case-insensitive strings otherwise; ISO dates therefore order correctly.
+// Comparisons combine with && and ||, group with parentheses, and values
+// containing spaces or operators can be quoted.
+type Expr interface {
+	Eval(value func(field string) interface{}) bool
+	Fields() []string
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e andExpr) Eval(v func(string) interface{}) bool { return e.left.Eval(v) && e.right.Eval(v) }
+func (e andExpr) Fields() []string                     { return append(e.left.Fields(), e.right.Fields()...) }
+
+type orExpr struct{ left, right Expr }
+
+func (e orExpr) Eval(v func(string) interface{}) bool { return e.left.Eval(v) || e.right.Eval(v) }
+func (e orExpr) Fields() []string                     { return append(e.left.Fields(), e.right.Fields()...) }
+
+type cmpExpr struct {
+	field string
+	op    string
+	value string
+}
+
+func (e cmpExpr) Fields() []string { return []string{e.field} }
+
+func (e cmpExpr) Eval(value func(string) interface{}) bool {
+	v := value(e.field)
+	if v == nil {
+		// A missing field only equals the empty string
+		switch e.op {
+		case "=", "==":
+			return e.value == ""
+		case "!=":
+			return e.value != ""
+		}
+		return false
+	}
+
+	actual := format(v)
+	if e.op == "~" {
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(e.value))
+	}
+
+	c := compare(actual, e.value)
+	switch e.op {
+	case "=", "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// ParseExpr parses a --where expression.
+func ParseExpr(s string) (Expr, error) {
+	p := &exprParser{}
+	if err := p.tokenize(s); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+type token struct {
+	kind string // "word", "op", "and", "or", "(", ")"
+	text string
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+var comparisonOps = []string{"==", "!=", "<=", ">=", "=", "<", ">", "~"}
+
+func (p *exprParser) tokenize(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case strings.HasPrefix(s[i:], "&&"):
+			p.tokens = append(p.tokens, token{"and", "&&"})
+			i += 2
+		case strings.HasPrefix(s[i:], "||"):
+			p.tokens = append(p.tokens, token{"or", "||"})
+			i += 2
+		case c == '(' || c == ')':
+			p.tokens = append(p.tokens, token{string(c), string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("unterminated quote")
+			}
+			p.tokens = append(p.tokens, token{"word", s[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			if op := matchOp(s[i:]); op != "" {
+				p.tokens = append(p.tokens, token{"op", op})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(s) && !unicode.IsSpace(rune(s[i])) && !strings.ContainsRune("()\"'", rune(s[i])) &&
+				matchOp(s[i:]) == "" && !strings.HasPrefix(s[i:], "&&") && !strings.HasPrefix(s[i:], "||") {
+				i++
+			}
+			p.tokens = append(p.tokens, token{"word", s[start:i]})
+		}
+	}
+	return nil
+}
+
+func matchOp(s string) string {
+	for _, op := range comparisonOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *exprParser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.kind == "or"; t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t != nil && t.kind == "and"; t = p.peek() {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseTerm() (Expr, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if t.kind == "(" {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return expr, nil
+	}
+	if t.kind != "word" {
+		return nil, fmt.Errorf("expected a field name, got %q", t.text)
+	}
+	field := t.text
+	p.pos++
+
+	op := p.peek()
+	if op == nil || op.kind != "op" {
+		return nil, fmt.Errorf("expected a comparison after %q", field)
+	}
+	p.pos++
+
+	value := ""
+	if v := p.peek(); v != nil && v.kind == "word" {
+		value = v.text
+		p.pos++
+	}
+	return cmpExpr{field: field, op: op.text, value: value}, nil
+}
@@ -0,0 +1,85 @@
+package columns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpr(t *testing.T) {
+	record := map[string]interface{}{
+		"status":   "ACTIVE",
+		"days":     12.0,
+		"name":     "Front Desk",
+		"expiry":   "2026-03-01T00:00:00Z",
+		"metadata": map[string]interface{}{"tier": "gold"},
+		"empty":    "",
+	}
+	value := func(field string) interface{} {
+		if field == "metadata.tier" {
+			return "gold"
+		}
+		return record[field]
+	}
+
+	tests := []struct {
+		expr   string
+		want   bool
+		fields []string
+	}{
+		{"status=ACTIVE", true, []string{"status"}},
+		{"status==active", true, []string{"status"}},
+		{"status!=ACTIVE", false, []string{"status"}},
+		{"days<30", true, []string{"days"}},
+		{"days<=12", true, []string{"days"}},
+		{"days>12", false, []string{"days"}},
+		{"days>=100", false, []string{"days"}},
+		{"days<9", false, []string{"days"}}, // numeric, not "12" < "9"
+		{"name~desk", true, []string{"name"}},
+		{"name~printer", false, []string{"name"}},
+		{`name="Front Desk"`, true, []string{"name"}},
+		{`name='front desk'`, true, []string{"name"}},
+		{"expiry<2026-06-01", true, []string{"expiry"}},
+		{"metadata.tier=gold", true, []string{"metadata.tier"}},
+		{"status=ACTIVE && days<30", true, []string{"status", "days"}},
+		{"status=ACTIVE && days>30", false, []string{"status", "days"}},
+		{"status=EXPIRED || days<30", true, []string{"status", "days"}},
+		{"status=EXPIRED || days>30 && name~desk", false, []string{"status", "days", "name"}},
+		{"(status=EXPIRED || days<30) && name~desk", true, []string{"status", "days", "name"}},
+		{"status=EXPIRED || (days<30 && name~printer)", false, []string{"status", "days", "name"}},
+		// Missing and empty fields only equal the empty string
+		{"missing=", true, []string{"missing"}},
+		{"missing!=", false, []string{"missing"}},
+		{"missing<5", false, []string{"missing"}},
+		{"empty=", true, []string{"empty"}},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.expr)
+		if err != nil {
+			t.Errorf("ParseExpr(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := e.Eval(value); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+		if got := e.Fields(); !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("%q fields = %v, want %v", tt.expr, got, tt.fields)
+		}
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"status",
+		"=ACTIVE",
+		"status=ACTIVE &&",
+		"(status=ACTIVE",
+		"status=ACTIVE)",
+		`name="Front Desk`,
+		"status=ACTIVE days<30",
+	} {
+		if _, err := ParseExpr(expr); err == nil {
+			t.Errorf("ParseExpr(%q) succeeded, want an error", expr)
+		}
+	}
+}
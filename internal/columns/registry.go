@@ -0,0 +1,93 @@
+package columns
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+)
+
+// Licenses is the column registry for licenses.
+var Licenses = NewSet([]string{"id", "key", "name", "status", "expiry", "owner_id"},
+	Column[api.License]{Name: "status", Header: "STATUS", Value: func(l api.License) interface{} { return strings.ToUpper(l.Status) }},
+	Column[api.License]{Name: "days", Header: "DAYS", Value: func(l api.License) interface{} { return DaysUntil(l.Expiry) }},
+	Column[api.License]{
+		Name:   "usage",
+		Header: "USAGE",
+		Value:  func(l api.License) interface{} { return l.Uses },
+		Cell: func(l api.License) string {
+			if l.MaxUses > 0 {
+				return fmt.Sprintf("%d/%d", l.Uses, l.MaxUses)
+			}
+			return fmt.Sprintf("%d", l.Uses)
+		},
+	},
+)
+
+// Machines is the column registry for machines.
+var Machines = NewSet([]string{"id", "fingerprint", "name", "platform", "heartbeat", "last_heartbeat", "license_id"},
+	Column[api.Machine]{Name: "heartbeat", Header: "HEARTBEAT", Value: func(m api.Machine) interface{} { return strings.ToUpper(m.HeartbeatStatus) }},
+	Column[api.Machine]{Name: "component_count", Header: "COMPONENTS", Value: func(m api.Machine) interface{} { return len(m.Components) }},
+)
+
+// Components is the column registry for components.
+var Components = NewSet[api.Component]([]string{"id", "fingerprint", "name", "machine_id", "created"})
+
+// Users is the column registry for users.
+var Users = NewSet([]string{"id", "email", "first_name", "last_name", "role", "status"},
+	Column[api.User]{Name: "status", Header: "STATUS", Value: func(u api.User) interface{} { return strings.ToUpper(u.Status) }},
+	Column[api.User]{Name: "full_name", Header: "NAME", Value: func(u api.User) interface{} { return strings.TrimSpace(u.FirstName + " " + u.LastName) }},
+)
+
+// Tokens is the column registry for API tokens.
+var Tokens = NewSet([]string{"id", "kind", "bearer_type", "bearer_id", "expiry", "created"},
+	Column[api.Token]{Name: "days", Header: "DAYS", Value: func(t api.Token) interface{} { return DaysUntil(t.Expiry) }},
+)
+
+// Releases is the column registry for releases.
+var Releases = NewSet([]string{"id", "version", "channel", "status", "name", "product_id", "created"},
+	Column[api.Release]{Name: "status", Header: "STATUS", Value: func(r api.Release) interface{} { return strings.ToUpper(r.Status) }},
+)
+
+// Artifacts is the column registry for release artifacts.
+var Artifacts = NewSet([]string{"id", "filename", "platform", "arch", "filetype", "size", "status", "release_id"},
+	Column[api.Artifact]{Name: "status", Header: "STATUS", Value: func(a api.Artifact) interface{} { return strings.ToUpper(a.Status) }},
+	Column[api.Artifact]{Name: "size", Header: "SIZE", Value: func(a api.Artifact) interface{} { return a.Filesize }},
+)
+
+// Processes is the column registry for machine processes.
+var Processes = NewSet([]string{"id", "pid", "status", "last_heartbeat", "machine_id", "license_id"},
+	Column[api.Process]{Name: "status", Header: "STATUS", Value: func(p api.Process) interface{} { return strings.ToUpper(p.Status) }},
+)
+
+// WebhookEndpoints is the column registry for webhook endpoints.
+var WebhookEndpoints = NewSet([]string{"id", "url", "subscriptions", "algorithm", "created"},
+	Column[api.WebhookEndpoint]{
+		Name:   "subscriptions",
+		Header: "SUBSCRIPTIONS",
+		Value:  func(e api.WebhookEndpoint) interface{} { return e.Subscriptions },
+		Cell:   func(e api.WebhookEndpoint) string { return strings.Join(e.Subscriptions, ",") },
+	},
+	Column[api.WebhookEndpoint]{Name: "algorithm", Header: "ALGORITHM", Value: func(e api.WebhookEndpoint) interface{} { return e.SignatureAlgorithm }},
+)
+
+// WebhookEvents is the column registry for webhook events.
+var WebhookEvents = NewSet([]string{"id", "event", "status", "response", "endpoint", "created"},
+	Column[api.WebhookEvent]{Name: "status", Header: "STATUS", Value: func(e api.WebhookEvent) interface{} { return strings.ToUpper(e.Status) }},
+	Column[api.WebhookEvent]{Name: "response", Header: "RESPONSE", Value: func(e api.WebhookEvent) interface{} { return e.LastResponseCode }},
+)
+
+// DaysUntil returns the whole days until an RFC 3339 timestamp, floored at
+// zero, or nil when there is no timestamp.
+func DaysUntil(ts string) interface{} {
+	if ts == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return nil
+	}
+	return int(math.Max(0, time.Until(t).Hours()/24))
+}
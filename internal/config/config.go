@@ -13,15 +13,24 @@ import (
 
 // Config holds credentials for a single profile.
 type Config struct {
-	AccountID   string `json:"account_id"`
-	BaseURL     string `json:"base_url"`
-	Token       string `json:"token"`
-	Email       string `json:"email,omitempty"`
-	Password    string `json:"password,omitempty"`
-	TokenExp    string `json:"token_expiry,omitempty"`
-	ProfileName string `json:"-"` // runtime-only, not persisted inside the profile
+	AccountID        string `json:"account_id"`
+	BaseURL          string `json:"base_url"`
+	Token            string `json:"token"`
+	Email            string `json:"email,omitempty"`
+	Password         string `json:"password,omitempty"`
+	TokenExp         string `json:"token_expiry,omitempty"`
+	PublicKey        string `json:"public_key,omitempty"`
+	AllowDestructive bool   `json:"allow_destructive,omitempty"` // enables destructive MCP tools
+	CacheTTL         string `json:"cache_ttl,omitempty"`         // how long GET responses are served from the cache, e.g. "5m"
+	ProfileName      string `json:"-"`                           // runtime-only, not persisted inside the profile
+	NoCache          bool   `json:"-"`                           // runtime-only: don't read or store cached responses
+	RefreshCache     bool   `json:"-"`                           // runtime-only: refetch instead of reading cached responses
 }
 
+// DefaultCacheTTL is used when a profile doesn't set cache_ttl: every cached
+// read is revalidated, so serving reads without asking the server is opt-in.
+const DefaultCacheTTL = 0
+
 // ProfilesConfig is the top-level structure stored in profiles.json.
 type ProfilesConfig struct {
 	DefaultProfile string             `json:"default_profile"`
@@ -33,6 +42,18 @@ func configDir() string {
 	return filepath.Join(home, ".keygen-cli")
 }
 
+// CacheDir returns the directory of a profile's response cache. With an
+// empty name it returns the directory holding every profile's cache.
+func CacheDir(profile string) string {
+	return filepath.Join(configDir(), "cache", profile)
+}
+
+// SyncFile returns where 'keygen sync' keeps the profile's offline copy of
+// the account.
+func SyncFile(profile string) string {
+	return filepath.Join(configDir(), "sync", profile+".json.gz")
+}
+
 func profilesPath() string {
 	return filepath.Join(configDir(), "profiles.json")
 }
@@ -130,11 +151,17 @@ func applyEnvOverrides(cfg *Config) {
 	} else if v := os.Getenv("KEYGEN_API_TOKEN"); v != "" {
 		cfg.Token = v
 	}
+	if v := os.Getenv("KEYGEN_PUBLIC_KEY"); v != "" {
+		cfg.PublicKey = v
+	}
 	if v := os.Getenv("KEYGEN_EMAIL"); v != "" {
 		cfg.Email = v
 	} else if v := os.Getenv("KEYGEN_ACCOUNT_EMAIL"); v != "" {
 		cfg.Email = v
 	}
+	if v := os.Getenv("KEYGEN_CACHE_TTL"); v != "" {
+		cfg.CacheTTL = v
+	}
 	if v := os.Getenv("KEYGEN_PASSWORD"); v != "" {
 		cfg.Password = v
 	} else if v := os.Getenv("KEYGEN_ACCOUNT_PASSWORD"); v != "" {
@@ -268,6 +295,21 @@ func (c *Config) IsTokenExpired() bool {
 	return time.Now().After(t)
 }
 
+// CacheDuration parses CacheTTL, e.g. "5m" or "0" to revalidate every read.
+func (c *Config) CacheDuration() (time.Duration, error) {
+	if c.CacheTTL == "" {
+		return DefaultCacheTTL, nil
+	}
+	if c.CacheTTL == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.CacheTTL)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid cache TTL %q (want a duration like 30s or 5m)", c.CacheTTL)
+	}
+	return d, nil
+}
+
 func (c *Config) Validate() error {
 	if c.AccountID == "" {
 		return fmt.Errorf("account ID not configured (set KEYGEN_ACCOUNT_ID or run keygen login --profile %s)", c.ProfileName)
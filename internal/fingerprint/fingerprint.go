@@ -0,0 +1,181 @@
+// Package fingerprint derives machine and component fingerprints from the
+// local host, mirroring what the client SDK computes so support engineers can
+// look devices up before activating or checking them.
+package fingerprint
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Schemes lists the supported hashing schemes.
+var Schemes = []string{"hmac-sha256", "sha256", "sha512", "raw"}
+
+// Source kinds.
+const (
+	KindMachineID   = "machine-id"
+	KindProductUUID = "product-uuid"
+	KindMAC         = "mac"
+	KindDisk        = "disk"
+)
+
+// Source is one hardware identifier read from the host.
+type Source struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// Collect reads the identifiers available on this host. Sources that can't
+// be read (commonly the DMI UUID without root) are skipped and reported in
+// the returned notes.
+func Collect() ([]Source, []string, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, fmt.Errorf("local fingerprinting is only supported on Linux (running on %s)", runtime.GOOS)
+	}
+
+	var sources []Source
+	var notes []string
+
+	if v, err := readFirst("/etc/machine-id", "/var/lib/dbus/machine-id"); err == nil {
+		sources = append(sources, Source{Kind: KindMachineID, Name: "machine-id", Value: v})
+	} else {
+		notes = append(notes, "machine-id: "+err.Error())
+	}
+
+	if v, err := readFirst("/sys/class/dmi/id/product_uuid"); err == nil {
+		sources = append(sources, Source{Kind: KindProductUUID, Name: "product_uuid", Value: strings.ToLower(v)})
+	} else {
+		notes = append(notes, "product_uuid: "+err.Error())
+	}
+
+	macs, err := macAddresses()
+	if err != nil {
+		notes = append(notes, "mac addresses: "+err.Error())
+	}
+	sources = append(sources, macs...)
+
+	sources = append(sources, diskSerials()...)
+
+	return sources, notes, nil
+}
+
+// Hash derives a fingerprint from a raw identifier.
+//
+//	hmac-sha256  hex HMAC-SHA256 keyed by the identifier over the salt
+//	             (the "protected ID" scheme used by the Keygen SDKs)
+//	sha256       hex SHA-256 of salt + identifier
+//	sha512       hex SHA-512 of salt + identifier
+//	raw          the identifier itself
+func Hash(value, scheme, salt string) (string, error) {
+	switch scheme {
+	case "hmac-sha256":
+		mac := hmac.New(sha256.New, []byte(value))
+		mac.Write([]byte(salt))
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	case "sha256":
+		sum := sha256.Sum256([]byte(salt + value))
+		return hex.EncodeToString(sum[:]), nil
+	case "sha512":
+		sum := sha512.Sum512([]byte(salt + value))
+		return hex.EncodeToString(sum[:]), nil
+	case "raw":
+		return value, nil
+	}
+	return "", fmt.Errorf("unknown hashing scheme %q (use %s)", scheme, strings.Join(Schemes, ", "))
+}
+
+// MachineSource picks the identifier for the machine fingerprint: the
+// requested kind, or machine-id falling back to the DMI product UUID.
+func MachineSource(sources []Source, kind string) (*Source, error) {
+	order := []string{KindMachineID, KindProductUUID}
+	if kind != "" {
+		order = []string{kind}
+	}
+	for _, k := range order {
+		for i := range sources {
+			if sources[i].Kind == k {
+				return &sources[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no readable %s on this host", strings.Join(order, " or "))
+}
+
+// ComponentSources returns the identifiers used for component fingerprints.
+func ComponentSources(sources []Source) []Source {
+	var out []Source
+	for _, s := range sources {
+		if s.Kind == KindMAC || s.Kind == KindDisk || s.Kind == KindProductUUID {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func readFirst(paths ...string) (string, error) {
+	var lastErr error
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if v := strings.TrimSpace(string(data)); v != "" {
+			return v, nil
+		}
+		lastErr = fmt.Errorf("%s is empty", p)
+	}
+	return "", lastErr
+}
+
+// macAddresses returns the hardware addresses of physical-looking network
+// interfaces, skipping loopback and interfaces without an address.
+func macAddresses() ([]Source, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Source
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		// Interfaces without a backing device (bridges, veths, tunnels) are virtual
+		if _, err := os.Stat(filepath.Join("/sys/class/net", iface.Name, "device")); err != nil {
+			continue
+		}
+		out = append(out, Source{Kind: KindMAC, Name: iface.Name, Value: strings.ToLower(iface.HardwareAddr.String())})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// diskSerials returns serial numbers of block devices that expose one.
+func diskSerials() []Source {
+	devices, _ := filepath.Glob("/sys/block/*")
+	var out []Source
+	for _, dev := range devices {
+		name := filepath.Base(dev)
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") || strings.HasPrefix(name, "zram") {
+			continue
+		}
+		serial, err := readFirst(filepath.Join(dev, "device", "serial"), filepath.Join(dev, "serial"))
+		if err != nil {
+			continue
+		}
+		out = append(out, Source{Kind: KindDisk, Name: name, Value: serial})
+	}
+	return out
+}
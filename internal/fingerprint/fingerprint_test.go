@@ -0,0 +1,161 @@
+package fingerprint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		scheme  string
+		salt    string
+		want    string
+		wantErr string
+	}{
+		{
+			// RFC 4231 test case 2: the identifier is the key, the salt the message
+			name:   "hmac-sha256 salted",
+			value:  "Jefe",
+			scheme: "hmac-sha256",
+			salt:   "what do ya want for nothing?",
+			want:   "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		},
+		{
+			name:   "hmac-sha256 unsalted",
+			value:  "machine-1",
+			scheme: "hmac-sha256",
+			want:   "da3db534f6edafe3de40722b9670c50abc3c0fe239e3761b6af9808018120ca1",
+		},
+		{
+			name:   "sha256 unsalted",
+			value:  "abc",
+			scheme: "sha256",
+			want:   "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:   "sha256 salt comes first",
+			value:  "bc",
+			scheme: "sha256",
+			salt:   "a",
+			want:   "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			name:   "sha512 salted",
+			value:  "c",
+			scheme: "sha512",
+			salt:   "ab",
+			want:   "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		},
+		{
+			name:   "raw ignores the salt",
+			value:  "machine-1",
+			scheme: "raw",
+			salt:   "ignored",
+			want:   "machine-1",
+		},
+		{
+			name:    "unknown scheme",
+			value:   "machine-1",
+			scheme:  "md5",
+			wantErr: `unknown hashing scheme "md5"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Hash(tt.value, tt.scheme, tt.salt)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Hash error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Hash = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMachineSource(t *testing.T) {
+	machineID := Source{Kind: KindMachineID, Name: "machine-id", Value: "m1"}
+	uuid := Source{Kind: KindProductUUID, Name: "product_uuid", Value: "u1"}
+	mac := Source{Kind: KindMAC, Name: "eth0", Value: "aa:bb:cc:dd:ee:ff"}
+
+	tests := []struct {
+		name    string
+		sources []Source
+		kind    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "machine-id first",
+			sources: []Source{mac, uuid, machineID},
+			want:    "m1",
+		},
+		{
+			name:    "falls back to the product UUID",
+			sources: []Source{mac, uuid},
+			want:    "u1",
+		},
+		{
+			name:    "requested kind",
+			sources: []Source{machineID, mac},
+			kind:    KindMAC,
+			want:    "aa:bb:cc:dd:ee:ff",
+		},
+		{
+			name:    "nothing readable",
+			sources: []Source{mac},
+			wantErr: "no readable machine-id or product-uuid on this host",
+		},
+		{
+			name:    "requested kind missing",
+			sources: []Source{machineID},
+			kind:    KindDisk,
+			wantErr: "no readable disk on this host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MachineSource(tt.sources, tt.kind)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("MachineSource error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MachineSource: %v", err)
+			}
+			if got.Value != tt.want {
+				t.Errorf("MachineSource = %+v, want value %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComponentSources(t *testing.T) {
+	sources := []Source{
+		{Kind: KindMachineID, Name: "machine-id"},
+		{Kind: KindMAC, Name: "eth0"},
+		{Kind: KindProductUUID, Name: "product_uuid"},
+		{Kind: KindDisk, Name: "sda"},
+	}
+	var got []string
+	for _, s := range ComponentSources(sources) {
+		got = append(got, s.Name)
+	}
+	if want := []string{"eth0", "product_uuid", "sda"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ComponentSources = %v, want %v", got, want)
+	}
+	if got := ComponentSources(sources[:1]); got != nil {
+		t.Errorf("ComponentSources without hardware = %v, want none", got)
+	}
+}
@@ -0,0 +1,188 @@
+// Package gateway is a small authenticated HTTP/JSON front for read-mostly
+// CLI operations, so internal tools can query license state without holding
+// the Keygen admin token. It adds API-key auth, a route allow-list,
+// response caching and Prometheus metrics around plain handler functions.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Route is one endpoint of the gateway.
+type Route struct {
+	// Name identifies the route in the allow-list and in metrics.
+	Name string
+	// Pattern is an http.ServeMux pattern, e.g. "GET /v1/licenses/{id}/status".
+	Pattern string
+	Handler func(r *http.Request) (interface{}, error)
+}
+
+// StatusError is a handler error with the HTTP status to report. Other
+// handler errors are reported as 502 Bad Gateway.
+type StatusError struct {
+	Status int
+	Err    error
+}
+
+func (e *StatusError) Error() string { return e.Err.Error() }
+
+func (e *StatusError) Unwrap() error { return e.Err }
+
+// Options configure a Gateway.
+type Options struct {
+	// APIKeys are accepted as "Authorization: Bearer <key>" or "X-API-Key".
+	// With no keys, requests are not authenticated.
+	APIKeys []string
+	// Allow lists the route names to serve; empty serves every route.
+	Allow []string
+	// CacheTTL is how long successful responses are reused; 0 disables caching.
+	CacheTTL time.Duration
+}
+
+// Gateway is an http.Handler serving routes under /v1 plus /healthz and
+// /metrics, which are not authenticated.
+type Gateway struct {
+	mux     *http.ServeMux
+	keys    [][]byte
+	cache   *cache
+	metrics *metrics
+	routes  []string
+}
+
+// New creates a gateway serving the allowed routes.
+func New(routes []Route, opts Options) (*Gateway, error) {
+	known := map[string]bool{}
+	for _, r := range routes {
+		known[r.Name] = true
+	}
+	allowed := map[string]bool{}
+	for _, name := range opts.Allow {
+		if !known[name] {
+			names := make([]string, 0, len(known))
+			for n := range known {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown route %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		allowed[name] = true
+	}
+
+	g := &Gateway{mux: http.NewServeMux(), metrics: newMetrics()}
+	for _, k := range opts.APIKeys {
+		g.keys = append(g.keys, []byte(k))
+	}
+	if opts.CacheTTL > 0 {
+		g.cache = newCache(opts.CacheTTL)
+	}
+
+	for _, r := range routes {
+		if len(allowed) > 0 && !allowed[r.Name] {
+			continue
+		}
+		g.mux.Handle(r.Pattern, g.wrap(r))
+		g.routes = append(g.routes, r.Name)
+	}
+	g.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"ok": true, "data": map[string]interface{}{"routes": g.routes}})
+	})
+	g.mux.Handle("GET /metrics", g.metrics)
+	return g, nil
+}
+
+// Routes returns the names of the routes being served.
+func (g *Gateway) Routes() []string {
+	return g.routes
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) wrap(route Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := g.serve(route, w, r)
+		g.metrics.observe(route.Name, status, time.Since(start))
+	})
+}
+
+// serve handles one request and returns the status code written.
+func (g *Gateway) serve(route Route, w http.ResponseWriter, r *http.Request) int {
+	if !g.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="keygen"`)
+		return writeJSON(w, http.StatusUnauthorized, map[string]interface{}{"ok": false, "error": "missing or invalid API key"})
+	}
+
+	key := route.Name + " " + r.URL.RequestURI()
+	if g.cache != nil {
+		if body, ok := g.cache.get(key); ok {
+			g.metrics.cacheHit(route.Name)
+			w.Header().Set("X-Cache", "HIT")
+			return writeBody(w, http.StatusOK, body)
+		}
+		g.metrics.cacheMiss(route.Name)
+		w.Header().Set("X-Cache", "MISS")
+	}
+
+	data, err := route.Handler(r)
+	if err != nil {
+		status := http.StatusBadGateway
+		var se *StatusError
+		if errors.As(err, &se) {
+			status = se.Status
+		}
+		if status >= 500 {
+			g.metrics.upstreamError(route.Name)
+		}
+		return writeJSON(w, status, map[string]interface{}{"ok": false, "error": err.Error()})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"ok": true, "data": data})
+	if err != nil {
+		return writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"ok": false, "error": err.Error()})
+	}
+	if g.cache != nil {
+		g.cache.set(key, body)
+	}
+	return writeBody(w, http.StatusOK, body)
+}
+
+func (g *Gateway) authorized(r *http.Request) bool {
+	if len(g.keys) == 0 {
+		return true
+	}
+	got := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); got == "" && strings.HasPrefix(auth, "Bearer ") {
+		got = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if got == "" {
+		return false
+	}
+	ok := false
+	for _, k := range g.keys {
+		if subtle.ConstantTimeCompare([]byte(got), k) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) int {
+	body, _ := json.Marshal(v)
+	return writeBody(w, status, body)
+}
+
+func writeBody(w http.ResponseWriter, status int, body []byte) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+	return status
+}
@@ -0,0 +1,189 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testGateway serves an "echo" route counting its calls, a "missing" route
+// failing with a 404 and a "broken" route failing upstream.
+func testGateway(t *testing.T, opts Options) (*Gateway, *int) {
+	calls := 0
+	routes := []Route{
+		{Name: "echo", Pattern: "GET /v1/echo/{id}", Handler: func(r *http.Request) (interface{}, error) {
+			calls++
+			return map[string]interface{}{"id": r.PathValue("id"), "call": calls}, nil
+		}},
+		{Name: "missing", Pattern: "GET /v1/missing", Handler: func(r *http.Request) (interface{}, error) {
+			return nil, &StatusError{Status: http.StatusNotFound, Err: errors.New("no such license")}
+		}},
+		{Name: "broken", Pattern: "GET /v1/broken", Handler: func(r *http.Request) (interface{}, error) {
+			return nil, errors.New("keygen unreachable")
+		}},
+	}
+	g, err := New(routes, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, &calls
+}
+
+func get(g *Gateway, path string, header ...string) (int, http.Header, string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+	body, _ := io.ReadAll(rec.Result().Body)
+	return rec.Code, rec.Header(), string(body)
+}
+
+func TestAuth(t *testing.T) {
+	g, calls := testGateway(t, Options{APIKeys: []string{"s3cret", "other"}})
+
+	tests := []struct {
+		name   string
+		path   string
+		header []string
+		want   int
+	}{
+		{"no key", "/v1/echo/1", nil, http.StatusUnauthorized},
+		{"wrong key", "/v1/echo/1", []string{"X-API-Key", "nope"}, http.StatusUnauthorized},
+		{"wrong bearer", "/v1/echo/1", []string{"Authorization", "Bearer nope"}, http.StatusUnauthorized},
+		{"not a bearer", "/v1/echo/1", []string{"Authorization", "Basic s3cret"}, http.StatusUnauthorized},
+		{"X-API-Key", "/v1/echo/1", []string{"X-API-Key", "s3cret"}, http.StatusOK},
+		{"bearer", "/v1/echo/1", []string{"Authorization", "Bearer other"}, http.StatusOK},
+		{"healthz is open", "/healthz", nil, http.StatusOK},
+		{"metrics is open", "/metrics", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, header, body := get(g, tt.path, tt.header...)
+			if code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", code, tt.want, body)
+			}
+			if code == http.StatusUnauthorized && header.Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate")
+			}
+		})
+	}
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want only for the 2 authorized requests", *calls)
+	}
+}
+
+func TestAllowList(t *testing.T) {
+	g, _ := testGateway(t, Options{Allow: []string{"echo"}})
+	if got := g.Routes(); len(got) != 1 || got[0] != "echo" {
+		t.Errorf("Routes() = %v, want [echo]", got)
+	}
+	if code, _, _ := get(g, "/v1/echo/1"); code != http.StatusOK {
+		t.Errorf("allowed route: status %d", code)
+	}
+	if code, _, _ := get(g, "/v1/missing"); code != http.StatusNotFound {
+		t.Errorf("route outside the allow-list: status %d, want 404", code)
+	}
+
+	if _, err := New(nil, Options{Allow: []string{"nope"}}); err == nil || !strings.Contains(err.Error(), `unknown route "nope"`) {
+		t.Errorf("unknown allowed route: error %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	g, calls := testGateway(t, Options{CacheTTL: time.Minute})
+
+	for i, want := range []string{"MISS", "HIT", "HIT"} {
+		code, header, body := get(g, "/v1/echo/1")
+		if code != http.StatusOK || header.Get("X-Cache") != want {
+			t.Errorf("request %d: status %d, X-Cache %q; want 200, %s", i+1, code, header.Get("X-Cache"), want)
+		}
+		if !strings.Contains(body, `"call":1`) {
+			t.Errorf("request %d: body %s, want the first response", i+1, body)
+		}
+	}
+	if _, header, _ := get(g, "/v1/echo/2"); header.Get("X-Cache") != "MISS" {
+		t.Errorf("other path: X-Cache %q, want MISS", header.Get("X-Cache"))
+	}
+	if *calls != 2 {
+		t.Errorf("handler called %d times, want 2", *calls)
+	}
+
+	// Errors are not cached
+	get(g, "/v1/missing")
+	if _, header, _ := get(g, "/v1/missing"); header.Get("X-Cache") != "MISS" {
+		t.Errorf("error response: X-Cache %q on the second request, want MISS", header.Get("X-Cache"))
+	}
+
+	// Expired entries are fetched again
+	for k, e := range g.cache.entries {
+		e.expires = time.Now().Add(-time.Second)
+		g.cache.entries[k] = e
+	}
+	if _, header, body := get(g, "/v1/echo/1"); header.Get("X-Cache") != "MISS" || !strings.Contains(body, `"call":3`) {
+		t.Errorf("after the TTL: X-Cache %q, body %s; want a new response", header.Get("X-Cache"), body)
+	}
+
+	uncached, _ := testGateway(t, Options{})
+	if _, header, _ := get(uncached, "/v1/echo/1"); header.Get("X-Cache") != "" {
+		t.Errorf("without a TTL: X-Cache %q", header.Get("X-Cache"))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	g, _ := testGateway(t, Options{})
+	tests := []struct {
+		path string
+		want int
+		body string
+	}{
+		{"/v1/missing", http.StatusNotFound, `{"error":"no such license","ok":false}`},
+		{"/v1/broken", http.StatusBadGateway, `{"error":"keygen unreachable","ok":false}`},
+		{"/v1/nope", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		code, _, body := get(g, tt.path)
+		if code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.path, code, tt.want)
+		}
+		if tt.body != "" && strings.TrimSpace(body) != tt.body {
+			t.Errorf("%s: body %s, want %s", tt.path, body, tt.body)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	g, _ := testGateway(t, Options{APIKeys: []string{"k"}, CacheTTL: time.Minute})
+	get(g, "/v1/echo/1", "X-API-Key", "k")
+	get(g, "/v1/echo/1", "X-API-Key", "k")
+	get(g, "/v1/echo/1")
+	get(g, "/v1/broken", "X-API-Key", "k")
+
+	_, header, body := get(g, "/metrics")
+	if ct := header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, line := range []string{
+		"# TYPE keygen_serve_requests_total counter",
+		`keygen_serve_requests_total{route="echo",code="200"} 2`,
+		`keygen_serve_requests_total{route="echo",code="401"} 1`,
+		`keygen_serve_requests_total{route="broken",code="502"} 1`,
+		`keygen_serve_request_duration_seconds_count{route="echo"} 3`,
+		`keygen_serve_cache_hits_total{route="echo"} 1`,
+		`keygen_serve_cache_misses_total{route="echo"} 1`,
+		`keygen_serve_upstream_errors_total{route="broken"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics lack %q:\n%s", line, body)
+		}
+	}
+	if strings.Contains(body, fmt.Sprintf("route=%q,code=%q", "echo", "404")) {
+		t.Errorf("metrics count a request that wasn't made:\n%s", body)
+	}
+}
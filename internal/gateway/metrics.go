@@ -0,0 +1,116 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metrics are the gateway's counters, exposed in the Prometheus text format.
+type metrics struct {
+	mu             sync.Mutex
+	requests       map[[2]string]int64 // route, status code
+	durationSum    map[string]float64
+	durationCount  map[string]int64
+	cacheHits      map[string]int64
+	cacheMisses    map[string]int64
+	upstreamErrors map[string]int64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		requests:       map[[2]string]int64{},
+		durationSum:    map[string]float64{},
+		durationCount:  map[string]int64{},
+		cacheHits:      map[string]int64{},
+		cacheMisses:    map[string]int64{},
+		upstreamErrors: map[string]int64{},
+	}
+}
+
+func (m *metrics) observe(route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[2]string{route, fmt.Sprintf("%d", status)}]++
+	m.durationSum[route] += d.Seconds()
+	m.durationCount[route]++
+}
+
+func (m *metrics) cacheHit(route string) {
+	m.mu.Lock()
+	m.cacheHits[route]++
+	m.mu.Unlock()
+}
+
+func (m *metrics) cacheMiss(route string) {
+	m.mu.Lock()
+	m.cacheMisses[route]++
+	m.mu.Unlock()
+}
+
+func (m *metrics) upstreamError(route string) {
+	m.mu.Lock()
+	m.upstreamErrors[route]++
+	m.mu.Unlock()
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	header := func(name, typ, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("keygen_serve_requests_total", "counter", "Requests handled, by route and status code.")
+	reqKeys := make([][2]string, 0, len(m.requests))
+	for k := range m.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		if reqKeys[i][0] != reqKeys[j][0] {
+			return reqKeys[i][0] < reqKeys[j][0]
+		}
+		return reqKeys[i][1] < reqKeys[j][1]
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(&b, "keygen_serve_requests_total{route=%q,code=%q} %d\n", k[0], k[1], m.requests[k])
+	}
+
+	header("keygen_serve_request_duration_seconds", "summary", "Time spent handling requests, by route.")
+	for _, route := range sortedKeys(m.durationCount) {
+		fmt.Fprintf(&b, "keygen_serve_request_duration_seconds_sum{route=%q} %g\n", route, m.durationSum[route])
+		fmt.Fprintf(&b, "keygen_serve_request_duration_seconds_count{route=%q} %d\n", route, m.durationCount[route])
+	}
+
+	counters := []struct {
+		name, help string
+		values     map[string]int64
+	}{
+		{"keygen_serve_cache_hits_total", "Responses served from the cache, by route.", m.cacheHits},
+		{"keygen_serve_cache_misses_total", "Responses not found in the cache, by route.", m.cacheMisses},
+		{"keygen_serve_upstream_errors_total", "Requests that failed talking to Keygen, by route.", m.upstreamErrors},
+	}
+	for _, c := range counters {
+		header(c.name, "counter", c.help)
+		for _, route := range sortedKeys(c.values) {
+			fmt.Fprintf(&b, "%s{route=%q} %d\n", c.name, route, c.values[route])
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
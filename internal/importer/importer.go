@@ -0,0 +1,443 @@
+// Package importer reads user and license rows from CSV files for
+// 'keygen import', maps their columns to Keygen fields and validates them.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Type describes what an import creates.
+type Type struct {
+	Name string
+	// Fields are the fields a column can map to, besides metadata.<key>.
+	Fields []string
+	// Required fields must have a value in every row.
+	Required []string
+}
+
+// Types lists the importable types.
+var Types = []Type{
+	{
+		Name:     "users",
+		Fields:   []string{"email", "first_name", "last_name", "role", "password"},
+		Required: []string{"email"},
+	},
+	{
+		Name:     "licenses",
+		Fields:   []string{"policy", "key", "name", "owner", "expiry", "max_machines", "max_cores", "max_uses", "protected", "suspended"},
+		Required: []string{"policy"},
+	},
+}
+
+// Lookup returns the import type with the given name.
+func Lookup(name string) (Type, bool) {
+	for _, t := range Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// Mapping maps Keygen fields to CSV columns.
+type Mapping struct {
+	// Columns maps a field, e.g. "email" or "metadata.reseller", to the
+	// header of the column holding it.
+	Columns map[string]string `yaml:"columns"`
+	// Defaults are used for fields a row leaves empty.
+	Defaults map[string]string `yaml:"defaults"`
+}
+
+// LoadMapping reads a YAML mapping file.
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Mapping
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Row is one data row of the CSV file.
+type Row struct {
+	// Line is the row's line number in the file, counting the header.
+	Line int `json:"line"`
+	// Record holds the row's original cells.
+	Record []string `json:"-"`
+	// Fields are the mapped values, with defaults applied.
+	Fields map[string]string `json:"fields"`
+	Errors []string          `json:"errors,omitempty"`
+
+	// Status is the outcome: created, existing, duplicate, failed or
+	// invalid; "create" in dry runs.
+	Status string `json:"status,omitempty"`
+	// ID is the created or existing resource.
+	ID string `json:"id,omitempty"`
+	// OwnerID is a license's owner.
+	OwnerID string `json:"owner_id,omitempty"`
+	// First is the earlier row a duplicate repeats.
+	First *Row `json:"-"`
+}
+
+// secretFields are never echoed back: they are redacted in JSON output and
+// their columns are left out of the results file.
+var secretFields = []string{"password"}
+
+// MarshalJSON redacts the secret fields.
+func (r Row) MarshalJSON() ([]byte, error) {
+	type plain Row
+	out := plain(r)
+	out.Fields = make(map[string]string, len(r.Fields))
+	for k, v := range r.Fields {
+		out.Fields[k] = v
+	}
+	for _, field := range secretFields {
+		if _, ok := out.Fields[field]; ok {
+			out.Fields[field] = "[redacted]"
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Errorf records a validation error on the row.
+func (r *Row) Errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// File is a parsed CSV file.
+type File struct {
+	Header []string
+	Rows   []*Row
+	// columns maps fields to the index of their column.
+	columns map[string]int
+}
+
+// Read parses a CSV file and maps its columns. Without a mapping, columns
+// whose header names a field ("Email", "first name", "metadata.tier") are
+// used.
+func Read(r io.Reader, t Type, m *Mapping) (*File, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the CSV file is empty")
+	}
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns, err := mapColumns(header, t, m)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &File{Header: header, columns: columns}
+	for i, record := range records[1:] {
+		if blank(record) {
+			continue
+		}
+		row := &Row{Line: i + 2, Record: record, Fields: map[string]string{}}
+		for field, col := range columns {
+			if col < len(record) {
+				if v := strings.TrimSpace(record[col]); v != "" {
+					row.Fields[field] = v
+				}
+			}
+		}
+		if m != nil {
+			for field, v := range m.Defaults {
+				if _, ok := row.Fields[field]; !ok && v != "" {
+					row.Fields[field] = v
+				}
+			}
+		}
+		f.Rows = append(f.Rows, row)
+	}
+	return f, nil
+}
+
+// mapColumns returns the column index of each mapped field.
+func mapColumns(header []string, t Type, m *Mapping) (map[string]int, error) {
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.TrimSpace(h)] = i
+	}
+
+	columns := map[string]int{}
+	if m != nil && len(m.Columns) > 0 {
+		for field, col := range m.Columns {
+			if !t.accepts(field) {
+				return nil, fmt.Errorf("mapping: unknown field %q for %s", field, t.Name)
+			}
+			i, ok := index[col]
+			if !ok {
+				return nil, fmt.Errorf("mapping: column %q of %s is not in the CSV header", col, field)
+			}
+			columns[field] = i
+		}
+	} else {
+		for i, h := range header {
+			field := normalize(h)
+			if t.accepts(field) {
+				columns[field] = i
+			}
+		}
+	}
+	if m != nil {
+		for field := range m.Defaults {
+			if !t.accepts(field) {
+				return nil, fmt.Errorf("mapping: unknown default %q for %s", field, t.Name)
+			}
+		}
+	}
+
+	for _, field := range t.Required {
+		_, mapped := columns[field]
+		_, defaulted := m.defaults()[field]
+		if !mapped && !defaulted {
+			return nil, fmt.Errorf("no column maps to %s; name a column %q or map one in --mapping", field, field)
+		}
+	}
+	return columns, nil
+}
+
+func (m *Mapping) defaults() map[string]string {
+	if m == nil {
+		return nil
+	}
+	return m.Defaults
+}
+
+func (t Type) accepts(field string) bool {
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		return key != ""
+	}
+	for _, f := range t.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize turns a header like "First Name" into a field name. Metadata
+// keys keep their case.
+func normalize(h string) string {
+	h = strings.TrimSpace(h)
+	if key, ok := strings.CutPrefix(h, "metadata."); ok {
+		return "metadata." + key
+	}
+	return strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(h, "-", " ")), "_"))
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the values of every row and the duplicates between them,
+// recording errors on the rows. It returns the number of invalid rows.
+func (f *File) Validate(t Type) int {
+	keys := map[string]int{}
+	for _, row := range f.Rows {
+		for _, field := range t.Required {
+			if row.Fields[field] == "" {
+				row.Errorf("%s is required", field)
+			}
+		}
+		for _, field := range []string{"email", "owner"} {
+			if v := row.Fields[field]; v != "" && (field == "email" || strings.Contains(v, "@")) && !ValidEmail(v) {
+				row.Errorf("%s %q is not a valid email address", field, v)
+			}
+		}
+		if v := row.Fields["expiry"]; v != "" {
+			if _, err := ParseExpiry(v); err != nil {
+				row.Errorf("expiry %q is not a date (YYYY-MM-DD or RFC 3339)", v)
+			}
+		}
+		for _, field := range []string{"max_machines", "max_cores", "max_uses"} {
+			if v := row.Fields[field]; v != "" {
+				if n, err := strconv.Atoi(v); err != nil || n < 0 {
+					row.Errorf("%s %q is not a whole number", field, v)
+				}
+			}
+		}
+		for _, field := range []string{"protected", "suspended"} {
+			if v := row.Fields[field]; v != "" {
+				if _, err := strconv.ParseBool(v); err != nil {
+					row.Errorf("%s %q is not true or false", field, v)
+				}
+			}
+		}
+		if key := row.Fields["key"]; t.Name == "licenses" && key != "" {
+			if line, ok := keys[key]; ok {
+				row.Errorf("duplicate key %q, also on line %d", key, line)
+			} else {
+				keys[key] = row.Line
+			}
+		}
+	}
+	return f.Invalid()
+}
+
+// Invalid returns the number of rows with errors.
+func (f *File) Invalid() int {
+	n := 0
+	for _, row := range f.Rows {
+		if len(row.Errors) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteResults writes the file back with status, id, owner_id for
+// licenses, and error columns appended to each row. Columns of secret
+// fields are left out.
+func (f *File) WriteResults(w io.Writer, t Type) error {
+	extra := []string{"status", "id", "error"}
+	if t.Name == "licenses" {
+		extra = []string{"status", "id", "owner_id", "error"}
+	}
+	secret := map[int]bool{}
+	for _, field := range secretFields {
+		if i, ok := f.columns[field]; ok {
+			secret[i] = true
+		}
+	}
+	keep := func(record []string) []string {
+		kept := make([]string, 0, len(f.Header)+len(extra))
+		for i := range f.Header {
+			if secret[i] {
+				continue
+			}
+			if i < len(record) {
+				kept = append(kept, record[i])
+			} else {
+				kept = append(kept, "")
+			}
+		}
+		return kept
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append(keep(f.Header), extra...)); err != nil {
+		return err
+	}
+	for _, row := range f.Rows {
+		record := append(keep(row.Record), row.Status, row.ID)
+		if t.Name == "licenses" {
+			record = append(record, row.OwnerID)
+		}
+		record = append(record, strings.Join(row.Errors, "; "))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ValidEmail reports whether s is a bare email address.
+func ValidEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return false
+	}
+	domain := s[strings.LastIndex(s, "@")+1:]
+	return strings.Contains(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// ParseExpiry parses a date or RFC 3339 timestamp. Dates expire at the
+// start of the day in UTC.
+func ParseExpiry(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// Attributes returns the Keygen attributes of a valid row: fields in
+// camelCase with typed values, and metadata.<key> fields collected into
+// metadata. Whole-number metadata values are sent as numbers. The policy
+// and owner references are not attributes.
+func Attributes(row *Row) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	metadata := map[string]interface{}{}
+	for _, field := range sortedFields(row.Fields) {
+		v := row.Fields[field]
+		if key, ok := strings.CutPrefix(field, "metadata."); ok {
+			if n, err := strconv.Atoi(v); err == nil {
+				metadata[key] = n
+			} else {
+				metadata[key] = v
+			}
+			continue
+		}
+		switch field {
+		case "policy", "owner":
+			continue
+		case "email":
+			attrs["email"] = strings.ToLower(v)
+		case "expiry":
+			t, _ := ParseExpiry(v)
+			attrs["expiry"] = t.UTC().Format(time.RFC3339)
+		case "max_machines", "max_cores", "max_uses":
+			n, _ := strconv.Atoi(v)
+			attrs[camelCase(field)] = n
+		case "protected", "suspended":
+			b, _ := strconv.ParseBool(v)
+			attrs[field] = b
+		default:
+			attrs[camelCase(field)] = v
+		}
+	}
+	if len(metadata) > 0 {
+		attrs["metadata"] = metadata
+	}
+	return attrs
+}
+
+func sortedFields(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
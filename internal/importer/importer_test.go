@@ -0,0 +1,228 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	users, _ := Lookup("users")
+	licenses, _ := Lookup("licenses")
+
+	tests := []struct {
+		name    string
+		typ     Type
+		csv     string
+		mapping *Mapping
+		want    []string // "line fields" per row
+		wantErr string
+	}{
+		{
+			name: "headers name the fields",
+			typ:  users,
+			csv:  "\ufeffEmail,First Name,last-name,metadata.Tier,Notes\nada@example.com, Ada ,Lovelace,gold,ignored\n",
+			want: []string{"2 map[email:ada@example.com first_name:Ada last_name:Lovelace metadata.Tier:gold]"},
+		},
+		{
+			name: "blank rows are skipped and keep line numbers",
+			typ:  users,
+			csv:  "email\na@example.com\n,\nb@example.com\n",
+			want: []string{"2 map[email:a@example.com]", "4 map[email:b@example.com]"},
+		},
+		{
+			name: "short rows",
+			typ:  users,
+			csv:  "email,role\na@example.com\n",
+			want: []string{"2 map[email:a@example.com]"},
+		},
+		{
+			name: "mapping with defaults",
+			typ:  licenses,
+			csv:  "Plan,Customer,Seats\nPro,ada@example.com,3\n,bob@example.com,\n",
+			mapping: &Mapping{
+				Columns:  map[string]string{"owner": "Customer", "max_machines": "Seats", "policy": "Plan"},
+				Defaults: map[string]string{"policy": "Basic", "max_machines": "1"},
+			},
+			want: []string{
+				"2 map[max_machines:3 owner:ada@example.com policy:Pro]",
+				"3 map[max_machines:1 owner:bob@example.com policy:Basic]",
+			},
+		},
+		{
+			name:    "a default stands in for a required column",
+			typ:     licenses,
+			csv:     "key\nK-1\n",
+			mapping: &Mapping{Defaults: map[string]string{"policy": "Basic"}},
+			want:    []string{"2 map[key:K-1 policy:Basic]"},
+		},
+		{
+			name:    "empty file",
+			typ:     users,
+			csv:     "",
+			wantErr: "the CSV file is empty",
+		},
+		{
+			name:    "required column missing",
+			typ:     licenses,
+			csv:     "key\nK-1\n",
+			wantErr: `no column maps to policy; name a column "policy" or map one in --mapping`,
+		},
+		{
+			name:    "mapping to an unknown field",
+			typ:     users,
+			csv:     "email\na@example.com\n",
+			mapping: &Mapping{Columns: map[string]string{"email": "email", "phone": "email"}},
+			wantErr: `mapping: unknown field "phone" for users`,
+		},
+		{
+			name:    "mapping to a missing column",
+			typ:     users,
+			csv:     "email\na@example.com\n",
+			mapping: &Mapping{Columns: map[string]string{"email": "E-mail"}},
+			wantErr: `mapping: column "E-mail" of email is not in the CSV header`,
+		},
+		{
+			name:    "unknown default",
+			typ:     users,
+			csv:     "email\na@example.com\n",
+			mapping: &Mapping{Defaults: map[string]string{"policy": "Basic"}},
+			wantErr: `mapping: unknown default "policy" for users`,
+		},
+		{
+			name:    "malformed CSV",
+			typ:     users,
+			csv:     "email\n\"a@example.com\n",
+			wantErr: "reading CSV",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Read(strings.NewReader(tt.csv), tt.typ, tt.mapping)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			var got []string
+			for _, row := range f.Rows {
+				got = append(got, fmt.Sprintf("%d %v", row.Line, row.Fields))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  string
+		csv  string
+		want [][]string // errors per row
+	}{
+		{
+			name: "valid users",
+			typ:  "users",
+			csv:  "email,role\nada@example.com,admin\nbob@example.co.uk,\n",
+			want: [][]string{nil, nil},
+		},
+		{
+			name: "user emails",
+			typ:  "users",
+			csv:  "email,first_name\n,Ada\nAda <ada@example.com>,\nada@localhost,\nada@example.,\n",
+			want: [][]string{
+				{"email is required"},
+				{`email "Ada <ada@example.com>" is not a valid email address`},
+				{`email "ada@localhost" is not a valid email address`},
+				{`email "ada@example." is not a valid email address`},
+			},
+		},
+		{
+			name: "license values",
+			typ:  "licenses",
+			csv: "policy,owner,expiry,max_machines,max_uses,protected\n" +
+				"Pro,ada@example.com,2027-01-01,3,0,true\n" +
+				"Pro,u-123,2027-01-01T12:00:00Z,,,\n" +
+				"Pro,ada@,01/01/2027,-1,many,yes\n",
+			want: [][]string{
+				nil,
+				nil,
+				{
+					`owner "ada@" is not a valid email address`,
+					`expiry "01/01/2027" is not a date (YYYY-MM-DD or RFC 3339)`,
+					`max_machines "-1" is not a whole number`,
+					`max_uses "many" is not a whole number`,
+					`protected "yes" is not true or false`,
+				},
+			},
+		},
+		{
+			name: "duplicate license keys",
+			typ:  "licenses",
+			csv:  "policy,key\nPro,K-1\nPro,K-2\n,K-1\n",
+			want: [][]string{nil, nil, {"policy is required", `duplicate key "K-1", also on line 2`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, _ := Lookup(tt.typ)
+			f, err := Read(strings.NewReader(tt.csv), typ, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			invalid := f.Validate(typ)
+
+			var got [][]string
+			wantInvalid := 0
+			for i, row := range f.Rows {
+				got = append(got, row.Errors)
+				if len(tt.want[i]) > 0 {
+					wantInvalid++
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors =\n%q\nwant\n%q", got, tt.want)
+			}
+			if invalid != wantInvalid {
+				t.Errorf("Validate = %d, want %d", invalid, wantInvalid)
+			}
+		})
+	}
+}
+
+func TestSecretsAreNotEchoed(t *testing.T) {
+	users, _ := Lookup("users")
+	f, err := Read(strings.NewReader("email,Password,note\nada@example.com,hunter2,hi\n"), users, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := f.Rows[0]
+	row.Status, row.ID = "created", "u-1"
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), `"password":"[redacted]"`) {
+		t.Errorf("row JSON = %s, want the password redacted", data)
+	}
+	if row.Fields["password"] != "hunter2" {
+		t.Error("MarshalJSON changed the row's fields")
+	}
+
+	var out strings.Builder
+	if err := f.WriteResults(&out, users); err != nil {
+		t.Fatal(err)
+	}
+	if want := "email,note,status,id,error\nada@example.com,hi,created,u-1,\n"; out.String() != want {
+		t.Errorf("results =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,208 @@
+// Package mcp serves CLI operations as Model Context Protocol tools over
+// stdio, using newline-delimited JSON-RPC 2.0.
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ProtocolVersion is the MCP revision the server speaks when the client
+// doesn't ask for one.
+const ProtocolVersion = "2024-11-05"
+
+// Tool is an operation exposed to MCP clients.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the tool's arguments object.
+	InputSchema map[string]interface{}
+	ReadOnly    bool
+	// Destructive tools are hidden and refused unless the server allows them.
+	Destructive bool
+	Call        func(args json.RawMessage) (interface{}, error)
+}
+
+// Server answers MCP requests for a fixed set of tools.
+type Server struct {
+	name             string
+	version          string
+	tools            map[string]Tool
+	allowDestructive bool
+}
+
+// NewServer creates a server exposing tools. Destructive tools are only
+// offered when allowDestructive is set.
+func NewServer(name, version string, tools []Tool, allowDestructive bool) *Server {
+	s := &Server{name: name, version: version, tools: map[string]Tool{}, allowDestructive: allowDestructive}
+	for _, t := range tools {
+		s.tools[t.Name] = t
+	}
+	return s
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes.
+const (
+	codeParse          = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Serve reads requests from r and writes responses to w until r is
+// exhausted. Requests are handled one at a time, in order.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParse, err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, rerr := s.handle(&req)
+		// Notifications carry no id and get no response.
+		if len(req.ID) == 0 {
+			continue
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rerr}
+		if rerr == nil && result == nil {
+			resp.Result = map[string]interface{}{}
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Server) handle(req *request) (interface{}, *rpcError) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{codeInvalidRequest, "jsonrpc must be \"2.0\""}
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]interface{}{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return nil, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.listTools()}, nil
+	case "tools/call":
+		return s.callTool(req.Params)
+	}
+	if len(req.ID) == 0 {
+		// Unknown notifications, e.g. notifications/initialized, are ignored.
+		return nil, nil
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method not found: %s", req.Method)}
+}
+
+func (s *Server) listTools() []map[string]interface{} {
+	names := make([]string, 0, len(s.tools))
+	for name, t := range s.tools {
+		if t.Destructive && !s.allowDestructive {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		t := s.tools[name]
+		tools[i] = map[string]interface{}{
+			"name":        t.Name,
+			"description": t.Description,
+			"inputSchema": t.InputSchema,
+			"annotations": map[string]interface{}{
+				"readOnlyHint":    t.ReadOnly,
+				"destructiveHint": t.Destructive,
+			},
+		}
+	}
+	return tools
+}
+
+// callTool runs a tool. Failures of the tool itself are reported in the
+// result with isError set, so the model can see and react to them.
+func (s *Server) callTool(raw json.RawMessage) (interface{}, *rpcError) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{codeInvalidParams, err.Error()}
+	}
+	t, ok := s.tools[params.Name]
+	if !ok {
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool: %s", params.Name)}
+	}
+	if t.Destructive && !s.allowDestructive {
+		return toolError(fmt.Errorf("tool %s is destructive and disabled for this profile (enable with keygen profile edit <name> --allow-destructive)", t.Name)), nil
+	}
+
+	result, err := t.Call(params.Arguments)
+	if err != nil {
+		return toolError(err), nil
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolError(err), nil
+	}
+	return map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": string(data)}},
+		"isError": false,
+	}, nil
+}
+
+func toolError(err error) map[string]interface{} {
+	return map[string]interface{}{
+		"content": []map[string]interface{}{{"type": "text", "text": err.Error()}},
+		"isError": true,
+	}
+}
@@ -0,0 +1,108 @@
+// Package metrics writes metric families in the Prometheus text and
+// OpenMetrics exposition formats.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric types.
+const (
+	Gauge   = "gauge"
+	Counter = "counter"
+)
+
+// Family is a named metric with its samples.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Sample is one labelled value of a family.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Label is a metric label.
+type Label struct {
+	Name, Value string
+}
+
+// L builds labels from name/value pairs.
+func L(pairs ...string) []Label {
+	labels := make([]Label, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, Label{pairs[i], pairs[i+1]})
+	}
+	return labels
+}
+
+// Add appends a sample to the family.
+func (f *Family) Add(value float64, labels ...Label) {
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// Write writes the families in the Prometheus text format, or in OpenMetrics
+// when openMetrics is set. Counter names end in _total in both; OpenMetrics
+// declares the family without the suffix.
+func Write(w io.Writer, families []Family, openMetrics bool) error {
+	var b strings.Builder
+	for _, f := range families {
+		name := f.Name
+		if openMetrics && f.Type == Counter {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, escapeHelp(f.Help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, f.Type)
+
+		samples := append([]Sample(nil), f.Samples...)
+		sort.SliceStable(samples, func(i, j int) bool { return labelString(samples[i].Labels) < labelString(samples[j].Labels) })
+		for _, s := range samples {
+			fmt.Fprintf(&b, "%s%s %s\n", f.Name, labelString(s.Labels), formatValue(s.Value))
+		}
+	}
+	if openMetrics {
+		b.WriteString("# EOF\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func labelString(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + escapeLabel(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
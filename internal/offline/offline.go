@@ -0,0 +1,245 @@
+// Package offline answers read-only lookups from the copy of an account that
+// 'keygen sync' stores, for sites without a route to the license server.
+package offline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+)
+
+// ErrNotFound is returned for resources that weren't in the account when it
+// was synced.
+var ErrNotFound = errors.New("not found in the sync")
+
+// Store is a synced account, indexed for the lookups of the offline
+// commands. Its methods match those of api.Client.
+type Store struct {
+	// Synced is when the copy was taken.
+	Synced    time.Time
+	AccountID string
+	Counts    map[string]int
+
+	licenses     map[string]*api.License // by ID and by key
+	users        map[string]*api.User    // by ID and by lowercased email
+	userLicenses map[string][]api.License
+	// machines holds each license's machines with their components.
+	machines     map[string][]api.Machine
+	fingerprints map[string][]api.Machine
+	components   map[string]*api.Component // by fingerprint
+}
+
+// Load reads the sync file at path.
+func Load(path string) (*Store, error) {
+	snap, err := snapshot.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(snap)
+}
+
+// New indexes a snapshot of an account.
+func New(snap *snapshot.Snapshot) (*Store, error) {
+	synced, err := time.Parse(time.RFC3339, snap.Created)
+	if err != nil {
+		return nil, fmt.Errorf("sync time %q: %w", snap.Created, err)
+	}
+	s := &Store{
+		Synced:       synced,
+		AccountID:    snap.AccountID,
+		Counts:       snap.Counts(),
+		licenses:     map[string]*api.License{},
+		users:        map[string]*api.User{},
+		userLicenses: map[string][]api.License{},
+		machines:     map[string][]api.Machine{},
+		fingerprints: map[string][]api.Machine{},
+		components:   map[string]*api.Component{},
+	}
+
+	byMachine := map[string][]api.Component{}
+	for _, r := range snap.Resources["components"] {
+		c := api.ParseComponent(resource(r))
+		s.components[c.Fingerprint] = &c
+		byMachine[c.MachineID] = append(byMachine[c.MachineID], c)
+	}
+	for _, r := range snap.Resources["machines"] {
+		m := api.ParseMachine(resource(r))
+		m.Components = byMachine[m.ID]
+		s.machines[m.LicenseID] = append(s.machines[m.LicenseID], m)
+		s.fingerprints[m.Fingerprint] = append(s.fingerprints[m.Fingerprint], m)
+	}
+	for _, r := range snap.Resources["users"] {
+		u := api.ParseUser(resource(r))
+		s.users[u.ID] = &u
+		s.users[strings.ToLower(u.Email)] = &u
+	}
+	for _, r := range snap.Resources["licenses"] {
+		l := api.ParseLicense(resource(r))
+		s.licenses[l.ID] = &l
+		if l.Key != "" {
+			s.licenses[l.Key] = &l
+		}
+		if l.OwnerID != "" {
+			s.userLicenses[l.OwnerID] = append(s.userLicenses[l.OwnerID], l)
+		}
+	}
+	return s, nil
+}
+
+// resource turns a snapshot resource back into the API's form so the api
+// package's parse functions can read it.
+func resource(r snapshot.Resource) api.JSONAPIResource {
+	res := api.JSONAPIResource{ID: r.ID, Attributes: r.Attributes, Relationships: map[string]api.Relationship{}}
+	for name, id := range r.Relationships {
+		data, _ := json.Marshal(api.RelationshipData{ID: id})
+		res.Relationships[name] = api.Relationship{Data: data}
+	}
+	return res
+}
+
+func (s *Store) notFound(kind, id string) error {
+	return fmt.Errorf("%s %s %w of %s", kind, id, ErrNotFound, s.Synced.Format(time.RFC3339))
+}
+
+// GetLicense returns a license by ID or key.
+func (s *Store) GetLicense(id string) (*api.License, error) {
+	l, ok := s.licenses[id]
+	if !ok {
+		return nil, s.notFound("license", id)
+	}
+	found := *l
+	return &found, nil
+}
+
+// ValidateLicense checks a license against the synced copy: its status, its
+// expiry against the current time and its machine count. Checks that need
+// the server, like heartbeats and scopes, are not made.
+func (s *Store) ValidateLicense(id string) (*api.LicenseValidation, *api.License, error) {
+	l, err := s.GetLicense(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	v := &api.LicenseValidation{}
+	switch {
+	case strings.EqualFold(l.Status, "BANNED"):
+		v.Code, v.Detail = "BANNED", "is banned"
+	case strings.EqualFold(l.Status, "SUSPENDED"):
+		v.Code, v.Detail = "SUSPENDED", "is suspended"
+	case expired(l):
+		v.Code, v.Detail = "EXPIRED", "is expired"
+	case l.MaxMachines > 0 && len(s.machines[l.ID]) > l.MaxMachines:
+		v.Code, v.Detail = "TOO_MANY_MACHINES", "has too many associated machines"
+	default:
+		v.Valid, v.Code, v.Detail = true, "VALID", "is valid"
+	}
+	return v, l, nil
+}
+
+// expired reports whether a license has expired by now, which may be after
+// the sync.
+func expired(l *api.License) bool {
+	if t, err := api.ParseTime(l.Expiry); err == nil {
+		return !t.After(time.Now())
+	}
+	return strings.EqualFold(l.Status, "EXPIRED")
+}
+
+// GetLicenseMachines returns a license's machines with their components.
+func (s *Store) GetLicenseMachines(licenseID string) ([]api.Machine, error) {
+	l, err := s.GetLicense(licenseID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]api.Machine(nil), s.machines[l.ID]...), nil
+}
+
+// ListMachines returns the machines matching the fingerprint and license
+// filters; other filters aren't supported offline.
+func (s *Store) ListMachines(params map[string]string) ([]api.Machine, error) {
+	for name := range params {
+		if name != "fingerprint" && name != "license" && !strings.HasPrefix(name, "page[") {
+			return nil, fmt.Errorf("filtering machines by %s is not supported offline", name)
+		}
+	}
+	licenseID := ""
+	if id := params["license"]; id != "" {
+		l, err := s.GetLicense(id)
+		if err != nil {
+			return nil, err
+		}
+		licenseID = l.ID
+	}
+
+	var candidates []api.Machine
+	switch {
+	case params["fingerprint"] != "":
+		candidates = s.fingerprints[params["fingerprint"]]
+	case licenseID != "":
+		candidates = s.machines[licenseID]
+	default:
+		for _, machines := range s.machines {
+			candidates = append(candidates, machines...)
+		}
+	}
+	var machines []api.Machine
+	for _, m := range candidates {
+		if licenseID == "" || m.LicenseID == licenseID {
+			machines = append(machines, m)
+		}
+	}
+	return machines, nil
+}
+
+// FindComponentByFingerprint returns the component with the fingerprint,
+// or nil when none was synced.
+func (s *Store) FindComponentByFingerprint(fingerprint string) (*api.Component, error) {
+	found, _ := s.FindComponentsByFingerprints([]string{fingerprint})
+	return found[fingerprint], nil
+}
+
+// FindComponentsByFingerprints looks up several fingerprints. Fingerprints
+// that weren't registered are absent from the returned map.
+func (s *Store) FindComponentsByFingerprints(fingerprints []string) (map[string]*api.Component, error) {
+	found := map[string]*api.Component{}
+	for _, fp := range fingerprints {
+		if c, ok := s.components[fp]; ok {
+			c := *c
+			found[fp] = &c
+		}
+	}
+	return found, nil
+}
+
+// GetUser returns a user by ID.
+func (s *Store) GetUser(id string) (*api.User, error) {
+	u, ok := s.users[id]
+	if !ok || u.ID != id {
+		return nil, s.notFound("user", id)
+	}
+	found := *u
+	return &found, nil
+}
+
+// FindUserByEmail returns the user with the email, ignoring case. When there
+// is none the error wraps api.ErrUserNotFound.
+func (s *Store) FindUserByEmail(email string) (*api.User, error) {
+	u, ok := s.users[strings.ToLower(email)]
+	if !ok || !strings.EqualFold(u.Email, email) {
+		return nil, fmt.Errorf("%w: %s (sync of %s)", api.ErrUserNotFound, email, s.Synced.Format(time.RFC3339))
+	}
+	found := *u
+	return &found, nil
+}
+
+// GetUserLicenses returns the licenses a user owns.
+func (s *Store) GetUserLicenses(userID string) ([]api.License, error) {
+	if _, err := s.GetUser(userID); err != nil {
+		return nil, err
+	}
+	return append([]api.License(nil), s.userLicenses[userID]...), nil
+}
@@ -0,0 +1,205 @@
+package offline
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/api"
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+)
+
+func attrs(kv ...interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		m[kv[i].(string)] = kv[i+1]
+	}
+	return m
+}
+
+// testStore is synced long before the expiries it holds, so whether a
+// license is expired depends on the current time, not the sync time.
+func testStore(t *testing.T) *Store {
+	t.Helper()
+	future := time.Now().AddDate(1, 0, 0).UTC().Format(time.RFC3339)
+	license := func(id, status, expiry string, maxMachines float64) snapshot.Resource {
+		a := attrs("key", "KEY-"+id, "status", status, "maxMachines", maxMachines)
+		if expiry != "" {
+			a["expiry"] = expiry
+		}
+		return snapshot.Resource{ID: id, Attributes: a, Relationships: map[string]string{"owner": "u1"}}
+	}
+	machine := func(id, license, fingerprint string) snapshot.Resource {
+		return snapshot.Resource{ID: id, Attributes: attrs("fingerprint", fingerprint), Relationships: map[string]string{"license": license}}
+	}
+	store, err := New(&snapshot.Snapshot{
+		Format:    snapshot.Format,
+		Version:   snapshot.Version,
+		AccountID: "acct",
+		Created:   "2020-01-01T00:00:00Z",
+		Resources: map[string][]snapshot.Resource{
+			"users": {{ID: "u1", Attributes: attrs("email", "Ada@Example.com")}},
+			"licenses": {
+				license("valid", "ACTIVE", future, 2),
+				license("banned", "BANNED", "2021-01-01T00:00:00Z", 1),
+				license("suspended", "SUSPENDED", "2021-01-01T00:00:00Z", 0),
+				license("lapsed", "ACTIVE", "2021-01-01T00:00:00Z", 1),
+				license("expired", "EXPIRED", "", 0),
+				license("crowded", "ACTIVE", future, 1),
+			},
+			"machines": {
+				machine("m1", "valid", "fp-1"),
+				machine("m2", "banned", "fp-2"),
+				machine("m3", "banned", "fp-3"),
+				machine("m4", "lapsed", "fp-4"),
+				machine("m5", "lapsed", "fp-5"),
+				machine("m6", "crowded", "fp-1"),
+				machine("m7", "crowded", "fp-7"),
+			},
+			"components": {{ID: "c1", Attributes: attrs("fingerprint", "disk-1"), Relationships: map[string]string{"machine": "m1"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestNew(t *testing.T) {
+	store := testStore(t)
+	if want := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); !store.Synced.Equal(want) {
+		t.Errorf("Synced = %v, want %v", store.Synced, want)
+	}
+	if store.AccountID != "acct" || store.Counts["licenses"] != 6 || store.Counts["machines"] != 7 {
+		t.Errorf("AccountID = %q, Counts = %v", store.AccountID, store.Counts)
+	}
+
+	machines, err := store.GetLicenseMachines("valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(machines) != 1 || len(machines[0].Components) != 1 || machines[0].Components[0].Fingerprint != "disk-1" {
+		t.Errorf("GetLicenseMachines = %+v, want m1 with its component", machines)
+	}
+	if l, err := store.GetLicense("KEY-valid"); err != nil || l.ID != "valid" {
+		t.Errorf("GetLicense by key = %v, %v", l, err)
+	}
+
+	_, err = New(&snapshot.Snapshot{Created: "yesterday"})
+	if err == nil || !strings.Contains(err.Error(), `sync time "yesterday"`) {
+		t.Errorf("New with a bad sync time: error = %v", err)
+	}
+}
+
+func TestValidateLicense(t *testing.T) {
+	store := testStore(t)
+	tests := []struct {
+		name  string
+		id    string
+		valid bool
+		code  string
+	}{
+		{name: "valid", id: "valid", valid: true, code: "VALID"},
+		{name: "banned before expired and too many machines", id: "banned", code: "BANNED"},
+		{name: "suspended before expired", id: "suspended", code: "SUSPENDED"},
+		{name: "expired since the sync, before too many machines", id: "lapsed", code: "EXPIRED"},
+		{name: "expired status without an expiry", id: "expired", code: "EXPIRED"},
+		{name: "too many machines", id: "crowded", code: "TOO_MANY_MACHINES"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, l, err := store.ValidateLicense(tt.id)
+			if err != nil {
+				t.Fatalf("ValidateLicense: %v", err)
+			}
+			if v.Valid != tt.valid || v.Code != tt.code || l.ID != tt.id {
+				t.Errorf("ValidateLicense = %+v for %s, want valid %v, code %s", v, l.ID, tt.valid, tt.code)
+			}
+		})
+	}
+
+	if _, _, err := store.ValidateLicense("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ValidateLicense of a missing license: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListMachines(t *testing.T) {
+	store := testStore(t)
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "all machines",
+			params: map[string]string{"page[size]": "100", "page[number]": "1"},
+			want:   []string{"m1", "m2", "m3", "m4", "m5", "m6", "m7"},
+		},
+		{
+			name:   "by fingerprint",
+			params: map[string]string{"fingerprint": "fp-1"},
+			want:   []string{"m1", "m6"},
+		},
+		{
+			name:   "by license key",
+			params: map[string]string{"license": "KEY-lapsed"},
+			want:   []string{"m4", "m5"},
+		},
+		{
+			name:   "by fingerprint and license",
+			params: map[string]string{"fingerprint": "fp-1", "license": "crowded"},
+			want:   []string{"m6"},
+		},
+		{
+			name:    "unknown license",
+			params:  map[string]string{"license": "missing"},
+			wantErr: "license missing not found in the sync",
+		},
+		{
+			name:    "unsupported filter",
+			params:  map[string]string{"fingerprint": "fp-1", "product": "p1"},
+			wantErr: "filtering machines by product is not supported offline",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			machines, err := store.ListMachines(tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ListMachines error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListMachines: %v", err)
+			}
+			var got []string
+			for _, m := range machines {
+				got = append(got, m.ID)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListMachines = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindUserByEmail(t *testing.T) {
+	store := testStore(t)
+	for _, email := range []string{"Ada@Example.com", "ada@example.com", "ADA@EXAMPLE.COM"} {
+		if u, err := store.FindUserByEmail(email); err != nil || u.ID != "u1" {
+			t.Errorf("FindUserByEmail(%q) = %v, %v; want u1", email, u, err)
+		}
+	}
+	if _, err := store.FindUserByEmail("bob@example.com"); !errors.Is(err, api.ErrUserNotFound) {
+		t.Errorf("FindUserByEmail of a missing user: error = %v, want api.ErrUserNotFound", err)
+	}
+	if _, err := store.GetUser("ada@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUser by email: error = %v, want ErrNotFound", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"html"
 	"os"
 	"strings"
 
@@ -15,7 +16,7 @@ func Success(data interface{}) {
 		"ok":   true,
 		"data": data,
 	}
-	printJSON(out)
+	render(out, data, nil)
 }
 
 func SuccessList(data interface{}, count int) {
@@ -24,7 +25,7 @@ func SuccessList(data interface{}, count int) {
 		"count": count,
 		"data":  data,
 	}
-	printJSON(out)
+	render(out, data, nil)
 }
 
 func Error(msg string) {
@@ -32,7 +33,7 @@ func Error(msg string) {
 		"ok":    false,
 		"error": msg,
 	}
-	printJSON(out)
+	renderError(out)
 }
 
 func ErrorDetail(msg string, detail interface{}) {
@@ -41,13 +42,23 @@ func ErrorDetail(msg string, detail interface{}) {
 		"error":  msg,
 		"detail": detail,
 	}
-	printJSON(out)
+	renderError(out)
 }
 
 func Raw(data interface{}) {
 	printJSON(data)
 }
 
+// Line prints v as a single line of compact JSON, for streaming output.
+func Line(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+	fmt.Println(string(data))
+}
+
 func printJSON(v interface{}) {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -83,6 +94,61 @@ func CSV(headers []string, rows [][]string) {
 	w.Flush()
 }
 
+// CSVRows writes rows without a header, for appending to earlier CSV output.
+func CSVRows(rows [][]string) {
+	w := csv.NewWriter(os.Stdout)
+	for _, row := range rows {
+		_ = w.Write(row)
+	}
+	w.Flush()
+}
+
+// Markdown output
+
+// Markdown writes a GitHub-flavoured Markdown table.
+func Markdown(headers []string, rows [][]string) {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	line := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = cell.Replace(c)
+		}
+		fmt.Printf("| %s |\n", strings.Join(escaped, " | "))
+	}
+	line(headers)
+	sep := make([]string, len(headers))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	line(sep)
+	for _, row := range rows {
+		line(row)
+	}
+}
+
+// HTML output
+
+// HTML writes a standalone HTML table with inline styles, so it survives
+// being pasted into an email.
+func HTML(headers []string, rows [][]string) {
+	const cellStyle = "border:1px solid #ccc;padding:4px 8px;text-align:left"
+	var b strings.Builder
+	b.WriteString("<table style=\"border-collapse:collapse;font-family:sans-serif;font-size:13px\">\n<thead><tr>")
+	for _, h := range headers {
+		fmt.Fprintf(&b, "<th style=\"%s;background:#f4f4f4\">%s</th>", cellStyle, html.EscapeString(h))
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for _, c := range row {
+			fmt.Fprintf(&b, "<td style=\"%s\">%s</td>", cellStyle, html.EscapeString(c))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	fmt.Print(b.String())
+}
+
 // FormatTable is a helper that maps generic data to table format
 func FormatTable(format string, headers []string, rows [][]string) {
 	switch strings.ToLower(format) {
@@ -90,6 +156,10 @@ func FormatTable(format string, headers []string, rows [][]string) {
 		Table(headers, rows)
 	case "csv":
 		CSV(headers, rows)
+	case "markdown":
+		Markdown(headers, rows)
+	case "html":
+		HTML(headers, rows)
 	default:
 		// JSON is handled by caller
 	}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+var (
+	query *jmespath.JMESPath
+	raw   bool
+)
+
+// SetQuery sets a JMESPath expression that is evaluated over the JSON
+// envelope before printing, e.g. "data[?status=='ACTIVE'].key". With raw,
+// string results print unquoted and list results print one element per line.
+func SetQuery(expr string, rawOutput bool) error {
+	if expr == "" {
+		if rawOutput {
+			return fmt.Errorf("--raw needs --query")
+		}
+		return nil
+	}
+	switch format {
+	case "table", "csv", "markdown", "html", "template", "openmetrics":
+		return fmt.Errorf("--query can't be combined with --format %s", format)
+	}
+
+	q, err := jmespath.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid --query: %w", err)
+	}
+	query = q
+	raw = rawOutput
+	return nil
+}
+
+// printQuery evaluates the query against v's JSON form and prints the result.
+func printQuery(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+
+	result, err := query.Search(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error evaluating --query: %v\n", err)
+		return
+	}
+
+	switch {
+	case raw:
+		if list, ok := result.([]interface{}); ok {
+			for _, item := range list {
+				fmt.Println(rawString(item))
+			}
+			return
+		}
+		fmt.Println(rawString(result))
+	case format == "yaml":
+		printYAML(result)
+	case format == "ndjson":
+		eachItem(result, Line)
+	default:
+		printJSON(result)
+	}
+}
+
+// rawString renders a query result like jq -r: strings unquoted, other
+// scalars as-is and objects as compact JSON.
+func rawString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		data, _ := json.Marshal(x)
+		return string(data)
+	}
+}
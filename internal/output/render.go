@@ -0,0 +1,238 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats lists the values accepted by --format. openmetrics is written by
+// the commands that support it; the rest print JSON.
+var Formats = []string{"json", "table", "csv", "markdown", "html", "ndjson", "yaml", "template", "openmetrics"}
+
+var (
+	format = "json"
+	tmpl   *template.Template
+
+	// annotations are added to the envelope of every result; notices are
+	// printed to stderr when the format drops the envelope.
+	annotations = map[string]interface{}{}
+	notices     []string
+)
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// Configure selects the output format for every later call. The template
+// format takes its template from templateText or templateFile.
+func Configure(f, templateText, templateFile string) error {
+	f = strings.ToLower(f)
+	known := false
+	for _, v := range Formats {
+		if v == f {
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown format %q (want one of: %s)", f, strings.Join(Formats, ", "))
+	}
+
+	if f != "template" {
+		if templateText != "" || templateFile != "" {
+			return fmt.Errorf("--template and --template-file need --format template")
+		}
+		format = f
+		return nil
+	}
+
+	if templateText != "" && templateFile != "" {
+		return fmt.Errorf("use either --template or --template-file, not both")
+	}
+	if templateFile != "" {
+		data, err := os.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("reading template: %w", err)
+		}
+		templateText = string(data)
+	}
+	if templateText == "" {
+		return fmt.Errorf("--format template needs --template or --template-file")
+	}
+
+	t, err := template.New("output").Funcs(templateFuncs).Parse(templateText)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+	tmpl = t
+	format = f
+	return nil
+}
+
+// Annotate adds key to the envelope of every later result, e.g. where the
+// data came from. Formats without an envelope print notice to stderr
+// instead, so the annotation is never lost.
+func Annotate(key string, value interface{}, notice string) {
+	annotations[key] = value
+	notices = append(notices, notice)
+}
+
+// Format returns the configured output format.
+func Format() string {
+	return format
+}
+
+// Streaming reports whether the format writes list items one at a time, so
+// callers can print pages as they arrive instead of collecting them first.
+func Streaming() bool {
+	return format == "ndjson" || format == "template"
+}
+
+// SuccessTable prints a result, using headers and rows for --format table,
+// csv, markdown and html.
+func SuccessTable(data interface{}, headers []string, rows [][]string) {
+	render(map[string]interface{}{"ok": true, "data": data}, data, &tabular{headers, rows})
+}
+
+// SuccessListTable prints a list result, using headers and rows for the
+// tabular formats.
+func SuccessListTable(data interface{}, count int, headers []string, rows [][]string) {
+	render(map[string]interface{}{"ok": true, "count": count, "data": data}, data, &tabular{headers, rows})
+}
+
+// Item prints a single record of a stream that is still being read, e.g.
+// log entries while following. A --query applies to each record.
+func Item(v interface{}) {
+	if query != nil {
+		printQuery(v)
+		return
+	}
+	switch format {
+	case "yaml":
+		fmt.Println("---")
+		printYAML(v)
+	case "template":
+		printTemplate(v)
+	default:
+		Line(v)
+	}
+}
+
+type tabular struct {
+	headers []string
+	rows    [][]string
+}
+
+// render writes a successful result. In the streaming formats a list is
+// written one element per line and the envelope is dropped; the other
+// formats print the whole envelope. A --query sees the envelope in every
+// format.
+func render(envelope map[string]interface{}, data interface{}, t *tabular) {
+	annotate(envelope, query != nil || format != "json" && format != "yaml")
+	if query != nil {
+		printQuery(envelope)
+		return
+	}
+	switch format {
+	case "table", "csv", "markdown", "html":
+		if t != nil {
+			FormatTable(format, t.headers, t.rows)
+			return
+		}
+		printJSON(envelope)
+	case "ndjson":
+		eachItem(data, Line)
+	case "template":
+		eachItem(data, printTemplate)
+	case "yaml":
+		printYAML(envelope)
+	default:
+		printJSON(envelope)
+	}
+}
+
+// renderError writes an error envelope in the configured format.
+func renderError(envelope map[string]interface{}) {
+	annotate(envelope, false)
+	switch format {
+	case "ndjson", "template":
+		Line(envelope)
+	case "yaml":
+		printYAML(envelope)
+	default:
+		printJSON(envelope)
+	}
+}
+
+// annotate adds the annotations to an envelope, and prints their notices
+// when the envelope won't be shown as is.
+func annotate(envelope map[string]interface{}, notify bool) {
+	for k, v := range annotations {
+		envelope[k] = v
+	}
+	if notify {
+		for _, n := range notices {
+			fmt.Fprintln(os.Stderr, n)
+		}
+	}
+}
+
+// eachItem calls fn for every element of a slice, or once for anything else.
+func eachItem(data interface{}, fn func(interface{})) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		fn(data)
+		return
+	}
+	for i := 0; i < v.Len(); i++ {
+		fn(v.Index(i).Interface())
+	}
+}
+
+// printYAML goes through JSON first so keys match the json tags used
+// everywhere else.
+func printYAML(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+	}
+	enc.Close()
+}
+
+// printTemplate executes the template against v's Go value, so struct
+// fields are addressed by their Go names, e.g. {{.Key}}.
+func printTemplate(v interface{}) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting output: %v\n", err)
+		return
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	fmt.Print(out)
+}
@@ -0,0 +1,265 @@
+// Package report renders account reports from HTML and Markdown templates.
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var builtins embed.FS
+
+// Data is the model every report template renders: the same aggregates as
+// 'keygen status'. Templates address fields by their Go names, e.g.
+// {{.Totals.Licenses}}.
+type Data struct {
+	Title     string
+	Generated time.Time
+	AccountID string
+	// UserEmail is set when the report is scoped to one user; Users and
+	// Products totals are then zero.
+	UserEmail    string
+	Totals       Totals
+	Statuses     []StatusCount
+	Licenses     []License
+	TopConsumers []License
+	Expiring     []License
+	ExpiringDays int
+	Warnings     []string
+}
+
+// Totals are the account-wide counts.
+type Totals struct {
+	Licenses   int
+	Users      int
+	Products   int
+	Machines   int
+	Components int
+}
+
+// StatusCount is the number of licenses in one status.
+type StatusCount struct {
+	Status  string
+	Count   int
+	Percent float64
+}
+
+// License is one license with its quotas.
+type License struct {
+	ID            string
+	Key           string
+	Name          string
+	Status        string
+	Owner         string
+	ProductID     string
+	PolicyID      string
+	Expiry        string
+	DaysRemaining int
+	Machines      int
+	Quotas        []Quota
+	// Utilization is the highest quota percentage of the license.
+	Utilization float64
+}
+
+// Quota is one limited resource of a license.
+type Quota struct {
+	Resource string
+	Used     int
+	Limit    float64
+	Percent  float64
+}
+
+// Statuses counts licenses by status, most common first.
+func Statuses(counts map[string]int) []StatusCount {
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	statuses := make([]StatusCount, 0, len(counts))
+	for s, n := range counts {
+		statuses = append(statuses, StatusCount{Status: s, Count: n, Percent: percent(float64(n), float64(total))})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Count != statuses[j].Count {
+			return statuses[i].Count > statuses[j].Count
+		}
+		return statuses[i].Status < statuses[j].Status
+	})
+	return statuses
+}
+
+// TopConsumers returns up to n licenses with quotas, highest utilization first.
+func TopConsumers(licenses []License, n int) []License {
+	var top []License
+	for _, l := range licenses {
+		if len(l.Quotas) > 0 {
+			top = append(top, l)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Utilization > top[j].Utilization })
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// Expiring returns the licenses that expire between now and cutoff, soonest
+// first. Licenses that have already expired are left out.
+func Expiring(licenses []License, now, cutoff time.Time) []License {
+	var expiring []License
+	for _, l := range licenses {
+		expiry, err := time.Parse(time.RFC3339, l.Expiry)
+		if err != nil || expiry.Before(now) || expiry.After(cutoff) {
+			continue
+		}
+		expiring = append(expiring, l)
+	}
+	sort.SliceStable(expiring, func(i, j int) bool { return expiring[i].Expiry < expiring[j].Expiry })
+	return expiring
+}
+
+// NewQuota builds a quota with its percentage of the limit.
+func NewQuota(resource string, used int, limit float64) Quota {
+	return Quota{Resource: resource, Used: used, Limit: limit, Percent: percent(float64(used), limit)}
+}
+
+func percent(n, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return n / total * 100
+}
+
+// Builtins lists the names of the built-in templates.
+func Builtins() []string {
+	entries, _ := builtins.ReadDir("templates")
+	seen := map[string]bool{}
+	var names []string
+	for _, e := range entries {
+		name := strings.SplitN(e.Name(), ".", 2)[0]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Template is a parsed report template.
+type Template struct {
+	Name     string
+	Markdown bool
+	execute  func(io.Writer, interface{}) error
+}
+
+// Load returns the built-in template called name, or parses name as a
+// template file when no built-in matches. Built-ins come in HTML and
+// Markdown; markdown picks the variant. Files ending in .md or .markdown
+// are parsed with text/template, all others with html/template.
+func Load(name string, markdown bool) (*Template, error) {
+	for _, b := range Builtins() {
+		if b != name {
+			continue
+		}
+		file := name + ".html.tmpl"
+		if markdown {
+			file = name + ".md.tmpl"
+		}
+		text, err := builtins.ReadFile("templates/" + file)
+		if err != nil {
+			return nil, fmt.Errorf("template %q has no %s variant", name, variant(markdown))
+		}
+		return parse(name, string(text), markdown)
+	}
+
+	text, err := os.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown template %q (built-in: %s, or a template file)", name, strings.Join(Builtins(), ", "))
+		}
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	return parse(filepath.Base(name), string(text), IsMarkdown(name))
+}
+
+// IsMarkdown reports whether path has a Markdown file extension.
+func IsMarkdown(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".md" || ext == ".markdown"
+}
+
+func variant(markdown bool) string {
+	if markdown {
+		return "Markdown"
+	}
+	return "HTML"
+}
+
+func parse(name, text string, markdown bool) (*Template, error) {
+	t := &Template{Name: name, Markdown: markdown}
+	if markdown {
+		tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+		t.execute = tmpl.Execute
+	} else {
+		tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing template: %w", err)
+		}
+		t.execute = tmpl.Execute
+	}
+	return t, nil
+}
+
+// Execute renders the report to w.
+func (t *Template) Execute(w io.Writer, data *Data) error {
+	if err := t.execute(w, data); err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return nil
+}
+
+var funcs = map[string]interface{}{
+	// pct formats a percentage without decimals, e.g. 83%.
+	"pct": func(p float64) string { return fmt.Sprintf("%.0f%%", p) },
+	// width caps a percentage at 100 for use as a bar width.
+	"width": func(p float64) float64 {
+		if p > 100 {
+			return 100
+		}
+		return p
+	},
+	// bar draws a percentage as a text bar of n characters.
+	"bar": func(p float64, n int) string {
+		filled := int(p/100*float64(n) + 0.5)
+		if filled > n {
+			filled = n
+		}
+		if filled < 0 {
+			filled = 0
+		}
+		return strings.Repeat("█", filled) + strings.Repeat("░", n-filled)
+	},
+	// date formats an RFC 3339 timestamp as YYYY-MM-DD.
+	"date": func(s string) string {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.Format("2006-01-02")
+	},
+	// md escapes pipes so a value fits in a Markdown table cell.
+	"md":    func(s string) string { return strings.ReplaceAll(s, "|", `\|`) },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
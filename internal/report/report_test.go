@@ -0,0 +1,28 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	licenses := []License{
+		{ID: "expired", Expiry: "2026-10-01T00:00:00Z"},
+		{ID: "expired-today", Expiry: "2026-10-18T11:00:00Z"},
+		{ID: "in-20-days", Expiry: "2026-11-07T00:00:00Z"},
+		{ID: "in-3-days", Expiry: "2026-10-21T00:00:00Z"},
+		{ID: "in-60-days", Expiry: "2026-12-17T00:00:00Z"},
+		{ID: "no-expiry"},
+		{ID: "bad-expiry", Expiry: "soon"},
+	}
+
+	var ids []string
+	for _, l := range Expiring(licenses, now, now.Add(30*24*time.Hour)) {
+		ids = append(ids, l.ID)
+	}
+	if want := []string{"in-3-days", "in-20-days"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Expiring = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,125 @@
+// Package schema derives JSON Schemas from the Go types the CLI prints, so
+// agents can validate command output without scraping help text.
+package schema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// For returns the JSON Schema of v's type as encoded by encoding/json.
+// A nil v, or an interface{}, accepts any value. Struct fields without
+// omitempty are required, and a desc tag becomes the field's description.
+func For(v interface{}) map[string]interface{} {
+	if v == nil {
+		return map[string]interface{}{}
+	}
+	return typeSchema(reflect.TypeOf(v))
+}
+
+// Envelope wraps a data schema in the {"ok": true, "data": ...} envelope
+// printed by the JSON output, with a count for lists.
+func Envelope(data map[string]interface{}, list bool) map[string]interface{} {
+	if list {
+		data = map[string]interface{}{"type": "array", "items": data}
+	}
+	props := map[string]interface{}{
+		"ok":   map[string]interface{}{"const": true},
+		"data": data,
+	}
+	required := []string{"ok", "data"}
+	if list {
+		props["count"] = map[string]interface{}{"type": "integer", "minimum": 0}
+		required = append(required, "count")
+	}
+	return map[string]interface{}{
+		"$schema":    Draft,
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	}
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(typeSchema(t.Elem()))
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return nullable(map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())})
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return nullable(map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())})
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		var required []string
+		addFields(t, props, &required)
+		s := map[string]interface{}{"type": "object", "properties": props}
+		if t.Name() != "" {
+			s["title"] = t.Name()
+		}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	return map[string]interface{}{}
+}
+
+// nullable allows null in addition to s, as encoding/json writes for nil
+// pointers, slices and maps.
+func nullable(s map[string]interface{}) map[string]interface{} {
+	if t, ok := s["type"].(string); ok {
+		s["type"] = []string{t, "null"}
+	}
+	return s
+}
+
+// addFields adds a struct's JSON fields to props, flattening embedded
+// structs the way encoding/json does.
+func addFields(t reflect.Type, props map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(ft, props, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		prop := typeSchema(f.Type)
+		if desc := f.Tag.Get("desc"); desc != "" {
+			prop["description"] = desc
+		}
+		props[name] = prop
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
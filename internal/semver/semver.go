@@ -0,0 +1,240 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version (major.minor.patch[-prerelease][+build]).
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+// Parse parses a semantic version. A leading "v" is accepted and missing
+// minor/patch components default to zero.
+func Parse(s string) (Version, error) {
+	var v Version
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return v, fmt.Errorf("empty version")
+	}
+
+	if i := strings.Index(s, "+"); i >= 0 {
+		v.Build = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is lower than, equal to
+// or greater than b. Build metadata is ignored.
+func Compare(a, b Version) int {
+	if c := cmpInt(a.Major, b.Major); c != 0 {
+		return c
+	}
+	if c := cmpInt(a.Minor, b.Minor); c != 0 {
+		return c
+	}
+	if c := cmpInt(a.Patch, b.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// CompareStrings compares two version strings. Unparseable versions sort
+// before valid ones.
+func CompareStrings(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return Compare(va, vb)
+}
+
+// Match reports whether version satisfies constraint. A constraint is a list of
+// comparators separated by spaces or commas, all of which must match, e.g.
+// ">=1.2.0 <2.0.0", "^1.4", "~1.4.2" or "1.5.0". An operator may be written
+// apart from its version, as in ">= 1.2.0". Alternatives can be joined with
+// "||".
+func Match(version, constraint string) (bool, error) {
+	v, err := Parse(version)
+	if err != nil {
+		return false, err
+	}
+
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" || constraint == "*" {
+		return true, nil
+	}
+
+	for _, alt := range strings.Split(constraint, "||") {
+		ok, err := matchAll(v, alt)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// operators are the comparator prefixes, longest first.
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+func matchAll(v Version, constraint string) (bool, error) {
+	fields := strings.FieldsFunc(constraint, func(r rune) bool { return r == ' ' || r == ',' })
+	// Join an operator written apart from its version, as in ">= 1.2.0"
+	var comparators []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if isOperator(f) {
+			if i+1 == len(fields) || isOperator(fields[i+1]) {
+				return false, fmt.Errorf("invalid constraint %q: %s needs a version", constraint, f)
+			}
+			i++
+			f += fields[i]
+		}
+		comparators = append(comparators, f)
+	}
+
+	for _, f := range comparators {
+		ok, err := matchOne(v, f)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func isOperator(s string) bool {
+	for _, o := range operators {
+		if s == o {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOne(v Version, comparator string) (bool, error) {
+	op := "="
+	for _, o := range operators {
+		if strings.HasPrefix(comparator, o) {
+			op = o
+			comparator = comparator[len(o):]
+			break
+		}
+	}
+
+	target, err := Parse(comparator)
+	if err != nil {
+		return false, fmt.Errorf("invalid constraint %q: %w", comparator, err)
+	}
+
+	c := Compare(v, target)
+	switch op {
+	case ">=":
+		return c >= 0, nil
+	case "<=":
+		return c <= 0, nil
+	case "!=":
+		return c != 0, nil
+	case ">":
+		return c > 0, nil
+	case "<":
+		return c < 0, nil
+	case "^":
+		// Same left-most non-zero component
+		if c < 0 {
+			return false, nil
+		}
+		if target.Major > 0 {
+			return v.Major == target.Major, nil
+		}
+		if target.Minor > 0 {
+			return v.Major == 0 && v.Minor == target.Minor, nil
+		}
+		return v.Major == 0 && v.Minor == 0 && v.Patch == target.Patch, nil
+	case "~":
+		// Same major.minor
+		return c >= 0 && v.Major == target.Major && v.Minor == target.Minor, nil
+	default:
+		return c == 0, nil
+	}
+}
+
+func comparePrerelease(a, b string) int {
+	// A version without a prerelease has higher precedence
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	pa := strings.Split(a, ".")
+	pb := strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmpInt(na, nb)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(pa[i], pb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmpInt(len(pa), len(pb))
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Version
+		wantErr bool
+	}{
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, false},
+		{"v2.0", Version{Major: 2}, false},
+		{"1", Version{Major: 1}, false},
+		{"1.2.3-beta.1+build.5", Version{1, 2, 3, "beta.1", "build.5"}, false},
+		{"", Version{}, true},
+		{"1.2.3.4", Version{}, true},
+		{"1.x", Version{}, true},
+		{"-1.0.0", Version{}, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+		{"not-a-version", "1.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareStrings(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareStrings(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		version, constraint string
+		want                bool
+	}{
+		// Forms documented in 'releases list --help'
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.1.9", ">=1.2.0 <2.0.0", false},
+		{"1.9.0", "^1.4", true},
+		{"2.0.0", "^1.4", false},
+		{"1.3.0", "^1.4", false},
+		{"1.4.5", "~1.4.2", true},
+		{"1.5.0", "~1.4.2", false},
+		{"1.4.1", "~1.4.2", false},
+		// Operators written apart from their version
+		{"1.5.0", ">= 1.2.0 < 2.0.0", true},
+		{"2.1.0", ">= 1.2.0, < 2.0.0", false},
+		{"1.2.0", "= 1.2.0", true},
+		// Commas, exact versions, inequality and alternatives
+		{"1.5.0", ">=1.2.0,<2.0.0", true},
+		{"1.5.0", "1.5.0", true},
+		{"1.5.1", "1.5.0", false},
+		{"1.5.1", "!=1.5.0", true},
+		{"3.0.0", "^1.0 || ^3.0", true},
+		{"2.0.0", "^1.0 || ^3.0", false},
+		{"0.2.5", "^0.2.1", true},
+		{"0.3.0", "^0.2.1", false},
+		{"0.0.3", "^0.0.3", true},
+		{"0.0.4", "^0.0.3", false},
+		{"9.9.9", "", true},
+		{"9.9.9", "*", true},
+		{"1.0.0-rc.1", "<1.0.0", true},
+	}
+	for _, tt := range tests {
+		got, err := Match(tt.version, tt.constraint)
+		if err != nil {
+			t.Errorf("Match(%q, %q) error: %v", tt.version, tt.constraint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+		}
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	tests := []struct{ version, constraint string }{
+		{"1.0.0", ">="},
+		{"1.0.0", ">= <2.0.0"},
+		{"1.0.0", ">=1.x"},
+		{"nope", ">=1.0.0"},
+	}
+	for _, tt := range tests {
+		if _, err := Match(tt.version, tt.constraint); err == nil {
+			t.Errorf("Match(%q, %q) succeeded, want an error", tt.version, tt.constraint)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package snapshot
+
+import "sort"
+
+// Change is a resource that differs between two snapshots.
+type Change struct {
+	Kind string `json:"kind"`
+	// Op is added, removed or modified.
+	Op     string        `json:"op"`
+	Label  string        `json:"label"`
+	AID    string        `json:"a_id,omitempty"`
+	BID    string        `json:"b_id,omitempty"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is one attribute or relationship that differs. Metadata keys
+// are compared one by one, e.g. "metadata.tier".
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffOptions selects what Diff compares.
+type DiffOptions struct {
+	// Kinds to report; every kind is still matched so relationships can be
+	// compared across accounts.
+	Kinds []string
+	// Ignore lists attributes that are not compared.
+	Ignore []string
+}
+
+// DefaultIgnore are attributes that change on their own and would make
+// every diff noisy: timestamps, validation, check-in and heartbeat
+// bookkeeping, and the events Keygen last sent.
+var DefaultIgnore = []string{
+	"created", "updated",
+	"lastValidated", "lastCheckOut", "lastCheckIn", "nextCheckIn",
+	"lastExpirationEvent", "lastExpiringSoonEvent", "lastCheckInEvent", "lastCheckInSoonEvent",
+	"heartbeatStatus", "lastHeartbeat", "nextHeartbeat", "lastDeathEvent",
+}
+
+// Diff compares b against a. Resources are matched by ID, then by their
+// natural key with relationships mapped from a's IDs to b's, so snapshots
+// of different accounts can be compared.
+func Diff(a, b *Snapshot, opts DiffOptions) []Change {
+	ignore := map[string]bool{}
+	for _, name := range append(append([]string(nil), DefaultIgnore...), opts.Ignore...) {
+		ignore[name] = true
+	}
+	report := map[string]bool{}
+	for _, k := range opts.Kinds {
+		report[k] = true
+	}
+
+	changes := []Change{}
+	// ids maps a's IDs to b's per kind
+	ids := map[string]map[string]string{}
+	for _, kind := range Kinds {
+		ids[kind.Name] = map[string]string{}
+
+		bByID := map[string]Resource{}
+		bByKey := map[string]Resource{}
+		for _, r := range b.Resources[kind.Name] {
+			bByID[r.ID] = r
+			if key := kind.Key(r.Attributes, r.Relationships); key != "" {
+				bByKey[key] = r
+			}
+		}
+
+		matched := map[string]bool{}
+		for _, ra := range a.Resources[kind.Name] {
+			rels := map[string]string{}
+			for _, rel := range kind.Relations {
+				id := ra.Relationships[rel.Name]
+				if mapped, ok := ids[rel.Kind][id]; ok {
+					id = mapped
+				}
+				if id != "" {
+					rels[rel.Name] = id
+				}
+			}
+
+			rb, ok := bByID[ra.ID]
+			if !ok {
+				if key := kind.Key(ra.Attributes, rels); key != "" {
+					rb, ok = bByKey[key]
+				}
+			}
+			if ok && matched[rb.ID] {
+				ok = false
+			}
+			if !ok {
+				if report[kind.Name] {
+					changes = append(changes, Change{Kind: kind.Name, Op: "removed", Label: kind.label(ra), AID: ra.ID})
+				}
+				continue
+			}
+
+			matched[rb.ID] = true
+			ids[kind.Name][ra.ID] = rb.ID
+			if !report[kind.Name] {
+				continue
+			}
+			fields := compareAttributes(ra.Attributes, rb.Attributes, ignore)
+			for _, rel := range kind.Relations {
+				if rels[rel.Name] != rb.Relationships[rel.Name] {
+					fields = append(fields, FieldChange{Field: rel.Name, From: nullable(ra.Relationships[rel.Name]), To: nullable(rb.Relationships[rel.Name])})
+				}
+			}
+			if len(fields) > 0 {
+				changes = append(changes, Change{Kind: kind.Name, Op: "modified", Label: kind.label(ra), AID: ra.ID, BID: rb.ID, Fields: fields})
+			}
+		}
+
+		if !report[kind.Name] {
+			continue
+		}
+		for _, rb := range b.Resources[kind.Name] {
+			if !matched[rb.ID] {
+				changes = append(changes, Change{Kind: kind.Name, Op: "added", Label: kind.label(rb), BID: rb.ID})
+			}
+		}
+	}
+	return changes
+}
+
+func (k Kind) label(r Resource) string {
+	if s := str(r.Attributes, k.Label); s != "" {
+		return s
+	}
+	return r.ID
+}
+
+// compareAttributes lists the attributes that differ, metadata key by key.
+func compareAttributes(a, b map[string]interface{}, ignore map[string]bool) []FieldChange {
+	var fields []FieldChange
+	for _, name := range unionKeys(a, b) {
+		if ignore[name] {
+			continue
+		}
+		if name == "metadata" {
+			ma, _ := a[name].(map[string]interface{})
+			mb, _ := b[name].(map[string]interface{})
+			for _, key := range unionKeys(ma, mb) {
+				if !equal(ma[key], mb[key]) {
+					fields = append(fields, FieldChange{Field: "metadata." + key, From: ma[key], To: mb[key]})
+				}
+			}
+			continue
+		}
+		if !equal(a[name], b[name]) {
+			fields = append(fields, FieldChange{Field: name, From: a[name], To: b[name]})
+		}
+	}
+	return fields
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
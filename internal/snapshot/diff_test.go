@@ -0,0 +1,154 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func snap(resources map[string][]Resource) *Snapshot {
+	return &Snapshot{Format: Format, Version: Version, Resources: resources}
+}
+
+func attrs(kv ...interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		m[kv[i].(string)] = kv[i+1]
+	}
+	return m
+}
+
+func TestDiff(t *testing.T) {
+	all := []string{"products", "policies", "users", "licenses"}
+	a := snap(map[string][]Resource{
+		"products": {{ID: "p1", Attributes: attrs("name", "App")}},
+		"policies": {
+			{ID: "pol1", Attributes: attrs("name", "Standard", "maxMachines", 1.0), Relationships: map[string]string{"product": "p1"}},
+			{ID: "pol2", Attributes: attrs("name", "Pro"), Relationships: map[string]string{"product": "p1"}},
+		},
+		"licenses": {{ID: "lic1", Attributes: attrs("key", "KEY-1", "metadata", attrs("tier", "gold")), Relationships: map[string]string{"policy": "pol1"}}},
+	})
+
+	tests := []struct {
+		name string
+		a    *Snapshot // the base snapshot when nil
+		b    *Snapshot
+		opts DiffOptions
+		want []Change
+	}{
+		{
+			name: "same account",
+			b:    a,
+			opts: DiffOptions{Kinds: all},
+			want: []Change{},
+		},
+		{
+			name: "other account matched by natural key",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "P1", Attributes: attrs("name", "App")}},
+				"policies": {
+					{ID: "POL1", Attributes: attrs("name", "Standard", "maxMachines", 1.0), Relationships: map[string]string{"product": "P1"}},
+					{ID: "POL2", Attributes: attrs("name", "Pro"), Relationships: map[string]string{"product": "P1"}},
+				},
+				"licenses": {{ID: "LIC1", Attributes: attrs("key", "KEY-1", "metadata", attrs("tier", "gold")), Relationships: map[string]string{"policy": "POL1"}}},
+			}),
+			opts: DiffOptions{Kinds: all},
+			want: []Change{},
+		},
+		{
+			name: "modified attributes and metadata keys",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "p1", Attributes: attrs("name", "App")}},
+				"policies": {
+					{ID: "pol1", Attributes: attrs("name", "Standard", "maxMachines", 5.0, "updated", "2026-10-18T00:00:00Z", "lastValidated", "now"), Relationships: map[string]string{"product": "p1"}},
+					{ID: "pol2", Attributes: attrs("name", "Pro"), Relationships: map[string]string{"product": "p1"}},
+				},
+				"licenses": {{ID: "lic1", Attributes: attrs("key", "KEY-1", "metadata", attrs("tier", "silver", "seats", 3.0)), Relationships: map[string]string{"policy": "pol1"}}},
+			}),
+			opts: DiffOptions{Kinds: all},
+			want: []Change{
+				{Kind: "policies", Op: "modified", Label: "Standard", AID: "pol1", BID: "pol1", Fields: []FieldChange{{"maxMachines", 1.0, 5.0}}},
+				{Kind: "licenses", Op: "modified", Label: "KEY-1", AID: "lic1", BID: "lic1", Fields: []FieldChange{
+					{"metadata.seats", nil, 3.0},
+					{"metadata.tier", "gold", "silver"},
+				}},
+			},
+		},
+		{
+			name: "user renamed",
+			a:    snap(map[string][]Resource{"users": {{ID: "u1", Attributes: attrs("email", "ada@example.com", "lastName", "Lovelace", "lastValidated", "then")}}}),
+			b:    snap(map[string][]Resource{"users": {{ID: "u1", Attributes: attrs("email", "ada@example.com", "lastName", "King", "lastValidated", "now")}}}),
+			opts: DiffOptions{Kinds: all},
+			want: []Change{
+				{Kind: "users", Op: "modified", Label: "ada@example.com", AID: "u1", BID: "u1", Fields: []FieldChange{{"lastName", "Lovelace", "King"}}},
+			},
+		},
+		{
+			name: "ignored attribute",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "p1", Attributes: attrs("name", "App")}},
+				"policies": {
+					{ID: "pol1", Attributes: attrs("name", "Standard", "maxMachines", 5.0), Relationships: map[string]string{"product": "p1"}},
+					{ID: "pol2", Attributes: attrs("name", "Pro"), Relationships: map[string]string{"product": "p1"}},
+				},
+				"licenses": a.Resources["licenses"],
+			}),
+			opts: DiffOptions{Kinds: all, Ignore: []string{"maxMachines"}},
+			want: []Change{},
+		},
+		{
+			name: "moved to another policy",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "P1", Attributes: attrs("name", "App")}},
+				"policies": {
+					{ID: "POL1", Attributes: attrs("name", "Standard", "maxMachines", 1.0), Relationships: map[string]string{"product": "P1"}},
+					{ID: "POL2", Attributes: attrs("name", "Pro"), Relationships: map[string]string{"product": "P1"}},
+				},
+				"licenses": {{ID: "LIC1", Attributes: attrs("key", "KEY-1", "metadata", attrs("tier", "gold")), Relationships: map[string]string{"policy": "POL2"}}},
+			}),
+			opts: DiffOptions{Kinds: all},
+			want: []Change{
+				{Kind: "licenses", Op: "modified", Label: "KEY-1", AID: "lic1", BID: "LIC1", Fields: []FieldChange{{"policy", "pol1", "POL2"}}},
+			},
+		},
+		{
+			name: "added and removed",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "p1", Attributes: attrs("name", "App")}},
+				"policies": {{ID: "pol1", Attributes: attrs("name", "Standard", "maxMachines", 1.0), Relationships: map[string]string{"product": "p1"}}},
+				"users":    {{ID: "u1", Attributes: attrs("email", "ada@example.com")}},
+				"licenses": {{ID: "lic2", Attributes: attrs("key", "KEY-2"), Relationships: map[string]string{"policy": "pol1"}}},
+			}),
+			opts: DiffOptions{Kinds: all},
+			want: []Change{
+				{Kind: "policies", Op: "removed", Label: "Pro", AID: "pol2"},
+				{Kind: "users", Op: "added", Label: "ada@example.com", BID: "u1"},
+				{Kind: "licenses", Op: "removed", Label: "KEY-1", AID: "lic1"},
+				{Kind: "licenses", Op: "added", Label: "KEY-2", BID: "lic2"},
+			},
+		},
+		{
+			name: "only the selected kinds are reported",
+			b: snap(map[string][]Resource{
+				"products": {{ID: "P1", Attributes: attrs("name", "App", "url", "https://example.com")}},
+				"policies": {
+					{ID: "POL1", Attributes: attrs("name", "Standard", "maxMachines", 1.0), Relationships: map[string]string{"product": "P1"}},
+				},
+				"licenses": {{ID: "LIC1", Attributes: attrs("key", "KEY-1", "metadata", attrs("tier", "gold")), Relationships: map[string]string{"policy": "POL1"}}},
+			}),
+			opts: DiffOptions{Kinds: []string{"licenses"}},
+			want: []Change{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := a
+			if tt.a != nil {
+				base = tt.a
+			}
+			got := Diff(base, tt.b, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Mapping records which target resource each source resource became
+// during a migration, so an interrupted run can resume where it stopped.
+type Mapping struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	FromAccount string `json:"from_account"`
+	ToAccount   string `json:"to_account"`
+	Updated     string `json:"updated"`
+	// IDs maps source IDs to target IDs per kind.
+	IDs map[string]map[string]string `json:"ids"`
+}
+
+// LoadMapping reads a mapping file. A missing file yields an empty mapping.
+func LoadMapping(path string) (*Mapping, error) {
+	m := &Mapping{IDs: map[string]map[string]string{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if m.IDs == nil {
+		m.IDs = map[string]map[string]string{}
+	}
+	return m, nil
+}
+
+// Len returns the number of mapped resources.
+func (m *Mapping) Len() int {
+	n := 0
+	for _, ids := range m.IDs {
+		n += len(ids)
+	}
+	return n
+}
+
+// Save writes the mapping to path, replacing the file atomically so a
+// crash mid-write never leaves a truncated mapping behind.
+func (m *Mapping) Save(path string) error {
+	m.Updated = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
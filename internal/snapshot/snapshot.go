@@ -0,0 +1,265 @@
+// Package snapshot defines the versioned file format of 'keygen snapshot'
+// and the resource kinds it covers.
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Format identifies snapshot files.
+const Format = "keygen-snapshot"
+
+// Version is the snapshot format written by this build. Readers accept
+// this version and older ones.
+const Version = 1
+
+// Snapshot is a point-in-time dump of an account.
+type Snapshot struct {
+	Format    string `json:"format"`
+	Version   int    `json:"version"`
+	Created   string `json:"created"`
+	AccountID string `json:"account_id"`
+	BaseURL   string `json:"base_url"`
+	// Resources holds every resource by kind name, e.g. "licenses".
+	Resources map[string][]Resource `json:"resources"`
+}
+
+// Resource is one resource with its attributes and to-one relationships,
+// which map a relationship name to the related resource's ID.
+type Resource struct {
+	ID            string                 `json:"id"`
+	Attributes    map[string]interface{} `json:"attributes"`
+	Relationships map[string]string      `json:"relationships,omitempty"`
+}
+
+// Counts returns the number of resources of each kind.
+func (s *Snapshot) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, k := range Kinds {
+		counts[k.Name] = len(s.Resources[k.Name])
+	}
+	return counts
+}
+
+// Relation is a to-one relationship that restore maps to the target
+// account's IDs.
+type Relation struct {
+	Name string
+	Kind string
+}
+
+// Kind describes one resource collection.
+type Kind struct {
+	Name string
+	// Label is the attribute that names a resource to people, e.g. "email".
+	Label string
+	// Attributes are sent when a resource is created.
+	Attributes []string
+	// CreateOnly attributes can't be changed once a resource exists.
+	CreateOnly []string
+	Relations  []Relation
+	// Key identifies the same resource across accounts, from its attributes
+	// and its relationships already mapped to the target account.
+	Key func(attrs map[string]interface{}, rels map[string]string) string
+}
+
+// Path is the API collection path of the kind.
+func (k Kind) Path() string {
+	return "/" + k.Name
+}
+
+// Kinds lists the snapshot kinds in dependency order: every relation
+// points to an earlier kind.
+var Kinds = []Kind{
+	{
+		Name:       "products",
+		Label:      "name",
+		Attributes: []string{"name", "code", "url", "distributionStrategy", "platforms", "permissions", "metadata"},
+		Key:        func(a map[string]interface{}, _ map[string]string) string { return str(a, "name") },
+	},
+	{
+		Name:  "policies",
+		Label: "name",
+		Attributes: []string{
+			"name", "duration", "strict", "floating", "scheme", "encrypted", "protected", "usePool",
+			"maxMachines", "maxProcesses", "maxUsers", "maxCores", "maxUses",
+			"requireHeartbeat", "heartbeatDuration", "heartbeatCullStrategy", "heartbeatResurrectionStrategy", "heartbeatBasis",
+			"requireCheckIn", "checkInInterval", "checkInIntervalCount",
+			"requireProductScope", "requirePolicyScope", "requireMachineScope", "requireFingerprintScope",
+			"requireComponentsScope", "requireUserScope", "requireChecksumScope", "requireVersionScope",
+			"machineUniquenessStrategy", "machineMatchingStrategy", "componentUniquenessStrategy", "componentMatchingStrategy",
+			"expirationStrategy", "expirationBasis", "renewalBasis", "transferStrategy", "authenticationStrategy",
+			"machineLeasingStrategy", "processLeasingStrategy", "overageStrategy", "metadata",
+		},
+		CreateOnly: []string{"scheme", "encrypted", "usePool"},
+		Relations:  []Relation{{"product", "products"}},
+		Key: func(a map[string]interface{}, r map[string]string) string {
+			return r["product"] + "/" + str(a, "name")
+		},
+	},
+	{
+		Name:       "users",
+		Label:      "email",
+		Attributes: []string{"email", "firstName", "lastName", "role", "permissions", "metadata"},
+		Key:        func(a map[string]interface{}, _ map[string]string) string { return strings.ToLower(str(a, "email")) },
+	},
+	{
+		Name:  "licenses",
+		Label: "key",
+		Attributes: []string{
+			"name", "key", "expiry", "protected", "suspended", "permissions",
+			"maxMachines", "maxProcesses", "maxUsers", "maxCores", "maxUses", "metadata",
+		},
+		CreateOnly: []string{"key"},
+		Relations:  []Relation{{"policy", "policies"}, {"owner", "users"}},
+		Key:        func(a map[string]interface{}, _ map[string]string) string { return str(a, "key") },
+	},
+	{
+		Name:       "machines",
+		Label:      "fingerprint",
+		Attributes: []string{"fingerprint", "name", "platform", "hostname", "ip", "cores", "maxProcesses", "metadata"},
+		CreateOnly: []string{"fingerprint"},
+		Relations:  []Relation{{"license", "licenses"}},
+		Key: func(a map[string]interface{}, r map[string]string) string {
+			return r["license"] + "/" + str(a, "fingerprint")
+		},
+	},
+	{
+		Name:       "components",
+		Label:      "fingerprint",
+		Attributes: []string{"fingerprint", "name", "metadata"},
+		CreateOnly: []string{"fingerprint"},
+		Relations:  []Relation{{"machine", "machines"}},
+		Key: func(a map[string]interface{}, r map[string]string) string {
+			return r["machine"] + "/" + str(a, "fingerprint")
+		},
+	},
+}
+
+// CreateAttributes returns the attributes to send when creating r. Unset
+// attributes are left for the server to default.
+func (k Kind) CreateAttributes(r Resource) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	for _, name := range k.Attributes {
+		if v, ok := r.Attributes[name]; ok && v != nil {
+			attrs[name] = v
+		}
+	}
+	return attrs
+}
+
+// Changes returns the updatable attributes of want that differ from have.
+func (k Kind) Changes(want, have map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for _, name := range k.Attributes {
+		if contains(k.CreateOnly, name) {
+			continue
+		}
+		w, ok := want[name]
+		if !ok {
+			continue
+		}
+		if !equal(w, have[name]) {
+			changes[name] = w
+		}
+	}
+	return changes
+}
+
+// Lookup returns the kind called name.
+func Lookup(name string) (Kind, bool) {
+	for _, k := range Kinds {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// Write encodes s to path, gzip-compressed when path ends in .gz. The file
+// is only readable by its owner since it contains license keys.
+func Write(path string, s *Snapshot) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(s)
+	if gz != nil {
+		if cerr := gz.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read decodes a snapshot file, gzip-compressed or not.
+func Read(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var r io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("parsing snapshot: %w", err)
+	}
+	if s.Format != Format {
+		return nil, fmt.Errorf("%s is not a keygen snapshot", path)
+	}
+	if s.Version < 1 || s.Version > Version {
+		return nil, fmt.Errorf("snapshot version %d is not supported (this build reads up to version %d)", s.Version, Version)
+	}
+	return &s, nil
+}
+
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares JSON values, treating numbers by value.
+func equal(a, b interface{}) bool {
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return bytes.Equal(ja, jb)
+}
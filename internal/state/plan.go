@@ -0,0 +1,378 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+)
+
+// Live is the current state of an account.
+type Live struct {
+	Resources map[string][]snapshot.Resource
+	Users     []snapshot.Resource
+	// Attached maps a policy or license ID to the codes of the entitlements
+	// attached to it directly, not through its policy.
+	Attached map[string][]string
+}
+
+// Ref points to a related resource: an existing one by ID, or one the plan
+// creates by its address.
+type Ref struct {
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+	ID      string `json:"id,omitempty"`
+}
+
+// Diff is one attribute or relationship a change sets.
+type Diff struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Change creates or updates one resource.
+type Change struct {
+	Kind string `json:"kind"`
+	// Action is create or update.
+	Action string `json:"action"`
+	// Address names the resource, e.g. "App/Pro" for a policy.
+	Address string `json:"address"`
+	// ID is the existing resource's ID for updates.
+	ID string `json:"id,omitempty"`
+	// Attributes are sent to Keygen: all declared ones on create, the
+	// changed ones on update.
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// Relationships are set on create, or changed on update.
+	Relationships map[string]Ref `json:"relationships,omitempty"`
+	Attach        []Ref          `json:"attach,omitempty"`
+	Detach        []Ref          `json:"detach,omitempty"`
+	Diffs         []Diff         `json:"diffs"`
+}
+
+// Plan is the list of changes in the order they must be applied.
+type Plan struct {
+	Changes   []Change `json:"changes"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Counts returns the number of creates and updates.
+func (p *Plan) Counts() (create, update int) {
+	for _, c := range p.Changes {
+		if c.Action == "create" {
+			create++
+		} else {
+			update++
+		}
+	}
+	return create, update
+}
+
+// planner resolves references against live and declared resources.
+type planner struct {
+	live *Live
+	// refs maps "kind\x00name" to the resource it names, for every name a
+	// resource can be referenced by.
+	refs map[string]Ref
+	// addresses maps live IDs to addresses for display
+	addresses map[string]string
+	// granted maps policy addresses to the entitlement codes the policy
+	// will have, which its licenses don't need attached directly.
+	granted map[string][]string
+}
+
+// NewPlan compares the declared resources with the live account. Only
+// declared attributes, relationships and entitlement lists are managed;
+// undeclared resources are left alone.
+func NewPlan(declared map[string][]Resource, live *Live) (*Plan, error) {
+	p := &planner{live: live, refs: map[string]Ref{}, addresses: map[string]string{}, granted: map[string][]string{}}
+	for _, u := range live.Users {
+		ref := Ref{Kind: "users", Address: str(u.Attributes, "email"), ID: u.ID}
+		p.add(ref, u.ID, strings.ToLower(ref.Address))
+	}
+
+	plan := &Plan{Changes: []Change{}}
+	for i := range Kinds {
+		kind := &Kinds[i]
+		liveByAddress := map[string]snapshot.Resource{}
+		for _, r := range live.Resources[kind.Name] {
+			address := p.liveAddress(kind, r)
+			liveByAddress[address] = r
+			ref := Ref{Kind: kind.Name, Address: address, ID: r.ID}
+			p.addresses[r.ID] = address
+			if kind.Name == "policies" {
+				p.granted[address] = live.Attached[r.ID]
+			}
+			p.add(ref, r.ID, address, str(r.Attributes, "code"), str(r.Attributes, "name"))
+		}
+
+		for _, d := range declared[kind.Name] {
+			change, err := p.plan(kind, d, liveByAddress)
+			if err != nil {
+				return nil, err
+			}
+			if change == nil {
+				plan.Unchanged++
+				continue
+			}
+			plan.Changes = append(plan.Changes, *change)
+		}
+	}
+	return plan, nil
+}
+
+func (p *planner) plan(kind *Kind, d Resource, liveByAddress map[string]snapshot.Resource) (*Change, error) {
+	rels := map[string]Ref{}
+	for _, name := range kind.Refs {
+		v, ok := d.Refs[name]
+		if !ok {
+			continue
+		}
+		ref, err := p.resolve(refKind(name), v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", d.Path, name, err)
+		}
+		rels[name] = ref
+	}
+	address := d.ID
+	if kind.Name == "policies" {
+		address = rels["product"].Address + "/" + d.ID
+	}
+
+	if kind.Name == "policies" && d.Entitlements != nil {
+		p.granted[address] = d.Entitlements
+	}
+	var attach []Ref
+	for _, code := range d.Entitlements {
+		if kind.Name == "licenses" && contains(p.granted[p.licensePolicy(rels, liveByAddress[address])], code) {
+			continue
+		}
+		ref, err := p.resolve("entitlements", code)
+		if err != nil {
+			return nil, fmt.Errorf("%s.entitlements: %w", d.Path, err)
+		}
+		attach = append(attach, ref)
+	}
+
+	live, exists := liveByAddress[address]
+	if !exists {
+		if kind.Name == "licenses" && rels["policy"].Address == "" {
+			return nil, fmt.Errorf("%s: policy is required to create license %s", d.Path, d.ID)
+		}
+		ref := Ref{Kind: kind.Name, Address: address}
+		// Declared resources can be referenced before they exist
+		p.add(ref, "", address, str(d.Attributes, "code"), str(d.Attributes, "name"))
+
+		change := &Change{Kind: kind.Name, Action: "create", Address: address, Attributes: d.Attributes, Relationships: rels, Attach: attach}
+		for _, name := range sortedKeys(d.Attributes) {
+			change.Diffs = append(change.Diffs, Diff{Field: SnakeCase(name), To: d.Attributes[name]})
+		}
+		for _, name := range kind.Refs {
+			if ref, ok := rels[name]; ok {
+				change.Diffs = append(change.Diffs, Diff{Field: name, To: ref.Address})
+			}
+		}
+		for _, ref := range attach {
+			change.Diffs = append(change.Diffs, Diff{Field: "entitlements", To: ref.Address})
+		}
+		if len(change.Relationships) == 0 {
+			change.Relationships = nil
+		}
+		return change, nil
+	}
+
+	change := &Change{Kind: kind.Name, Action: "update", Address: address, ID: live.ID, Attributes: map[string]interface{}{}, Relationships: map[string]Ref{}}
+	for _, name := range sortedKeys(d.Attributes) {
+		want, have := d.Attributes[name], live.Attributes[name]
+		if equal(want, have) {
+			continue
+		}
+		if contains(kind.CreateOnly, name) {
+			return nil, fmt.Errorf("%s: %s can't be changed on an existing %s (%s -> %s)", d.Path, SnakeCase(name), kind.Singular, show(have), show(want))
+		}
+		change.Attributes[name] = want
+		change.Diffs = append(change.Diffs, Diff{Field: SnakeCase(name), From: have, To: want})
+	}
+	for _, name := range kind.Refs {
+		ref, ok := rels[name]
+		if !ok {
+			continue
+		}
+		current := live.Relationships[name]
+		if ref.ID != "" && ref.ID == current {
+			continue
+		}
+		if name == "product" {
+			return nil, fmt.Errorf("%s: a policy can't be moved to another product", d.Path)
+		}
+		change.Relationships[name] = ref
+		from := p.addresses[current]
+		if from == "" && current != "" {
+			from = current
+		}
+		change.Diffs = append(change.Diffs, Diff{Field: name, From: nullable(from), To: ref.Address})
+	}
+
+	if d.Entitlements != nil {
+		current := map[string]bool{}
+		for _, code := range p.live.Attached[live.ID] {
+			current[code] = true
+		}
+		wanted := map[string]bool{}
+		var keep []Ref
+		for _, ref := range attach {
+			wanted[ref.Address] = true
+			if !current[ref.Address] {
+				keep = append(keep, ref)
+				change.Diffs = append(change.Diffs, Diff{Field: "entitlements", To: ref.Address})
+			}
+		}
+		change.Attach = keep
+		for _, code := range p.live.Attached[live.ID] {
+			if !wanted[code] {
+				ref, err := p.resolve("entitlements", code)
+				if err != nil {
+					return nil, fmt.Errorf("%s.entitlements: %w", d.Path, err)
+				}
+				change.Detach = append(change.Detach, ref)
+				change.Diffs = append(change.Diffs, Diff{Field: "entitlements", From: code})
+			}
+		}
+	}
+
+	if len(change.Diffs) == 0 {
+		return nil, nil
+	}
+	if len(change.Relationships) == 0 {
+		change.Relationships = nil
+	}
+	return change, nil
+}
+
+// licensePolicy returns the address of the policy a license will be on.
+func (p *planner) licensePolicy(rels map[string]Ref, live snapshot.Resource) string {
+	if ref, ok := rels["policy"]; ok {
+		return ref.Address
+	}
+	return p.addresses[live.Relationships["policy"]]
+}
+
+// liveAddress names a live resource the way the plan does.
+func (p *planner) liveAddress(kind *Kind, r snapshot.Resource) string {
+	id := str(r.Attributes, kind.Identity)
+	if kind.Name == "policies" {
+		product := p.addresses[r.Relationships["product"]]
+		if product == "" {
+			product = r.Relationships["product"]
+		}
+		return product + "/" + id
+	}
+	return id
+}
+
+// add registers the names a resource can be referenced by. Names shared by
+// several resources are marked ambiguous.
+func (p *planner) add(ref Ref, names ...string) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		key := ref.Kind + "\x00" + name
+		if prev, ok := p.refs[key]; ok && prev.Address != ref.Address {
+			p.refs[key] = Ref{Kind: ref.Kind, Address: "\x00ambiguous"}
+			continue
+		}
+		p.refs[key] = ref
+	}
+}
+
+// resolve finds a resource by ID, address, code, name or email.
+func (p *planner) resolve(kind, name string) (Ref, error) {
+	lookup := name
+	if kind == "users" {
+		lookup = strings.ToLower(name)
+	}
+	ref, ok := p.refs[kind+"\x00"+lookup]
+	if !ok {
+		return ref, fmt.Errorf("%s %q not found", singular(kind), name)
+	}
+	if ref.Address == "\x00ambiguous" {
+		return ref, fmt.Errorf("%s %q is ambiguous; use its ID or product/name", singular(kind), name)
+	}
+	return ref, nil
+}
+
+// singular names one resource of a kind, e.g. "policy" for "policies".
+func singular(kind string) string {
+	for _, k := range Kinds {
+		if k.Name == kind {
+			return k.Singular
+		}
+	}
+	return strings.TrimSuffix(kind, "s")
+}
+
+func refKind(field string) string {
+	switch field {
+	case "product":
+		return "products"
+	case "policy":
+		return "policies"
+	}
+	return "users"
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// equal compares a declared value with a live one as JSON, treating
+// timestamps by instant so 2027-01-01T00:00:00Z matches
+// 2027-01-01T00:00:00.000Z.
+func equal(want, have interface{}) bool {
+	if ws, ok := want.(string); ok {
+		if hs, ok := have.(string); ok {
+			wt, werr := time.Parse(time.RFC3339, ws)
+			ht, herr := time.Parse(time.RFC3339, hs)
+			if werr == nil && herr == nil {
+				return wt.Equal(ht)
+			}
+		}
+	}
+	jw, _ := json.Marshal(want)
+	jh, _ := json.Marshal(have)
+	return bytes.Equal(jw, jh)
+}
+
+func str(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// show formats a value for error messages.
+func show(v interface{}) string {
+	if v == nil {
+		return "none"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,154 @@
+package state
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+)
+
+// testLive has two products with a "Pro" policy each, a license on
+// App/Basic with API attached directly, and SSO attached to App/Pro.
+func testLive() *Live {
+	res := func(id string, rels map[string]string, kv ...string) snapshot.Resource {
+		attrs := map[string]interface{}{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			attrs[kv[i]] = kv[i+1]
+		}
+		return snapshot.Resource{ID: id, Attributes: attrs, Relationships: rels}
+	}
+	return &Live{
+		Resources: map[string][]snapshot.Resource{
+			"products":     {res("p1", nil, "name", "App", "code", "app"), res("p2", nil, "name", "Tool")},
+			"entitlements": {res("e1", nil, "code", "SSO", "name", "SSO"), res("e2", nil, "code", "API", "name", "API")},
+			"policies": {
+				res("pol1", map[string]string{"product": "p1"}, "name", "Pro", "scheme", "ED25519_SIGN"),
+				res("pol2", map[string]string{"product": "p2"}, "name", "Pro"),
+				res("pol3", map[string]string{"product": "p1"}, "name", "Basic"),
+			},
+			"licenses": {res("lic1", map[string]string{"policy": "pol3"}, "key", "KEY-1", "expiry", "2027-01-01T00:00:00.000Z")},
+		},
+		Users:    []snapshot.Resource{res("u1", nil, "email", "Ada@Example.com")},
+		Attached: map[string][]string{"pol1": {"SSO"}, "lic1": {"API"}},
+	}
+}
+
+// summarize renders each change as "action kind address: field from -> to, ...".
+func summarize(p *Plan) []string {
+	lines := []string{}
+	for _, c := range p.Changes {
+		var diffs []string
+		for _, d := range c.Diffs {
+			diffs = append(diffs, d.Field+" "+show(d.From)+" -> "+show(d.To))
+		}
+		lines = append(lines, c.Action+" "+c.Kind+" "+c.Address+": "+strings.Join(diffs, ", "))
+	}
+	return lines
+}
+
+func TestNewPlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		want      []string
+		unchanged int
+		wantErr   string
+	}{
+		{
+			name: "in sync",
+			doc: `
+products:
+  - {name: App, code: app}
+licenses:
+  - {key: KEY-1, expiry: 2027-01-01T00:00:00Z, policy: App/Basic, entitlements: [API]}
+`,
+			want:      []string{},
+			unchanged: 2,
+		},
+		{
+			name: "creates referencing declared resources",
+			doc: `
+products:
+  - name: Suite
+entitlements:
+  - {code: EXPORT, name: Export}
+policies:
+  - {name: Std, product: Suite, entitlements: [EXPORT, SSO]}
+licenses:
+  - {key: KEY-2, policy: Suite/Std, owner: ada@example.com, entitlements: [EXPORT]}
+`,
+			want: []string{
+				`create products Suite: name none -> "Suite"`,
+				`create entitlements EXPORT: code none -> "EXPORT", name none -> "Export"`,
+				`create policies Suite/Std: name none -> "Std", product none -> "Suite", entitlements none -> "EXPORT", entitlements none -> "SSO"`,
+				// EXPORT comes with the policy
+				`create licenses KEY-2: key none -> "KEY-2", policy none -> "Suite/Std", owner none -> "Ada@Example.com"`,
+			},
+		},
+		{
+			name: "updates attributes, policy and entitlements",
+			doc: `
+licenses:
+  - {key: KEY-1, max_machines: 3, policy: pol1, entitlements: [SSO]}
+`,
+			want: []string{
+				`update licenses KEY-1: max_machines none -> 3, policy "App/Basic" -> "App/Pro", entitlements "API" -> none`,
+			},
+		},
+		{
+			name: "policy by product and name",
+			doc:  "policies:\n  - {name: Pro, product: Tool, max_uses: 10}\n",
+			want: []string{`update policies Tool/Pro: max_uses none -> 10`},
+		},
+		{
+			name:    "create-only attribute",
+			doc:     "policies:\n  - {name: Pro, product: App, scheme: RSA_2048_PKCS1_SIGN_V2}\n",
+			wantErr: `policies[0]: scheme can't be changed on an existing policy ("ED25519_SIGN" -> "RSA_2048_PKCS1_SIGN_V2")`,
+		},
+		{
+			name:    "ambiguous policy name",
+			doc:     "licenses:\n  - {key: KEY-1, policy: Pro}\n",
+			wantErr: `licenses[0].policy: policy "Pro" is ambiguous`,
+		},
+		{
+			name:    "unknown policy",
+			doc:     "licenses:\n  - {key: KEY-1, policy: Gold}\n",
+			wantErr: `licenses[0].policy: policy "Gold" not found`,
+		},
+		{
+			name:    "unknown entitlement",
+			doc:     "licenses:\n  - {key: KEY-1, entitlements: [AUDIT]}\n",
+			wantErr: `licenses[0].entitlements: entitlement "AUDIT" not found`,
+		},
+		{
+			name:    "new license without a policy",
+			doc:     "licenses:\n  - key: KEY-9\n",
+			wantErr: "licenses[0]: policy is required to create license KEY-9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			declared, err := Parse([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := NewPlan(declared, testLive())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewPlan error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPlan: %v", err)
+			}
+			if got := summarize(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if plan.Unchanged != tt.unchanged {
+				t.Errorf("unchanged = %d, want %d", plan.Unchanged, tt.unchanged)
+			}
+		})
+	}
+}
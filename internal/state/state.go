@@ -0,0 +1,227 @@
+// Package state reads desired-state files for 'keygen apply' and plans the
+// changes that bring an account in line with them.
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/productivityenthusiast/keygen-cli/internal/snapshot"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the state file version this build understands.
+const Version = 1
+
+// File is a desired-state file. Each resource is a map of snake_case
+// fields; see Kinds for the fields each kind accepts.
+type File struct {
+	Version      int                      `yaml:"version"`
+	Products     []map[string]interface{} `yaml:"products"`
+	Entitlements []map[string]interface{} `yaml:"entitlements"`
+	Policies     []map[string]interface{} `yaml:"policies"`
+	Licenses     []map[string]interface{} `yaml:"licenses"`
+}
+
+// Kind describes a resource kind a state file can declare.
+type Kind struct {
+	// Name is the collection name, e.g. "policies".
+	Name string
+	// Singular names one resource in plans, e.g. "policy".
+	Singular string
+	// Identity is the field that identifies a resource; policies are
+	// identified by it within their product.
+	Identity string
+	// Attributes are the Keygen attributes that can be declared, in
+	// camelCase; the file uses their snake_case form.
+	Attributes []string
+	// CreateOnly attributes can't be changed once a resource exists.
+	CreateOnly []string
+	// Refs are fields naming related resources: product, policy or owner.
+	Refs []string
+	// Entitlements is set when the kind accepts an entitlements list.
+	Entitlements bool
+}
+
+// Kinds lists the declarable kinds in the order they are applied.
+var Kinds = []Kind{
+	fromSnapshot("products", "product", "name", nil, false),
+	{
+		Name:       "entitlements",
+		Singular:   "entitlement",
+		Identity:   "code",
+		Attributes: []string{"name", "code", "metadata"},
+	},
+	fromSnapshot("policies", "policy", "name", []string{"product"}, true),
+	fromSnapshot("licenses", "license", "key", []string{"policy", "owner"}, true),
+}
+
+func fromSnapshot(name, singular, identity string, refs []string, entitlements bool) Kind {
+	sk, _ := snapshot.Lookup(name)
+	return Kind{
+		Name:         name,
+		Singular:     singular,
+		Identity:     identity,
+		Attributes:   sk.Attributes,
+		CreateOnly:   sk.CreateOnly,
+		Refs:         refs,
+		Entitlements: entitlements,
+	}
+}
+
+// Resource is one declared resource, normalized to Keygen attribute names.
+type Resource struct {
+	Kind *Kind
+	// Path locates the resource in the file, e.g. "licenses[2]".
+	Path string
+	// ID is the identity value, e.g. the license key.
+	ID         string
+	Attributes map[string]interface{}
+	// Refs holds the declared product, policy or owner references.
+	Refs map[string]string
+	// Entitlements are entitlement codes; nil when not declared, which
+	// leaves attached entitlements alone.
+	Entitlements []string
+}
+
+// Load reads and validates a state file.
+func Load(path string) (map[string][]Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse validates a state document and returns its resources by kind.
+func Parse(data []byte) (map[string][]Resource, error) {
+	var f File
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parsing state: %w", err)
+	}
+	if f.Version != 0 && f.Version != Version {
+		return nil, fmt.Errorf("state version %d is not supported (want %d)", f.Version, Version)
+	}
+
+	declared := map[string][]map[string]interface{}{
+		"products":     f.Products,
+		"entitlements": f.Entitlements,
+		"policies":     f.Policies,
+		"licenses":     f.Licenses,
+	}
+	resources := map[string][]Resource{}
+	for i := range Kinds {
+		kind := &Kinds[i]
+		seen := map[string]string{}
+		for j, fields := range declared[kind.Name] {
+			r, err := kind.parse(fmt.Sprintf("%s[%d]", kind.Name, j), fields)
+			if err != nil {
+				return nil, err
+			}
+			identity := r.ID
+			if kind.Name == "policies" {
+				identity = r.Refs["product"] + "/" + r.ID
+			}
+			if prev, ok := seen[identity]; ok {
+				return nil, fmt.Errorf("%s: duplicate %s %q, also declared at %s", r.Path, kind.Singular, identity, prev)
+			}
+			seen[identity] = r.Path
+			resources[kind.Name] = append(resources[kind.Name], r)
+		}
+	}
+	return resources, nil
+}
+
+func (k *Kind) parse(path string, fields map[string]interface{}) (Resource, error) {
+	r := Resource{Kind: k, Path: path, Attributes: map[string]interface{}{}, Refs: map[string]string{}}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := fields[name]
+		switch {
+		case contains(k.Refs, name):
+			s, ok := value.(string)
+			if !ok || s == "" {
+				return r, fmt.Errorf("%s.%s: must be a name, email or ID", path, name)
+			}
+			r.Refs[name] = s
+		case name == "entitlements" && k.Entitlements:
+			list, ok := value.([]interface{})
+			if !ok && value != nil {
+				return r, fmt.Errorf("%s.entitlements: must be a list of entitlement codes", path)
+			}
+			r.Entitlements = []string{}
+			for _, v := range list {
+				code, ok := v.(string)
+				if !ok || code == "" {
+					return r, fmt.Errorf("%s.entitlements: must be a list of entitlement codes", path)
+				}
+				r.Entitlements = append(r.Entitlements, code)
+			}
+		default:
+			attr := camelCase(name)
+			if !contains(k.Attributes, attr) {
+				return r, fmt.Errorf("%s: unknown field %q for a %s", path, name, k.Singular)
+			}
+			if t, ok := value.(time.Time); ok {
+				value = t.UTC().Format(time.RFC3339)
+			}
+			r.Attributes[attr] = value
+		}
+	}
+
+	id, _ := r.Attributes[k.Identity].(string)
+	if id == "" {
+		return r, fmt.Errorf("%s: %s is required", path, k.Identity)
+	}
+	r.ID = id
+	if k.Name == "policies" && r.Refs["product"] == "" {
+		return r, fmt.Errorf("%s: product is required", path)
+	}
+	return r, nil
+}
+
+// camelCase turns a snake_case field into a Keygen attribute name.
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// SnakeCase turns a Keygen attribute name into its state file field.
+func SnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
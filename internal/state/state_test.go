@@ -0,0 +1,146 @@
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    map[string][]string // kind -> "ID attributes refs entitlements"
+		wantErr string
+	}{
+		{
+			name: "every kind",
+			doc: `
+version: 1
+products:
+  - name: App
+    distribution_strategy: LICENSED
+entitlements:
+  - code: SSO
+    name: Single sign-on
+policies:
+  - name: Pro
+    product: App
+    max_machines: 3
+    entitlements: [SSO]
+licenses:
+  - key: KEY-1
+    policy: App/Pro
+    owner: ada@example.com
+    expiry: 2027-01-01T00:00:00Z
+    metadata: {seats: 5}
+    entitlements: []
+`,
+			want: map[string][]string{
+				"products":     {"App map[distributionStrategy:LICENSED name:App] map[] []"},
+				"entitlements": {"SSO map[code:SSO name:Single sign-on] map[] []"},
+				"policies":     {"Pro map[maxMachines:3 name:Pro] map[product:App] [SSO]"},
+				"licenses":     {"KEY-1 map[expiry:2027-01-01T00:00:00Z key:KEY-1 metadata:map[seats:5]] map[owner:ada@example.com policy:App/Pro] []"},
+			},
+		},
+		{
+			name: "version is optional",
+			doc:  "products:\n  - name: App\n",
+			want: map[string][]string{"products": {"App map[name:App] map[] []"}},
+		},
+		{
+			name: "policies of the same name in different products",
+			doc:  "policies:\n  - {name: Pro, product: App}\n  - {name: Pro, product: Tool}\n",
+			want: map[string][]string{"policies": {"Pro map[name:Pro] map[product:App] []", "Pro map[name:Pro] map[product:Tool] []"}},
+		},
+		{
+			name:    "unsupported version",
+			doc:     "version: 2\n",
+			wantErr: "state version 2 is not supported (want 1)",
+		},
+		{
+			name:    "unknown kind",
+			doc:     "machines: []\n",
+			wantErr: "field machines not found",
+		},
+		{
+			name:    "unknown field",
+			doc:     "products:\n  - name: App\n    colour: red\n",
+			wantErr: `products[0]: unknown field "colour" for a product`,
+		},
+		{
+			name:    "missing identity",
+			doc:     "licenses:\n  - policy: App/Pro\n",
+			wantErr: "licenses[0]: key is required",
+		},
+		{
+			name:    "policy without a product",
+			doc:     "policies:\n  - name: Pro\n",
+			wantErr: "policies[0]: product is required",
+		},
+		{
+			name:    "reference that isn't a name",
+			doc:     "licenses:\n  - {key: KEY-1, policy: 3}\n",
+			wantErr: "licenses[0].policy: must be a name, email or ID",
+		},
+		{
+			name:    "entitlements that aren't a list",
+			doc:     "licenses:\n  - {key: KEY-1, entitlements: SSO}\n",
+			wantErr: "licenses[0].entitlements: must be a list of entitlement codes",
+		},
+		{
+			name:    "entitlements on a product",
+			doc:     "products:\n  - {name: App, entitlements: [SSO]}\n",
+			wantErr: `products[0]: unknown field "entitlements" for a product`,
+		},
+		{
+			name:    "duplicate license",
+			doc:     "licenses:\n  - key: KEY-1\n  - key: KEY-1\n",
+			wantErr: `licenses[1]: duplicate license "KEY-1", also declared at licenses[0]`,
+		},
+		{
+			name:    "duplicate policy in a product",
+			doc:     "policies:\n  - {name: Pro, product: App}\n  - {name: Pro, product: App}\n",
+			wantErr: `policies[1]: duplicate policy "App/Pro", also declared at policies[0]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := Parse([]byte(tt.doc))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got := map[string][]string{}
+			for kind, list := range resources {
+				for _, r := range list {
+					got[kind] = append(got[kind], describe(r))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func describe(r Resource) string {
+	return fmt.Sprintf("%s %v %v %v", r.ID, r.Attributes, r.Refs, r.Entitlements)
+}
+
+func TestSnakeCase(t *testing.T) {
+	for _, name := range []string{"name", "maxMachines", "requireCheckIn", "heartbeatCullStrategy"} {
+		if got := camelCase(SnakeCase(name)); got != name {
+			t.Errorf("camelCase(SnakeCase(%q)) = %q", name, got)
+		}
+	}
+	if got := SnakeCase("requireFingerprintScope"); got != "require_fingerprint_scope" {
+		t.Errorf("SnakeCase = %q", got)
+	}
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Event is a decoded webhook delivery body.
+type Event struct {
+	ID       string          `json:"id"`
+	Event    string          `json:"event"`
+	Endpoint string          `json:"endpoint,omitempty"`
+	Created  string          `json:"created,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+}
+
+// DecodeEvent decodes a webhook-events resource as posted by Keygen. The
+// payload attribute is a JSON-encoded string; it is returned as raw JSON.
+func DecodeEvent(body []byte) (*Event, error) {
+	var doc struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Endpoint string `json:"endpoint"`
+				Event    string `json:"event"`
+				Payload  string `json:"payload"`
+				Created  string `json:"created"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parsing event: %w", err)
+	}
+
+	attr := doc.Data.Attributes
+	ev := &Event{
+		ID:       doc.Data.ID,
+		Event:    attr.Event,
+		Endpoint: attr.Endpoint,
+		Created:  attr.Created,
+	}
+	if attr.Payload != "" {
+		if json.Valid([]byte(attr.Payload)) {
+			ev.Payload = json.RawMessage(attr.Payload)
+		} else {
+			raw, _ := json.Marshal(attr.Payload)
+			ev.Payload = raw
+		}
+	}
+
+	return ev, nil
+}
+
+// Delivery is a captured webhook request, e.g. a local fixture.
+type Delivery struct {
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Host      string            `json:"host"`
+	Headers   map[string]string `json:"headers"`
+	Body      string            `json:"body"`
+	PublicKey string            `json:"public_key,omitempty"`
+}
+
+// LoadDelivery reads a captured delivery from a JSON file.
+func LoadDelivery(path string) (*Delivery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var d Delivery
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing delivery: %w", err)
+	}
+	if d.Method == "" {
+		d.Method = "POST"
+	}
+	return &d, nil
+}
+
+// Verify checks the captured delivery's signature.
+func (d *Delivery) Verify(publicKey ed25519.PublicKey, maxAge time.Duration) error {
+	header := http.Header{}
+	for k, v := range d.Headers {
+		header.Set(k, v)
+	}
+	return Verify(publicKey, d.Method, d.Path, d.Host, header, []byte(d.Body), maxAge)
+}
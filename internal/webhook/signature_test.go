@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const fixture = "../../testdata/webhooks/license-expiring-soon.json"
+
+func TestVerifyFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(d *Delivery)
+		wantErr string
+	}{
+		{"valid", func(d *Delivery) {}, ""},
+		{"tampered body", func(d *Delivery) {
+			d.Body = strings.Replace(d.Body, "license.expiring-soon", "license.deleted", 1)
+		}, "body digest mismatch"},
+		{"tampered body with a matching digest", func(d *Delivery) {
+			d.Body = strings.Replace(d.Body, "license.expiring-soon", "license.deleted", 1)
+			d.Headers["Digest"] = Digest([]byte(d.Body))
+		}, "invalid signature"},
+		{"other path", func(d *Delivery) { d.Path = "/other" }, "invalid signature"},
+		{"missing digest", func(d *Delivery) { delete(d.Headers, "Digest") }, "missing Digest header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := LoadDelivery(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := ParsePublicKey(d.PublicKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(d)
+
+			err = d.Verify(key, 0)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyRequiresDigestCoverage(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"data":{}}`)
+	header := http.Header{}
+	header.Set("Date", "Sun, 18 Oct 2026 12:00:00 GMT")
+	header.Set("Digest", Digest(body))
+
+	// A valid signature that leaves the body out
+	covered := []string{"(request-target)", "host", "date"}
+	msg, err := SigningString("POST", "/webhooks", "localhost", header, covered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header.Set(SignatureHeader, `algorithm="ed25519", signature="`+
+		base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(msg)))+`", headers="`+strings.Join(covered, " ")+`"`)
+
+	err = Verify(pub, "POST", "/webhooks", "localhost", header, body, 0)
+	if err == nil || !strings.Contains(err.Error(), "digest") {
+		t.Fatalf("Verify error = %v, want a digest coverage error", err)
+	}
+
+	// The same request signed by Sign is accepted
+	header.Set(SignatureHeader, "")
+	value, err := Sign(priv, "", "POST", "/webhooks", "localhost", header, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header.Set(SignatureHeader, value)
+	if err := Verify(pub, "POST", "/webhooks", "localhost", header, body, 0); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}